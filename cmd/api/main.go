@@ -1,65 +1,215 @@
 package main
 
 import (
+	"context"
+	"flag"
 	"log"
+	"log/slog"
 	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
 
+	"prototurk/internal/audit"
+	"prototurk/internal/config"
 	"prototurk/internal/database"
 	"prototurk/internal/handlers"
+	"prototurk/internal/jobs"
+	"prototurk/internal/logging"
+	"prototurk/internal/mailer"
+	"prototurk/internal/metrics"
 	"prototurk/internal/middleware"
+	"prototurk/internal/models"
+	"prototurk/internal/repository"
+	"prototurk/internal/server"
+	"prototurk/internal/service"
+	"prototurk/internal/tracing"
 
 	"github.com/gin-gonic/gin"
-	"github.com/joho/godotenv"
 )
 
 func main() {
-	if err := godotenv.Load(); err != nil {
-		log.Fatal("Error loading .env file")
+	configFile := flag.String("config", "", "YAML config file (default: CONFIG_FILE)")
+	flag.Parse()
+
+	cfg, err := config.Load(*configFile)
+	if err != nil {
+		log.Fatal("Invalid config:\n", err)
+	}
+
+	// Tüm loglar (log paketi dahil) JSON olarak stdout'a yazılır
+	logger, err := logging.New(cfg.Log, os.Stdout)
+	if err != nil {
+		fatal("Invalid log config", err)
+	}
+	slog.SetDefault(logger)
+	cfg.Database.Logger = logging.NewGormLogger(logger, cfg.Log.SlowQueryThreshold, cfg.Log.SQLParams)
+
+	// Exporter tanımlı değilse span kaydedilmez; gelen traceparent yine de korunur
+	shutdownTracing, err := tracing.Setup(cfg.Tracing)
+	if err != nil {
+		fatal("Tracing setup error", err)
 	}
 
 	// Database connection
-	dbConfig := &database.Config{
-		Host:     os.Getenv("DB_HOST"),
-		Port:     os.Getenv("DB_PORT"),
-		User:     os.Getenv("DB_USER"),
-		Password: os.Getenv("DB_PASSWORD"),
-		DBName:   os.Getenv("DB_NAME"),
+	db, err := database.NewConnection(cfg.Database)
+	if err != nil {
+		fatal("Database connection error", err)
+	}
+	if err := db.Use(tracing.NewGormPlugin()); err != nil {
+		fatal("Error registering tracing plugin", err)
+	}
+
+	// Migration'lar varsayılan olarak açılışta çalışır; ayrı bir adımda cmd/migrate ile çalıştırılacaksa kapatılabilir.
+	// Migration klasörü boşsa binary'ye gömülü dosyalar kullanılır.
+	if cfg.Migrations.OnStartup {
+		if err := database.RunMigrations(db, database.MigrationSource(cfg.Migrations.Dir), cfg.Migrations.Options); err != nil {
+			fatal("Error running migrations", err)
+		}
+	}
+
+	// İlk super admin'i oluştur veya kurulum token'ı üret
+	if err := database.BootstrapAdmin(db, &database.BootstrapConfig{
+		Email:    cfg.Bootstrap.Email,
+		Name:     cfg.Bootstrap.Name,
+		Password: cfg.Bootstrap.Password,
+	}); err != nil {
+		fatal("Error bootstrapping admin", err)
+	}
+
+	// Bilinen varsayılan parolayı kullanan aktif admin varsa production'da başlama
+	defaultPasswordAdmins, err := database.FindDefaultPasswordAdmins(db)
+	if err != nil {
+		fatal("Error checking admin passwords", err)
+	}
+	for _, admin := range defaultPasswordAdmins {
+		slog.Warn("Admin is using a known default password", "email", admin.Email)
+	}
+	if len(defaultPasswordAdmins) > 0 && cfg.IsProduction() {
+		fatal("Refusing to start in production while an admin uses a known default password", nil)
+	}
+
+	// Production veritabanı seed gibi geliştirme araçlarının çalışmaması için işaretlenir
+	if cfg.IsProduction() {
+		if err := database.MarkProduction(db); err != nil {
+			slog.Warn("Could not mark database as production", "error", err)
+		}
 	}
 
-	// Run migrations
-	if err := database.RunMigrations(dbConfig); err != nil {
-		log.Fatal("Error running migrations:", err)
+	// Periyodik arka plan işleri kapanışta durdurulur
+	workers := jobs.NewWorkers()
+
+	// Silinmiş adminleri retention süresi dolunca kalıcı olarak sil
+	if cfg.Admin.TrashRetention > 0 {
+		workers.Go(func(ctx context.Context) {
+			jobs.PurgeDeletedAdmins(ctx, db, cfg.Admin.TrashRetention, time.Hour)
+		})
 	}
 
-	db, err := database.NewConnection(dbConfig)
+	// Admin API'sine erişebilecek ağlar
+	allowedNetworks, err := middleware.ParseNetworks(strings.Join(cfg.Admin.AllowedCIDRs, ","))
 	if err != nil {
-		log.Fatal("Database connection error:", err)
+		fatal("Invalid admin allowed networks", err)
+	}
+	networkPolicy := middleware.NetworkPolicyConfig{
+		AllowedNetworks: allowedNetworks,
+		Bypass:          cfg.Admin.NetworkPolicyBypass,
 	}
 
-	// Seed default admin
-	if err := database.SeedDefaultAdmin(db); err != nil {
-		log.Fatal("Error seeding default admin:", err)
+	// Admin parola politikası
+	passwordPolicy := models.AdminPasswordPolicy{
+		MaxAge:      cfg.Admin.PasswordMaxAge,
+		HistorySize: cfg.Admin.PasswordHistorySize,
 	}
 
+	// Hassas admin işlemleri için yeniden doğrulama penceresi
+	requireReauth := middleware.RequireReauth(cfg.Admin.ReauthWindow)
+
+	// Token, 2FA secret'ı veya hesap bilgisi dönen yanıtlar cache'lenmez
+	noStore := middleware.NoStore()
+
+	// İkinci bir super admin onayı gerektiren işlemler
+	approvalActions, err := cfg.ApprovalActions()
+	if err != nil {
+		fatal("Invalid admin approval actions", err)
+	}
+	approvalPolicy := models.ApprovalPolicy{
+		Actions:             approvalActions,
+		TTL:                 cfg.Admin.ApprovalTTL,
+		BulkDeleteThreshold: cfg.Admin.BulkDeleteApprovalThreshold,
+	}
+	workers.Go(func(ctx context.Context) {
+		jobs.ExpireApprovalRequests(ctx, db, time.Minute)
+	})
+
 	// Initialize handlers
-	authHandler := handlers.NewAuthHandler(db)
-	adminHandler := handlers.NewAdminHandler(db)
+	jwtSecret := []byte(cfg.JWT.Secret)
+	userService := service.NewUserService(repository.NewGormUserRepository(db))
+	adminRepository := repository.NewGormAdminRepository(db)
+	adminService := service.NewAdminService(adminRepository, passwordPolicy)
+	approvalService := service.NewApprovalService(repository.NewGormApprovalRepository(db), approvalPolicy)
+	recorder := audit.NewRecorder(db)
+	authHandler := handlers.NewAuthHandler(userService, jwtSecret)
+	adminHandler := handlers.NewAdminHandler(adminService, approvalService, recorder, middleware.NewAdminNetworkChecker(db, networkPolicy, recorder), cfg.Admin.ReauthWindow, jwtSecret)
+	networkRuleHandler := handlers.NewAdminNetworkRuleHandler(service.NewNetworkRuleService(repository.NewGormNetworkRuleRepository(db), adminRepository, networkPolicy.AllowedNetworks, networkPolicy.Bypass), networkPolicy, recorder)
+
+	mail := mailer.New(&mailer.Config{
+		Host:     cfg.SMTP.Host,
+		Port:     cfg.SMTP.Port,
+		Username: cfg.SMTP.Username,
+		Password: cfg.SMTP.Password,
+		From:     cfg.SMTP.From,
+	})
+	invitationHandler := handlers.NewAdminInvitationHandler(repository.NewGormInvitationRepository(db), adminRepository, recorder, mail, cfg.Admin.InvitationURL, cfg.Admin.InvitationTTL)
+
+	jobManager := jobs.NewManager()
+	adminUserHandler := handlers.NewAdminUserHandler(userService, repository.NewGormUserBulkRepository(db), jobManager, approvalService, recorder, cfg.ImpersonationTTL, jwtSecret)
+	jobHandler := handlers.NewAdminJobHandler(jobManager)
+	approvalHandler := handlers.NewAdminApprovalHandler(approvalService, recorder)
+	statsHandler := handlers.NewAdminStatsHandler(service.NewStatsService(repository.NewGormStatsRepository(db), cfg.Admin.StatsCacheTTL))
 
 	// Initialize Gin router
-	router := gin.Default()
+	router := gin.New()
+	// gin.Context'i context olarak kullanan kodlar request context'indeki request ID ve trace'e erişebilsin
+	router.ContextWithFallback = true
+	router.Use(middleware.Tracing(cfg.Tracing.ServiceName), middleware.TraceResponse())
+	router.Use(middleware.RequestID(), middleware.RequestLogger(logger))
+	// Panic'lenen istekler de 500 olarak sayılsın diye Recovery'den önce eklenir
+	if cfg.Metrics.Enabled {
+		router.Use(middleware.Metrics())
+	}
+	router.Use(middleware.Recovery(logger))
+	// Preflight istekleri kimlik doğrulamaya ulaşmadan burada yanıtlanır
+	router.Use(middleware.SecurityHeaders(cfg.Security), middleware.CORS(cfg.CORS))
 
-	// JWT secret'ı global olarak ekle
-	router.Use(func(c *gin.Context) {
-		c.Set("jwt_secret", os.Getenv("JWT_SECRET"))
-		c.Set("db", db)
-		c.Next()
-	})
+	// Sadece güvenilen proxy'lerden gelen X-Forwarded-For başlıkları istemci IP'si olarak kabul edilir
+	if err := router.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		fatal("Invalid trusted proxies", err)
+	}
+
+	srv := server.New(router, cfg.Server)
+
+	// Liveness ve readiness (kimlik doğrulama gerektirmez)
+	migrator := database.NewMigrator(db, database.MigrationSource(cfg.Migrations.Dir), cfg.Migrations.Options)
+	healthHandler := handlers.NewHealthHandler(db, migrator, cfg.Server.ReadinessTimeout, srv.Draining)
+	router.GET("/healthz", healthHandler.Live)
+	router.GET("/readyz", healthHandler.Ready)
+
+	// Prometheus metrikleri (kimlik doğrulama gerektirmez, dışarıya açılmamalıdır)
+	if cfg.Metrics.Enabled {
+		if err := metrics.RegisterDatabase(db); err != nil {
+			fatal("Error registering database metrics", err)
+		}
+		router.GET("/metrics", gin.WrapH(metrics.Handler()))
+	}
 
 	// Routes
 	api := router.Group("/api")
 	{
 		// User routes
 		auth := api.Group("/auth")
+		auth.Use(noStore, middleware.JWT(db, jwtSecret))
 		{
 			auth.POST("/register", authHandler.Register)
 			auth.POST("/login", authHandler.Login)
@@ -70,28 +220,99 @@ func main() {
 
 		// Admin routes
 		admin := api.Group("/admin")
-		admin.Use(middleware.AdminJWT())
+		// Engellenen ağlardan gelen istekler token doğrulamasına ve admin sorgusuna ulaşmadan reddedilir
+		admin.Use(
+			middleware.AdminNetworkGlobalPolicy(db, networkPolicy, recorder),
+			middleware.AdminJWT(db, jwtSecret, passwordPolicy),
+			middleware.AdminNetworkPolicy(db, networkPolicy, recorder),
+		)
 		{
 			// Auth
-			admin.POST("/login", adminHandler.Login)
-			admin.GET("/me", adminHandler.Me)
+			admin.POST("/login", noStore, adminHandler.Login)
+			admin.POST("/setup", noStore, adminHandler.Setup)
+			admin.GET("/me", noStore, adminHandler.Me)
+			admin.PUT("/me/password", noStore, adminHandler.ChangePassword)
+			admin.POST("/reauth", noStore, adminHandler.Reauth)
+			admin.POST("/2fa/setup", noStore, adminHandler.SetupTwoFactor)
+			admin.POST("/2fa/confirm", noStore, adminHandler.ConfirmTwoFactor)
 
 			// CRUD
 			admin.POST("", adminHandler.Create)
 			admin.GET("", adminHandler.List)
 			admin.GET("/:id", adminHandler.Get)
 			admin.PUT("/:id", adminHandler.Update)
-			admin.DELETE("/:id", adminHandler.Delete)
+			admin.DELETE("/:id", requireReauth, adminHandler.Delete)
+			admin.POST("/:id/force-password-change", requireReauth, adminHandler.ForcePasswordChange)
+
+			// Trash
+			admin.GET("/trash", adminHandler.Trash)
+			admin.POST("/:id/restore", adminHandler.Restore)
+			admin.DELETE("/:id/purge", requireReauth, adminHandler.Purge)
+
+			// Invitations
+			admin.POST("/invitations", invitationHandler.Create)
+			admin.GET("/invitations", invitationHandler.List)
+			admin.POST("/invitations/:id/resend", invitationHandler.Resend)
+			admin.DELETE("/invitations/:id", invitationHandler.Revoke)
+			admin.POST("/invitations/accept", noStore, invitationHandler.Accept)
+
+			// Network rules
+			admin.GET("/network-rules", networkRuleHandler.List)
+			admin.POST("/network-rules", requireReauth, networkRuleHandler.Create)
+			admin.DELETE("/network-rules/:id", requireReauth, networkRuleHandler.Delete)
+
+			// Approvals
+			admin.GET("/approvals", approvalHandler.List)
+			admin.GET("/approvals/:id", approvalHandler.Get)
+			admin.POST("/approvals/:id/approve", requireReauth, approvalHandler.Approve)
+			admin.POST("/approvals/:id/reject", approvalHandler.Reject)
+			admin.POST("/approvals/:id/expire", approvalHandler.Expire)
+
+			// Stats
+			admin.GET("/stats", statsHandler.Get)
+
+			// Users
+			admin.POST("/users/:id/impersonate", noStore, adminUserHandler.Impersonate)
+			admin.POST("/users/import", adminUserHandler.Import)
+			admin.POST("/users/bulk/status", adminUserHandler.BulkStatus)
+			admin.POST("/users/bulk/delete", requireReauth, adminUserHandler.BulkDelete)
+
+			// Background jobs
+			admin.GET("/jobs/:id", jobHandler.Get)
 		}
 	}
 
 	// Start server
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
+	// SIGINT/SIGTERM sonrası /readyz başarısız döner, shutdown delay sonunda yeni istekler reddedilir ve
+	// devam edenler beklenir; ardından arka plan işleri ve veritabanı bağlantıları bu sırayla kapatılır,
+	// en son bekleyen span'ler aktarılır
+	srv.OnShutdown("background jobs", jobManager.Shutdown)
+	srv.OnShutdown("workers", workers.Shutdown)
+	srv.OnShutdown("database", func(context.Context) error {
+		return database.Close(db)
+	})
+	srv.OnShutdown("tracing", shutdownTracing)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	// İlk sinyalden sonra varsayılan davranışa dönülür; ikinci bir Ctrl+C süreci hemen sonlandırır
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	if err := srv.Run(ctx); err != nil {
+		fatal("Server error", err)
 	}
+	slog.Info("Server stopped")
+}
 
-	if err := router.Run(":" + port); err != nil {
-		log.Fatal("Server error:", err)
+// fatal hatayı loglayıp süreci sonlandırır
+func fatal(msg string, err error) {
+	if err != nil {
+		slog.Error(msg, "error", err)
+	} else {
+		slog.Error(msg)
 	}
+	os.Exit(1)
 }
@@ -0,0 +1,235 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"prototurk/internal/config"
+	"prototurk/internal/database"
+)
+
+const usage = `Usage: migrate [-config file] [-dir path] <command> [args]
+
+Ayarlar uygulama ile aynı kaynaklardan (.env, -config veya CONFIG_FILE, ortam değişkenleri) okunur.
+-dir verilmezse ayarlardaki migration klasörü, o da boşsa binary'ye gömülü migration'lar kullanılır
+(create ve embed-check için varsayılan: migrations).
+
+Commands:
+  up [N]            Bekleyen migration'ları çalıştırır (N verilirse en fazla N tane)
+  down [N]          Son N migration'ı geri alır (varsayılan 1)
+  status            Migration'ların durumunu listeler
+  create <name>     Yeni bir up/down dosya çifti oluşturur
+  redo              Son migration'ı geri alıp tekrar çalıştırır
+  force <version>   SQL çalıştırmadan migration kayıtlarını verilen versiyona getirir
+  verify            Eksik, değiştirilmiş ve bilinmeyen migration'ları listeler
+  schema check      GORM modellerini veritabanı şemasıyla (kolon, tip, index) karşılaştırır
+  embed-check       Gömülü migration'ların diskteki klasörle aynı olduğunu doğrular
+`
+
+func main() {
+	configFile := flag.String("config", "", "YAML config file (default: CONFIG_FILE)")
+	dir := flag.String("dir", "", "migration directory (default: migrations.dir, then embedded migrations)")
+	flag.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	flag.Parse()
+
+	args := flag.Args()
+	if len(args) == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	// Dosya oluşturma ve karşılaştırma her zaman diskteki klasörle çalışır
+	diskDir := *dir
+	if diskDir == "" {
+		diskDir = database.MigrationsDir
+	}
+
+	// create ve embed-check komutları ayar ve veritabanı bağlantısı gerektirmez
+	switch args[0] {
+	case "create":
+		if len(args) < 2 {
+			log.Fatal("create requires a migration name")
+		}
+		upFile, downFile, err := database.CreateMigration(diskDir, args[1])
+		if err != nil {
+			log.Fatal("Error creating migration: ", err)
+		}
+		fmt.Printf("Created %s\nCreated %s\n", upFile, downFile)
+		return
+
+	case "embed-check":
+		diffs, err := database.CompareSources(os.DirFS(diskDir), database.MigrationSource(""))
+		if err != nil {
+			log.Fatal("Error comparing migrations: ", err)
+		}
+		if len(diffs) > 0 {
+			for _, diff := range diffs {
+				fmt.Println(diff)
+			}
+			log.Fatalf("Embedded migrations differ from %s, rebuild the binary", diskDir)
+		}
+		fmt.Printf("Embedded migrations match %s\n", diskDir)
+		return
+	}
+
+	cfg, err := config.Load(*configFile)
+	if err != nil {
+		log.Fatal("Invalid config:\n", err)
+	}
+	if *dir == "" {
+		*dir = cfg.Migrations.Dir
+	}
+
+	db, err := database.NewConnection(cfg.Database)
+	if err != nil {
+		log.Fatal("Database connection error: ", err)
+	}
+	migrator := database.NewMigrator(db, database.MigrationSource(*dir), cfg.Migrations.Options)
+
+	switch args[0] {
+	case "up":
+		count, err := migrator.Up(optionalCount(args))
+		if err != nil {
+			log.Fatalf("Error running migrations (%d applied): %v", count, err)
+		}
+		fmt.Printf("Applied %d migration(s)\n", count)
+
+	case "down":
+		count, err := migrator.Down(optionalCount(args))
+		if err != nil {
+			log.Fatalf("Error reverting migrations (%d reverted): %v", count, err)
+		}
+		fmt.Printf("Reverted %d migration(s)\n", count)
+
+	case "status":
+		statuses, err := migrator.Status()
+		if err != nil {
+			log.Fatal("Error reading migration status: ", err)
+		}
+		printStatus(statuses)
+
+	case "verify":
+		report, err := migrator.Verify()
+		if err != nil {
+			log.Fatal("Error verifying migrations: ", err)
+		}
+		printReport(report)
+		if len(report.Modified) > 0 || len(report.Unknown) > 0 {
+			os.Exit(1)
+		}
+
+	case "schema":
+		if len(args) < 2 || args[1] != "check" {
+			log.Fatal("usage: migrate schema check")
+		}
+		issues, err := database.CheckSchema(db)
+		if err != nil {
+			log.Fatal("Error checking schema: ", err)
+		}
+		printSchemaIssues(issues)
+		if database.HasSchemaErrors(issues) {
+			os.Exit(1)
+		}
+
+	case "redo":
+		name, err := migrator.Redo()
+		if err != nil {
+			log.Fatal("Error redoing migration: ", err)
+		}
+		fmt.Printf("Redone %s\n", name)
+
+	case "force":
+		if len(args) < 2 {
+			log.Fatal("force requires a version")
+		}
+		version, err := strconv.Atoi(args[1])
+		if err != nil {
+			log.Fatalf("Invalid version %q", args[1])
+		}
+		if err := migrator.Force(version); err != nil {
+			log.Fatal("Error forcing version: ", err)
+		}
+		fmt.Printf("Forced version %d\n", version)
+
+	default:
+		flag.Usage()
+		os.Exit(2)
+	}
+}
+
+// optionalCount komutun ikinci argümanını sayı olarak okur, yoksa 0 döner
+func optionalCount(args []string) int {
+	if len(args) < 2 {
+		return 0
+	}
+	n, err := strconv.Atoi(args[1])
+	if err != nil || n < 0 {
+		log.Fatalf("Invalid count %q", args[1])
+	}
+	return n
+}
+
+func printStatus(statuses []database.MigrationStatus) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tEXECUTED AT\tDOWN")
+	for _, status := range statuses {
+		state, executedAt := "pending", "-"
+		if status.Applied {
+			state = "applied"
+			executedAt = status.ExecutedAt.Format("2006-01-02 15:04:05")
+		}
+		if status.Modified {
+			state = "modified"
+		}
+		down := "no"
+		if status.HasDown() {
+			down = "yes"
+		}
+		fmt.Fprintf(w, "%03d\t%s\t%s\t%s\t%s\n", status.Version, status.Name, state, executedAt, down)
+	}
+	w.Flush()
+}
+
+func printReport(report database.MigrationReport) {
+	sections := []struct {
+		title string
+		names []string
+	}{
+		{"Missing (not applied)", report.Missing},
+		{"Modified after apply", report.Modified},
+		{"Unknown (applied, no file)", report.Unknown},
+		{"Unverified (no checksum recorded)", report.Unverified},
+	}
+
+	for _, section := range sections {
+		fmt.Printf("%s: %d\n", section.title, len(section.names))
+		for _, name := range section.names {
+			fmt.Printf("  %s\n", name)
+		}
+	}
+	if report.Clean() {
+		fmt.Println("Migrations are in sync")
+	}
+}
+
+func printSchemaIssues(issues []database.SchemaIssue) {
+	if len(issues) == 0 {
+		fmt.Println("Schema matches models")
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TABLE\tCOLUMN\tISSUE\tDETAILS")
+	for _, issue := range issues {
+		column := issue.Column
+		if column == "" {
+			column = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", issue.Table, column, issue.Kind, issue.Message)
+	}
+	w.Flush()
+}
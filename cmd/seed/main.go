@@ -0,0 +1,77 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"prototurk/internal/config"
+	"prototurk/internal/database"
+	"prototurk/internal/seed"
+)
+
+func main() {
+	configFile := flag.String("config", "", "YAML config file (default: CONFIG_FILE)")
+	profileName := flag.String("profile", "minimal", "data profile: "+strings.Join(seed.ProfileNames(), ", "))
+	seedValue := flag.Int64("seed", 1, "random seed; the same seed always produces the same data")
+	reference := flag.String("reference", "", "reference date (YYYY-MM-DD) that timestamps are generated relative to (default: today, UTC)")
+	adminPassword := flag.String("admin-password", "", "password of the seeded admins (default: seed.admin_password or SEED_ADMIN_PASSWORD)")
+	userPassword := flag.String("user-password", "", "password of the seeded users (default: seed.user_password or SEED_USER_PASSWORD)")
+	flag.Parse()
+
+	profile, ok := seed.Profiles[*profileName]
+	if !ok {
+		log.Fatalf("Unknown profile %q (available: %s)", *profileName, strings.Join(seed.ProfileNames(), ", "))
+	}
+
+	// Ayarlar uygulama ile aynı kaynaklardan okunur; böylece seed aynı veritabanına bağlanır
+	cfg, err := config.Load(*configFile)
+	if err != nil {
+		log.Fatal("Invalid config:\n", err)
+	}
+	if cfg.IsProduction() {
+		log.Fatal("Refusing to seed in production environment")
+	}
+	if *adminPassword == "" {
+		*adminPassword = cfg.Seed.AdminPassword
+	}
+	if *userPassword == "" {
+		*userPassword = cfg.Seed.UserPassword
+	}
+
+	referenceTime := time.Now().UTC().Truncate(24 * time.Hour)
+	if *reference != "" {
+		parsed, err := time.Parse("2006-01-02", *reference)
+		if err != nil {
+			log.Fatalf("Invalid reference date %q", *reference)
+		}
+		referenceTime = parsed
+	}
+
+	db, err := database.NewConnection(cfg.Database)
+	if err != nil {
+		log.Fatal("Database connection error: ", err)
+	}
+
+	started := time.Now()
+	report, err := seed.Run(db, seed.Options{
+		Profile:       profile,
+		Seed:          *seedValue,
+		Reference:     referenceTime,
+		AdminPassword: *adminPassword,
+		UserPassword:  *userPassword,
+	})
+	if errors.Is(err, seed.ErrProductionDatabase) {
+		log.Fatal("Database is marked as production, refusing to seed")
+	}
+	if err != nil {
+		log.Fatal("Error seeding database: ", err)
+	}
+
+	fmt.Printf("Profile %s (seed %d, reference %s) finished in %s\n", profile.Name, *seedValue, referenceTime.Format("2006-01-02"), time.Since(started).Round(time.Millisecond))
+	fmt.Printf("Admins: %d created, %d already existed\n", report.AdminsCreated, report.AdminsSkipped)
+	fmt.Printf("Users:  %d created, %d already existed\n", report.UsersCreated, report.UsersSkipped)
+}
@@ -0,0 +1,98 @@
+package audit
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+
+	"prototurk/internal/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// Recorder handler'ların audit kaydı oluşturmak için kullandığı arayüzdür
+type Recorder interface {
+	// Record istek bağlamındaki admin adına bir audit kaydı oluşturur
+	Record(c *gin.Context, action string, targetType string, targetID uint, details map[string]interface{})
+	// RecordForAdmin context'te admin olmayan isteklerde işlemi verilen admin adına kaydeder
+	RecordForAdmin(c *gin.Context, adminID uint, action string, targetType string, targetID uint, details map[string]interface{})
+	// RecordSystem istek dışında çalışan işlemler (arka plan işleri gibi) için admin'e bağlı olmayan bir kayıt oluşturur
+	RecordSystem(ctx context.Context, action string, targetType string, targetID uint, details map[string]interface{})
+}
+
+type gormRecorder struct {
+	db *gorm.DB
+}
+
+// NewRecorder kayıtları verilen veritabanına yazan bir Recorder oluşturur
+func NewRecorder(db *gorm.DB) Recorder {
+	return &gormRecorder{db: db}
+}
+
+func (r *gormRecorder) Record(c *gin.Context, action string, targetType string, targetID uint, details map[string]interface{}) {
+	Record(c, r.db, action, targetType, targetID, details)
+}
+
+func (r *gormRecorder) RecordForAdmin(c *gin.Context, adminID uint, action string, targetType string, targetID uint, details map[string]interface{}) {
+	RecordForAdmin(c, r.db, adminID, action, targetType, targetID, details)
+}
+
+func (r *gormRecorder) RecordSystem(ctx context.Context, action string, targetType string, targetID uint, details map[string]interface{}) {
+	RecordSystem(r.db.WithContext(ctx), action, targetType, targetID, details)
+}
+
+// Record istek bağlamındaki admin adına bir audit kaydı oluşturur.
+// Audit kaydının yazılamaması isteği başarısız yapmaz, sadece loglanır.
+func Record(c *gin.Context, db *gorm.DB, action string, targetType string, targetID uint, details map[string]interface{}) {
+	entry := newEntry(action, targetType, targetID, details)
+	if adminID, exists := c.Get("admin_id"); exists {
+		id := adminID.(uint)
+		entry.AdminID = &id
+	}
+	entry.IPAddress = c.ClientIP()
+
+	save(requestDB(c, db), entry)
+}
+
+// RecordForAdmin context'te admin olmayan isteklerde (örneğin impersonation) işlemi verilen admin adına kaydeder
+func RecordForAdmin(c *gin.Context, db *gorm.DB, adminID uint, action string, targetType string, targetID uint, details map[string]interface{}) {
+	entry := newEntry(action, targetType, targetID, details)
+	entry.AdminID = &adminID
+	entry.IPAddress = c.ClientIP()
+
+	save(requestDB(c, db), entry)
+}
+
+// requestDB kaydı isteğin request_id'si ile loglar; istemci bağlantıyı kapatsa bile kayıt iptal edilmez
+func requestDB(c *gin.Context, db *gorm.DB) *gorm.DB {
+	return db.WithContext(context.WithoutCancel(c.Request.Context()))
+}
+
+// RecordSystem bir admin'e bağlı olmayan (arka plan işleri gibi) işlemler için audit kaydı oluşturur
+func RecordSystem(db *gorm.DB, action string, targetType string, targetID uint, details map[string]interface{}) {
+	save(db, newEntry(action, targetType, targetID, details))
+}
+
+func newEntry(action string, targetType string, targetID uint, details map[string]interface{}) *models.AuditLog {
+	entry := &models.AuditLog{
+		Action:     action,
+		TargetType: targetType,
+	}
+	if targetID != 0 {
+		entry.TargetID = &targetID
+	}
+	if len(details) > 0 {
+		if encoded, err := json.Marshal(details); err == nil {
+			s := string(encoded)
+			entry.Details = &s
+		}
+	}
+	return entry
+}
+
+func save(db *gorm.DB, entry *models.AuditLog) {
+	if err := db.Create(entry).Error; err != nil {
+		slog.ErrorContext(db.Statement.Context, "Error recording audit log", "action", entry.Action, "error", err)
+	}
+}
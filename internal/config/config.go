@@ -0,0 +1,294 @@
+// Package config uygulama ayarlarını ortam değişkenleri, opsiyonel .env dosyası ve opsiyonel YAML dosyasından
+// tipli bir yapıya okur ve açılışta doğrular
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"time"
+
+	"prototurk/internal/database"
+	"prototurk/internal/logging"
+	"prototurk/internal/middleware"
+	"prototurk/internal/models"
+	"prototurk/internal/tracing"
+
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+// MinJWTSecretLength HS256 için önerilen en kısa secret uzunluğudur (256 bit)
+const MinJWTSecretLength = 32
+
+const EnvironmentProduction = database.EnvironmentProduction
+
+type Config struct {
+	// Env (APP_ENV) development, staging, production gibi ortam adıdır
+	Env        string                           `yaml:"env"`
+	Server     ServerConfig                     `yaml:"server"`
+	Log        logging.Config                   `yaml:"log"`
+	Metrics    MetricsConfig                    `yaml:"metrics"`
+	Tracing    tracing.Config                   `yaml:"tracing"`
+	CORS       middleware.CORSConfig            `yaml:"cors"`
+	Security   middleware.SecurityHeadersConfig `yaml:"security_headers"`
+	JWT        JWTConfig                        `yaml:"jwt"`
+	Database   *database.Config                 `yaml:"database"`
+	Migrations MigrationConfig                  `yaml:"migrations"`
+	Bootstrap  BootstrapConfig                  `yaml:"bootstrap"`
+	Admin      AdminConfig                      `yaml:"admin"`
+	SMTP       SMTPConfig                       `yaml:"smtp"`
+	Seed       SeedConfig                       `yaml:"seed"`
+	// ImpersonationTTL destek ekibinin kullanıcı adına aldığı token'ların süresidir
+	ImpersonationTTL time.Duration `yaml:"impersonation_ttl"`
+}
+
+type ServerConfig struct {
+	Port int `yaml:"port"`
+	// TrustedProxies X-Forwarded-For başlığı kabul edilen proxy'lerdir (CIDR veya IP)
+	TrustedProxies []string `yaml:"trusted_proxies"`
+
+	// ReadHeaderTimeout başlıkları yavaş gönderen (slowloris) bağlantıları keser
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout"`
+	ReadTimeout       time.Duration `yaml:"read_timeout"`
+	WriteTimeout      time.Duration `yaml:"write_timeout"`
+	IdleTimeout       time.Duration `yaml:"idle_timeout"`
+	MaxHeaderBytes    int           `yaml:"max_header_bytes"`
+	// ShutdownDelay SIGINT/SIGTERM sonrası /readyz başarısız dönerken isteklerin kabul edilmeye devam edildiği süredir;
+	// load balancer'ın instance'ı trafikten çıkarması için zaman tanır
+	ShutdownDelay time.Duration `yaml:"shutdown_delay"`
+	// ReadinessTimeout /readyz bağımlılık kontrollerinin toplam süre sınırıdır
+	ReadinessTimeout time.Duration `yaml:"readiness_timeout"`
+	// ShutdownTimeout SIGINT/SIGTERM sonrası devam eden isteklerin ve arka plan işlerinin bitmesi için beklenecek en uzun süredir
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+}
+
+type MetricsConfig struct {
+	// Enabled true ise /metrics Prometheus formatında sunulur. Endpoint kimlik doğrulama gerektirmez;
+	// sadece iç ağdan erişilebilir olmalıdır.
+	Enabled bool `yaml:"enabled"`
+}
+
+type JWTConfig struct {
+	Secret string `yaml:"secret"`
+}
+
+type MigrationConfig struct {
+	// OnStartup false ise migration'lar ayrı bir adımda cmd/migrate ile çalıştırılır
+	OnStartup bool `yaml:"on_startup"`
+	// Dir boşsa binary'ye gömülü migration'lar kullanılır
+	Dir     string                   `yaml:"dir"`
+	Options database.MigratorOptions `yaml:",inline"`
+}
+
+type BootstrapConfig struct {
+	Email    string `yaml:"email"`
+	Name     string `yaml:"name"`
+	Password string `yaml:"password"`
+}
+
+type AdminConfig struct {
+	// TrashRetention sıfırdan büyükse silinmiş adminler bu süre sonunda kalıcı olarak silinir
+	TrashRetention      time.Duration `yaml:"trash_retention"`
+	AllowedCIDRs        []string      `yaml:"allowed_cidrs"`
+	NetworkPolicyBypass bool          `yaml:"network_policy_bypass"`
+	// PasswordMaxAge sıfırsa parolaların süresi dolmaz
+	PasswordMaxAge      time.Duration `yaml:"password_max_age"`
+	PasswordHistorySize int           `yaml:"password_history_size"`
+	ReauthWindow        time.Duration `yaml:"reauth_window"`
+	ApprovalActions     []string      `yaml:"approval_actions"`
+	ApprovalTTL         time.Duration `yaml:"approval_ttl"`
+	// BulkDeleteApprovalThreshold sıfırdan büyükse bu sayıdan fazla kullanıcı silen toplu silmeler onay gerektirir
+	BulkDeleteApprovalThreshold int           `yaml:"bulk_delete_approval_threshold"`
+	InvitationURL               string        `yaml:"invitation_url"`
+	InvitationTTL               time.Duration `yaml:"invitation_ttl"`
+	StatsCacheTTL               time.Duration `yaml:"stats_cache_ttl"`
+}
+
+type SMTPConfig struct {
+	Host     string `yaml:"host"`
+	Port     string `yaml:"port"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+	From     string `yaml:"from"`
+}
+
+// SeedConfig cmd/seed ile oluşturulan test verisinin ayarlarıdır; uygulama tarafından kullanılmaz
+type SeedConfig struct {
+	AdminPassword string `yaml:"admin_password"`
+	UserPassword  string `yaml:"user_password"`
+}
+
+// Default ortam değişkeni veya YAML ile değiştirilmeyen ayarların varsayılanlarını döner
+func Default() *Config {
+	return &Config{
+		Env: "development",
+		Server: ServerConfig{
+			Port:              8080,
+			ReadHeaderTimeout: 5 * time.Second,
+			ReadTimeout:       15 * time.Second,
+			WriteTimeout:      30 * time.Second,
+			IdleTimeout:       60 * time.Second,
+			MaxHeaderBytes:    1 << 20,
+			ShutdownDelay:     5 * time.Second,
+			ReadinessTimeout:  2 * time.Second,
+			ShutdownTimeout:   30 * time.Second,
+		},
+		Log:        logging.Config{Level: "info", Format: logging.FormatJSON, SlowQueryThreshold: 200 * time.Millisecond},
+		Metrics:    MetricsConfig{Enabled: true},
+		Tracing:    tracing.Config{Exporter: tracing.ExporterNone, ServiceName: "prototurk-api", SampleRatio: 1},
+		CORS:       middleware.DefaultCORSConfig(),
+		Security:   middleware.DefaultSecurityHeadersConfig(),
+		Database:   database.DefaultConfig(),
+		Migrations: MigrationConfig{OnStartup: true, Options: database.DefaultMigratorOptions()},
+		Admin: AdminConfig{
+			PasswordHistorySize:         5,
+			ReauthWindow:                5 * time.Minute,
+			ApprovalTTL:                 24 * time.Hour,
+			BulkDeleteApprovalThreshold: 100,
+			InvitationTTL:               72 * time.Hour,
+			StatsCacheTTL:               time.Minute,
+		},
+		SMTP:             SMTPConfig{Port: "587"},
+		Seed:             SeedConfig{AdminPassword: "seed-password", UserPassword: "seed-password"},
+		ImpersonationTTL: 15 * time.Minute,
+	}
+}
+
+// Load ayarları şu sırayla okur; sonraki kaynak öncekinin üzerine yazar:
+// varsayılanlar, YAML dosyası (path veya CONFIG_FILE), ortam değişkenleri.
+// .env dosyası yoksa hata verilmez ve zaten tanımlı ortam değişkenlerinin üzerine yazmaz.
+// Sonuç doğrulanır; geçersiz ayarlarla uygulama açılmamalıdır.
+func Load(path string) (*Config, error) {
+	if err := godotenv.Load(); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("error loading .env file: %v", err)
+	}
+
+	config := Default()
+
+	if path == "" {
+		path = os.Getenv("CONFIG_FILE")
+	}
+	if path != "" {
+		if err := config.loadFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := config.applyEnv(); err != nil {
+		return nil, err
+	}
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// loadFile YAML dosyasını okur. Bilinmeyen anahtarlar yazım hatası kabul edilip reddedilir.
+func (c *Config) loadFile(path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("error opening config file: %v", err)
+	}
+	defer file.Close()
+
+	decoder := yaml.NewDecoder(file)
+	decoder.KnownFields(true)
+	if err := decoder.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("error parsing config file %s: %v", path, err)
+	}
+
+	// Dosyada "database:" boş bırakılırsa varsayılanlar kaybolmasın
+	if c.Database == nil {
+		c.Database = database.DefaultConfig()
+	}
+	if policy, err := database.ParseDriftPolicy(string(c.Migrations.Options.DriftPolicy)); err == nil {
+		c.Migrations.Options.DriftPolicy = policy
+	}
+	return nil
+}
+
+// IsProduction uygulamanın production ortamında çalışıp çalışmadığını döner
+func (c *Config) IsProduction() bool {
+	return c.Env == EnvironmentProduction
+}
+
+// Validate tüm hataları tek seferde raporlamak için bulduğu sorunları birleştirerek döner
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(c.Env != "", "env must not be empty")
+	check(c.Server.Port >= 1 && c.Server.Port <= 65535, "server port %d is out of range 1-65535", c.Server.Port)
+	check(c.Server.ReadHeaderTimeout > 0 && c.Server.ReadTimeout > 0 && c.Server.WriteTimeout > 0 && c.Server.IdleTimeout > 0,
+		"server read, read header, write and idle timeouts must be positive")
+	check(c.Server.MaxHeaderBytes >= 1024, "server max header bytes must be at least 1024")
+	check(c.Server.ShutdownTimeout > 0, "server shutdown timeout must be positive")
+	check(c.Server.ShutdownDelay >= 0, "server shutdown delay must not be negative")
+	check(c.Server.ReadinessTimeout > 0, "server readiness timeout must be positive")
+	check(c.JWT.Secret != "", "jwt secret (JWT_SECRET) is required")
+	check(c.JWT.Secret == "" || len(c.JWT.Secret) >= MinJWTSecretLength,
+		"jwt secret must be at least %d bytes, got %d", MinJWTSecretLength, len(c.JWT.Secret))
+
+	if err := c.Log.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := c.Tracing.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := c.CORS.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := c.Security.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if err := c.Database.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if _, err := database.ParseDriftPolicy(string(c.Migrations.Options.DriftPolicy)); err != nil {
+		errs = append(errs, err)
+	}
+	check(c.Migrations.Options.LockTimeout > 0, "migration lock timeout must be positive")
+
+	for _, cidr := range c.Admin.AllowedCIDRs {
+		if _, err := models.ParseCIDR(cidr); err != nil {
+			errs = append(errs, fmt.Errorf("invalid admin allowed network %q: %v", cidr, err))
+		}
+	}
+	if _, err := c.ApprovalActions(); err != nil {
+		errs = append(errs, err)
+	}
+	check(c.Admin.TrashRetention >= 0, "admin trash retention must not be negative")
+	check(c.Admin.PasswordMaxAge >= 0, "admin password max age must not be negative")
+	check(c.Admin.PasswordHistorySize >= 0, "admin password history size must not be negative")
+	check(c.Admin.ReauthWindow > 0, "admin reauth window must be positive")
+	check(c.Admin.ApprovalTTL > 0, "admin approval ttl must be positive")
+	check(c.Admin.BulkDeleteApprovalThreshold >= 0, "admin bulk delete approval threshold must not be negative")
+	check(c.Admin.InvitationTTL > 0, "admin invitation ttl must be positive")
+	check(c.Admin.StatsCacheTTL > 0, "admin stats cache ttl must be positive")
+	check(c.ImpersonationTTL > 0, "impersonation ttl must be positive")
+	check(c.SMTP.Host == "" || c.SMTP.From != "", "smtp from address is required when smtp host is set")
+
+	return errors.Join(errs...)
+}
+
+// ApprovalActions ikinci bir super admin onayı gerektiren işlemleri döner
+func (c *Config) ApprovalActions() (map[models.ApprovalAction]bool, error) {
+	actions := make(map[models.ApprovalAction]bool)
+	for _, value := range c.Admin.ApprovalActions {
+		parsed, err := models.ParseApprovalActions(value)
+		if err != nil {
+			return nil, err
+		}
+		for action := range parsed {
+			actions[action] = true
+		}
+	}
+	return actions, nil
+}
@@ -0,0 +1,246 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"prototurk/internal/database"
+)
+
+const testSecret = "0123456789abcdef0123456789abcdef"
+
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("write config file: %v", err)
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		env     map[string]string
+		wantErr string
+		check   func(t *testing.T, cfg *Config)
+	}{
+		{
+			name: "defaults",
+			env:  map[string]string{"JWT_SECRET": testSecret},
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.Server.Port != 8080 || cfg.Database.MaxOpenConns != 25 || cfg.Migrations.Options.LockTimeout != time.Minute {
+					t.Fatalf("defaults not applied: %+v", cfg)
+				}
+			},
+		},
+		{
+			name: "yaml",
+			yaml: "server:\n  port: 9090\njwt:\n  secret: " + testSecret + "\ndatabase:\n  host: yaml-db\n  max_open_conns: 10\nmigrations:\n  drift: WARN\nseed:\n  user_password: from-yaml\n",
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.Server.Port != 9090 || cfg.JWT.Secret != testSecret {
+					t.Fatalf("server/jwt = %+v %+v", cfg.Server, cfg.JWT)
+				}
+				if cfg.Database.Host != "yaml-db" || cfg.Database.MaxOpenConns != 10 || cfg.Database.MaxIdleConns != 5 {
+					t.Fatalf("database = %+v, want yaml values merged with defaults", cfg.Database)
+				}
+				if cfg.Migrations.Options.DriftPolicy != database.DriftWarn {
+					t.Fatalf("drift policy = %q, want %q", cfg.Migrations.Options.DriftPolicy, database.DriftWarn)
+				}
+				if cfg.Seed.UserPassword != "from-yaml" || cfg.Seed.AdminPassword != "seed-password" {
+					t.Fatalf("seed = %+v", cfg.Seed)
+				}
+			},
+		},
+		{
+			name: "empty database section keeps defaults",
+			yaml: "database:\njwt:\n  secret: " + testSecret + "\n",
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.Database == nil || cfg.Database.MaxOpenConns != 25 {
+					t.Fatalf("database = %+v, want defaults", cfg.Database)
+				}
+			},
+		},
+		{
+			name: "env overrides yaml",
+			yaml: "server:\n  port: 9090\ndatabase:\n  host: yaml-db\n",
+			env: map[string]string{
+				"JWT_SECRET":                        testSecret,
+				"PORT":                              "7070",
+				"DB_HOST":                           "env-db",
+				"DATABASE_REPLICA_URLS":             "postgres://a, ,postgres://b",
+				"DB_STATEMENT_TIMEOUT_MS":           "1500",
+				"DB_MIGRATION_DRIFT":                "warn",
+				"DB_MIGRATION_LOCK_TIMEOUT_SECONDS": "5",
+				"ADMIN_ALLOWED_CIDRS":               "10.0.0.0/8, 192.168.1.1",
+				"SEED_ADMIN_PASSWORD":               "from-env",
+			},
+			check: func(t *testing.T, cfg *Config) {
+				if cfg.Server.Port != 7070 || cfg.Database.Host != "env-db" {
+					t.Fatalf("port = %d, host = %q; want env values", cfg.Server.Port, cfg.Database.Host)
+				}
+				if len(cfg.Database.ReplicaURLs) != 2 || cfg.Database.ReplicaURLs[1] != "postgres://b" {
+					t.Fatalf("replica urls = %q", cfg.Database.ReplicaURLs)
+				}
+				if cfg.Database.StatementTimeout != 1500*time.Millisecond {
+					t.Fatalf("statement timeout = %s", cfg.Database.StatementTimeout)
+				}
+				if cfg.Migrations.Options.DriftPolicy != database.DriftWarn || cfg.Migrations.Options.LockTimeout != 5*time.Second {
+					t.Fatalf("migration options = %+v", cfg.Migrations.Options)
+				}
+				if len(cfg.Admin.AllowedCIDRs) != 2 || cfg.Admin.AllowedCIDRs[1] != "192.168.1.1" {
+					t.Fatalf("allowed cidrs = %q", cfg.Admin.AllowedCIDRs)
+				}
+				if cfg.Seed.AdminPassword != "from-env" {
+					t.Fatalf("seed admin password = %q", cfg.Seed.AdminPassword)
+				}
+			},
+		},
+		{
+			name:    "unknown yaml field",
+			yaml:    "server:\n  prot: 9090\n",
+			env:     map[string]string{"JWT_SECRET": testSecret},
+			wantErr: "field prot not found",
+		},
+		{
+			name:    "invalid env number",
+			env:     map[string]string{"JWT_SECRET": testSecret, "PORT": "http"},
+			wantErr: `invalid PORT "http"`,
+		},
+		{
+			name:    "negative env duration",
+			env:     map[string]string{"JWT_SECRET": testSecret, "DB_CONN_MAX_LIFETIME_MINUTES": "-1"},
+			wantErr: "invalid DB_CONN_MAX_LIFETIME_MINUTES",
+		},
+		{
+			name:    "invalid env drift policy",
+			env:     map[string]string{"JWT_SECRET": testSecret, "DB_MIGRATION_DRIFT": "ignore"},
+			wantErr: "unknown migration drift policy",
+		},
+		{
+			name:    "validation runs after env",
+			yaml:    "jwt:\n  secret: " + testSecret + "\n",
+			env:     map[string]string{"JWT_SECRET": "short"},
+			wantErr: "jwt secret must be at least 32 bytes",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// Çalışan ortamdaki değişkenler sonucu etkilemesin
+			for _, name := range []string{"CONFIG_FILE", "JWT_SECRET", "PORT", "DB_HOST", "DATABASE_URL", "DB_MIGRATION_DRIFT", "SEED_ADMIN_PASSWORD", "SEED_USER_PASSWORD"} {
+				t.Setenv(name, "")
+			}
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+
+			path := ""
+			if tt.yaml != "" {
+				path = writeConfigFile(t, tt.yaml)
+			}
+
+			cfg, err := Load(path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("Load error = %v, want containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			tt.check(t, cfg)
+		})
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	t.Setenv("JWT_SECRET", testSecret)
+	if _, err := Load(filepath.Join(t.TempDir(), "missing.yaml")); err == nil || !strings.Contains(err.Error(), "error opening config file") {
+		t.Fatalf("Load error = %v, want error opening config file", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(cfg *Config)
+		want   []string
+	}{
+		{
+			name:   "valid",
+			modify: func(cfg *Config) {},
+		},
+		{
+			name: "all errors are joined",
+			modify: func(cfg *Config) {
+				cfg.JWT.Secret = ""
+				cfg.Server.Port = 0
+				cfg.Database.MaxIdleConns = 50
+				cfg.Migrations.Options.LockTimeout = 0
+				cfg.Admin.AllowedCIDRs = []string{"not-a-network"}
+				cfg.SMTP.Host = "smtp.example.com"
+				cfg.SMTP.From = ""
+			},
+			want: []string{
+				"jwt secret (JWT_SECRET) is required",
+				"server port 0 is out of range 1-65535",
+				"max idle connections (50) must not exceed max open connections (25)",
+				"migration lock timeout must be positive",
+				`invalid admin allowed network "not-a-network"`,
+				"smtp from address is required when smtp host is set",
+			},
+		},
+		{
+			name: "negative durations",
+			modify: func(cfg *Config) {
+				cfg.Server.ShutdownDelay = -time.Second
+				cfg.Admin.TrashRetention = -time.Hour
+			},
+			want: []string{
+				"server shutdown delay must not be negative",
+				"admin trash retention must not be negative",
+			},
+		},
+		{
+			name: "invalid drift policy and approval action",
+			modify: func(cfg *Config) {
+				cfg.Migrations.Options.DriftPolicy = "ignore"
+				cfg.Admin.ApprovalActions = []string{"admin.fly"}
+			},
+			want: []string{"unknown migration drift policy", "admin.fly"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default()
+			cfg.JWT.Secret = testSecret
+			tt.modify(cfg)
+
+			err := cfg.Validate()
+			if len(tt.want) == 0 {
+				if err != nil {
+					t.Fatalf("Validate: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("Validate = nil, want errors")
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Validate error %q does not contain %q", err, want)
+				}
+			}
+			if lines := strings.Count(err.Error(), "\n") + 1; lines != len(tt.want) {
+				t.Errorf("Validate reported %d errors, want %d:\n%v", lines, len(tt.want), err)
+			}
+		})
+	}
+}
@@ -0,0 +1,189 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"prototurk/internal/database"
+)
+
+// applyEnv tanımlı (boş olmayan) ortam değişkenlerini varsayılanların ve YAML dosyasının üzerine yazar.
+// Değişken adları .env.example ile aynıdır; süreler dakika, saat veya gün cinsinden tam sayı olarak verilir.
+func (c *Config) applyEnv() error {
+	env := &envReader{}
+
+	env.string("APP_ENV", &c.Env)
+	env.int("PORT", &c.Server.Port)
+	env.list("TRUSTED_PROXIES", &c.Server.TrustedProxies)
+	env.duration("SERVER_READ_HEADER_TIMEOUT_SECONDS", time.Second, &c.Server.ReadHeaderTimeout)
+	env.duration("SERVER_READ_TIMEOUT_SECONDS", time.Second, &c.Server.ReadTimeout)
+	env.duration("SERVER_WRITE_TIMEOUT_SECONDS", time.Second, &c.Server.WriteTimeout)
+	env.duration("SERVER_IDLE_TIMEOUT_SECONDS", time.Second, &c.Server.IdleTimeout)
+	env.int("SERVER_MAX_HEADER_BYTES", &c.Server.MaxHeaderBytes)
+	env.duration("SERVER_SHUTDOWN_DELAY_SECONDS", time.Second, &c.Server.ShutdownDelay)
+	env.duration("SERVER_SHUTDOWN_TIMEOUT_SECONDS", time.Second, &c.Server.ShutdownTimeout)
+	env.duration("SERVER_READINESS_TIMEOUT_SECONDS", time.Second, &c.Server.ReadinessTimeout)
+	env.string("JWT_SECRET", &c.JWT.Secret)
+
+	env.string("LOG_LEVEL", &c.Log.Level)
+	env.string("LOG_FORMAT", &c.Log.Format)
+	env.duration("LOG_SLOW_QUERY_MS", time.Millisecond, &c.Log.SlowQueryThreshold)
+	env.bool("LOG_SQL_PARAMS", &c.Log.SQLParams)
+	env.bool("METRICS_ENABLED", &c.Metrics.Enabled)
+
+	env.string("TRACING_EXPORTER", &c.Tracing.Exporter)
+	env.string("TRACING_OTLP_ENDPOINT", &c.Tracing.Endpoint)
+	env.string("TRACING_FILE", &c.Tracing.File)
+	env.string("TRACING_SERVICE_NAME", &c.Tracing.ServiceName)
+	env.float("TRACING_SAMPLE_RATIO", &c.Tracing.SampleRatio)
+
+	env.list("CORS_ALLOWED_ORIGINS", &c.CORS.AllowedOrigins)
+	env.list("CORS_ALLOWED_METHODS", &c.CORS.AllowedMethods)
+	env.list("CORS_ALLOWED_HEADERS", &c.CORS.AllowedHeaders)
+	env.list("CORS_EXPOSED_HEADERS", &c.CORS.ExposedHeaders)
+	env.bool("CORS_ALLOW_CREDENTIALS", &c.CORS.AllowCredentials)
+	env.duration("CORS_MAX_AGE_SECONDS", time.Second, &c.CORS.MaxAge)
+	env.duration("HSTS_MAX_AGE_SECONDS", time.Second, &c.Security.HSTSMaxAge)
+	env.bool("HSTS_INCLUDE_SUBDOMAINS", &c.Security.HSTSIncludeSubdomains)
+	env.string("REFERRER_POLICY", &c.Security.ReferrerPolicy)
+	env.string("CONTENT_SECURITY_POLICY", &c.Security.ContentSecurityPolicy)
+
+	env.string("DATABASE_URL", &c.Database.URL)
+	env.string("DB_HOST", &c.Database.Host)
+	env.string("DB_PORT", &c.Database.Port)
+	env.string("DB_USER", &c.Database.User)
+	env.string("DB_PASSWORD", &c.Database.Password)
+	env.string("DB_NAME", &c.Database.DBName)
+	env.string("DB_SSLMODE", &c.Database.SSLMode)
+	env.string("DB_SSLROOTCERT", &c.Database.SSLRootCert)
+	env.list("DATABASE_REPLICA_URLS", &c.Database.ReplicaURLs)
+	env.int("DB_MAX_OPEN_CONNS", &c.Database.MaxOpenConns)
+	env.int("DB_MAX_IDLE_CONNS", &c.Database.MaxIdleConns)
+	env.duration("DB_STATEMENT_TIMEOUT_MS", time.Millisecond, &c.Database.StatementTimeout)
+	env.duration("DB_CONN_MAX_LIFETIME_MINUTES", time.Minute, &c.Database.ConnMaxLifetime)
+	env.duration("DB_CONN_MAX_IDLE_TIME_MINUTES", time.Minute, &c.Database.ConnMaxIdleTime)
+
+	env.bool("DB_MIGRATE_ON_STARTUP", &c.Migrations.OnStartup)
+	env.string("MIGRATIONS_DIR", &c.Migrations.Dir)
+	env.driftPolicy("DB_MIGRATION_DRIFT", &c.Migrations.Options.DriftPolicy)
+	env.duration("DB_MIGRATION_LOCK_TIMEOUT_SECONDS", time.Second, &c.Migrations.Options.LockTimeout)
+
+	env.string("ADMIN_BOOTSTRAP_EMAIL", &c.Bootstrap.Email)
+	env.string("ADMIN_BOOTSTRAP_NAME", &c.Bootstrap.Name)
+	env.string("ADMIN_BOOTSTRAP_PASSWORD", &c.Bootstrap.Password)
+
+	env.duration("ADMIN_TRASH_RETENTION_DAYS", 24*time.Hour, &c.Admin.TrashRetention)
+	env.list("ADMIN_ALLOWED_CIDRS", &c.Admin.AllowedCIDRs)
+	env.bool("ADMIN_NETWORK_POLICY_BYPASS", &c.Admin.NetworkPolicyBypass)
+	env.duration("ADMIN_PASSWORD_MAX_AGE_DAYS", 24*time.Hour, &c.Admin.PasswordMaxAge)
+	env.int("ADMIN_PASSWORD_HISTORY_SIZE", &c.Admin.PasswordHistorySize)
+	env.duration("ADMIN_REAUTH_WINDOW_MINUTES", time.Minute, &c.Admin.ReauthWindow)
+	env.list("ADMIN_APPROVAL_ACTIONS", &c.Admin.ApprovalActions)
+	env.duration("ADMIN_APPROVAL_TTL_HOURS", time.Hour, &c.Admin.ApprovalTTL)
+	env.int("ADMIN_BULK_DELETE_APPROVAL_THRESHOLD", &c.Admin.BulkDeleteApprovalThreshold)
+	env.string("ADMIN_INVITATION_URL", &c.Admin.InvitationURL)
+	env.duration("ADMIN_INVITATION_TTL_HOURS", time.Hour, &c.Admin.InvitationTTL)
+	env.duration("ADMIN_STATS_CACHE_TTL_SECONDS", time.Second, &c.Admin.StatsCacheTTL)
+	env.duration("IMPERSONATION_TTL_MINUTES", time.Minute, &c.ImpersonationTTL)
+
+	env.string("SMTP_HOST", &c.SMTP.Host)
+	env.string("SMTP_PORT", &c.SMTP.Port)
+	env.string("SMTP_USERNAME", &c.SMTP.Username)
+	env.string("SMTP_PASSWORD", &c.SMTP.Password)
+	env.string("SMTP_FROM", &c.SMTP.From)
+
+	env.string("SEED_ADMIN_PASSWORD", &c.Seed.AdminPassword)
+	env.string("SEED_USER_PASSWORD", &c.Seed.UserPassword)
+
+	return env.err
+}
+
+// envReader ilk geçersiz değerde hatayı saklar ve sonraki değişkenleri atlar
+type envReader struct {
+	err error
+}
+
+func (r *envReader) lookup(name string) (string, bool) {
+	if r.err != nil {
+		return "", false
+	}
+	value := strings.TrimSpace(os.Getenv(name))
+	return value, value != ""
+}
+
+func (r *envReader) string(name string, dst *string) {
+	if value, ok := r.lookup(name); ok {
+		*dst = value
+	}
+}
+
+func (r *envReader) int(name string, dst *int) {
+	if value, ok := r.lookup(name); ok {
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			r.err = fmt.Errorf("invalid %s %q", name, value)
+			return
+		}
+		*dst = n
+	}
+}
+
+func (r *envReader) bool(name string, dst *bool) {
+	if value, ok := r.lookup(name); ok {
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			r.err = fmt.Errorf("invalid %s %q", name, value)
+			return
+		}
+		*dst = b
+	}
+}
+
+func (r *envReader) float(name string, dst *float64) {
+	if value, ok := r.lookup(name); ok {
+		f, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			r.err = fmt.Errorf("invalid %s %q", name, value)
+			return
+		}
+		*dst = f
+	}
+}
+
+func (r *envReader) duration(name string, unit time.Duration, dst *time.Duration) {
+	if value, ok := r.lookup(name); ok {
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 0 {
+			r.err = fmt.Errorf("invalid %s %q", name, value)
+			return
+		}
+		*dst = time.Duration(n) * unit
+	}
+}
+
+func (r *envReader) driftPolicy(name string, dst *database.DriftPolicy) {
+	if value, ok := r.lookup(name); ok {
+		policy, err := database.ParseDriftPolicy(value)
+		if err != nil {
+			r.err = err
+			return
+		}
+		*dst = policy
+	}
+}
+
+// list virgülle ayrılmış değeri boş elemanları atlayarak listeye çevirir
+func (r *envReader) list(name string, dst *[]string) {
+	if value, ok := r.lookup(name); ok {
+		var items []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		*dst = items
+	}
+}
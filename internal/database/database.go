@@ -1,43 +1,187 @@
 package database
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
-
-	"prototurk/internal/models"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
 )
 
 type Config struct {
-	Host     string
-	Port     string
-	User     string
-	Password string
-	DBName   string
+	// URL (DATABASE_URL) verilirse Host, Port, User, Password ve DBName yerine kullanılır
+	URL      string `yaml:"url"`
+	Host     string `yaml:"host"`
+	Port     string `yaml:"port"`
+	User     string `yaml:"user"`
+	Password string `yaml:"password"`
+	DBName   string `yaml:"dbname"`
+
+	SSLMode     string `yaml:"sslmode"`
+	SSLRootCert string `yaml:"sslrootcert"`
+	// StatementTimeout sıfırdan büyükse her bağlantıda statement_timeout olarak ayarlanır
+	StatementTimeout time.Duration `yaml:"statement_timeout"`
+
+	MaxOpenConns    int           `yaml:"max_open_conns"`
+	MaxIdleConns    int           `yaml:"max_idle_conns"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime"`
+	ConnMaxIdleTime time.Duration `yaml:"conn_max_idle_time"`
+
+	// ReplicaURLs salt okunur sorguların yönlendirilebileceği read replica bağlantılarıdır
+	ReplicaURLs []string `yaml:"replica_urls"`
+
+	// Logger boşsa GORM'un varsayılan logger'ı kullanılır
+	Logger gormlogger.Interface `yaml:"-"`
 }
 
-func NewConnection(config *Config) (*gorm.DB, error) {
-	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable",
-		config.Host, config.Port, config.User, config.Password, config.DBName)
+// DefaultConfig bağlantı havuzu varsayılanlarıyla bir Config döner
+func DefaultConfig() *Config {
+	return &Config{
+		MaxOpenConns:    25,
+		MaxIdleConns:    5,
+		ConnMaxLifetime: 30 * time.Minute,
+		ConnMaxIdleTime: 5 * time.Minute,
+	}
+}
+
+// Validate havuz ve timeout ayarlarının negatif olmadığını kontrol eder
+func (c *Config) Validate() error {
+	if c.MaxOpenConns < 0 || c.MaxIdleConns < 0 {
+		return fmt.Errorf("connection pool sizes must not be negative")
+	}
+	if c.MaxOpenConns > 0 && c.MaxIdleConns > c.MaxOpenConns {
+		return fmt.Errorf("max idle connections (%d) must not exceed max open connections (%d)", c.MaxIdleConns, c.MaxOpenConns)
+	}
+	if c.StatementTimeout < 0 || c.ConnMaxLifetime < 0 || c.ConnMaxIdleTime < 0 {
+		return fmt.Errorf("database timeouts must not be negative")
+	}
+	return nil
+}
 
-	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{})
+// DSN primary bağlantı için pgx DSN'ini oluşturur
+func (c *Config) DSN() (string, error) {
+	if c.URL != "" {
+		return c.withOptions(c.URL)
+	}
+
+	parts := []string{
+		"host=" + dsnValue(c.Host),
+		"port=" + dsnValue(c.Port),
+		"user=" + dsnValue(c.User),
+		"password=" + dsnValue(c.Password),
+		"dbname=" + dsnValue(c.DBName),
+	}
+	sslMode := c.SSLMode
+	if sslMode == "" {
+		sslMode = "disable"
+	}
+	parts = append(parts, "sslmode="+dsnValue(sslMode))
+	if c.SSLRootCert != "" {
+		parts = append(parts, "sslrootcert="+dsnValue(c.SSLRootCert))
+	}
+	if c.StatementTimeout > 0 {
+		parts = append(parts, fmt.Sprintf("statement_timeout=%d", c.StatementTimeout.Milliseconds()))
+	}
+	return strings.Join(parts, " "), nil
+}
+
+// withOptions URL biçimindeki DSN'e, URL'de açıkça verilmemiş TLS ve timeout ayarlarını ekler
+func (c *Config) withOptions(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid database url: %v", err)
+	}
+
+	query := u.Query()
+	if c.SSLMode != "" && query.Get("sslmode") == "" {
+		query.Set("sslmode", c.SSLMode)
+	}
+	if c.SSLRootCert != "" && query.Get("sslrootcert") == "" {
+		query.Set("sslrootcert", c.SSLRootCert)
+	}
+	if c.StatementTimeout > 0 && query.Get("statement_timeout") == "" {
+		query.Set("statement_timeout", strconv.FormatInt(c.StatementTimeout.Milliseconds(), 10))
+	}
+	u.RawQuery = query.Encode()
+	return u.String(), nil
+}
+
+// dsnValue anahtar=değer DSN'inde boşluk veya tırnak içeren değerleri tırnaklar
+func dsnValue(value string) string {
+	if value != "" && !strings.ContainsAny(value, ` '\`) {
+		return value
+	}
+	value = strings.ReplaceAll(value, `\`, `\\`)
+	return "'" + strings.ReplaceAll(value, "'", `\'`) + "'"
+}
+
+func NewConnection(config *Config) (*gorm.DB, error) {
+	dsn, err := config.DSN()
 	if err != nil {
 		return nil, err
 	}
 
-	// Create enum type
-	db.Exec(`DO $$ BEGIN
-		CREATE TYPE user_status AS ENUM ('active', 'passive', 'banned');
-		EXCEPTION
-		WHEN duplicate_object THEN null;
-	END $$;`)
+	// Şema sadece SQL migration'lardan gelir, burada AutoMigrate veya tip oluşturma yapılmaz
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: config.Logger})
+	if err != nil {
+		return nil, err
+	}
 
-	// Auto migrate the schema
-	err = db.AutoMigrate(&models.User{})
+	sqlDB, err := db.DB()
 	if err != nil {
 		return nil, err
 	}
+	sqlDB.SetMaxOpenConns(config.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(config.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(config.ConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(config.ConnMaxIdleTime)
+
+	if len(config.ReplicaURLs) > 0 {
+		if err := registerReplicas(db, config); err != nil {
+			return nil, fmt.Errorf("error configuring read replicas: %v", err)
+		}
+	}
 
 	return db, nil
 }
+
+// Pools bağlantı havuzlarını isimleriyle döner: "primary" ve tanımlıysa "replica_1", "replica_2"...
+// Metrik etiketi olarak kullanıldığı için replica URL'leri (parolalar dahil) isimlere eklenmez.
+func Pools(db *gorm.DB) (map[string]*sql.DB, error) {
+	primary, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+
+	pools := map[string]*sql.DB{"primary": primary}
+	if replicas, ok := replicaPools.Load(db.Config); ok {
+		for i, pool := range replicas.([]*sql.DB) {
+			pools[fmt.Sprintf("replica_%d", i+1)] = pool
+		}
+	}
+	return pools, nil
+}
+
+// Close replica havuzlarını ve primary bağlantı havuzunu kapatır.
+// Kullanımdaki bağlantılar işleri bitince kapanır; Close'dan sonra db kullanılmamalıdır.
+func Close(db *gorm.DB) error {
+	var errs []error
+	if pools, ok := replicaPools.LoadAndDelete(db.Config); ok {
+		for _, pool := range pools.([]*sql.DB) {
+			errs = append(errs, pool.Close())
+		}
+	}
+
+	primary, err := db.DB()
+	if err != nil {
+		return err
+	}
+	errs = append(errs, primary.Close())
+	return errors.Join(errs...)
+}
@@ -0,0 +1,49 @@
+package database
+
+import (
+	"errors"
+	"time"
+
+	"prototurk/pkg/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	environmentKey        = "environment"
+	EnvironmentProduction = "production"
+)
+
+// AppMetadata veritabanının kendisine ait anahtar/değer bilgileridir
+type AppMetadata struct {
+	Key       string    `gorm:"type:varchar(100);primaryKey"`
+	Value     string    `gorm:"type:text;not null"`
+	UpdatedAt time.Time `gorm:"type:timestamp with time zone;not null"`
+}
+
+func (AppMetadata) TableName() string {
+	return "app_metadata"
+}
+
+// MarkProduction veritabanını production olarak işaretler. İşaret uygulama tarafından kaldırılmaz;
+// bir veritabanı production dışına alınacaksa app_metadata kaydı elle silinmelidir.
+func MarkProduction(db *gorm.DB) error {
+	return db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "key"}},
+		DoUpdates: clause.AssignmentColumns([]string{"value", "updated_at"}),
+	}).Create(&AppMetadata{Key: environmentKey, Value: EnvironmentProduction, UpdatedAt: utils.Now()}).Error
+}
+
+// IsProduction veritabanının production olarak işaretlenip işaretlenmediğini döner
+func IsProduction(db *gorm.DB) (bool, error) {
+	var metadata AppMetadata
+	err := db.Where("key = ?", environmentKey).First(&metadata).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return metadata.Value == EnvironmentProduction, nil
+}
@@ -2,73 +2,514 @@ package database
 
 import (
 	"fmt"
-	"log"
+	"io/fs"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"regexp"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
+
+	"prototurk/migrations"
+	"prototurk/pkg/utils"
+
+	"gorm.io/gorm"
 )
 
-// RunMigrations tüm migration dosyalarını sırayla çalıştırır
-func RunMigrations(config *Config) error {
-	// Database bağlantısını bir kere oluştur
-	db, err := NewConnection(config)
-	if err != nil {
-		return fmt.Errorf("error connecting to database: %v", err)
+// MigrationsDir migration dosyalarının repository içindeki klasörüdür. Yeni dosyalar burada oluşturulur.
+const MigrationsDir = "migrations"
+
+// MigrationSource migration dosyalarının okunacağı kaynağı döner. dir boşsa binary'ye gömülü dosyalar,
+// değilse (development'ta dosyaları yeniden derlemeden denemek için) verilen klasör kullanılır.
+func MigrationSource(dir string) fs.FS {
+	if dir == "" {
+		return migrations.FS
+	}
+	return os.DirFS(dir)
+}
+
+// Migration tek bir şema değişikliğini temsil eder.
+// Up dosyası "NNN_name.up.sql" veya eski biçimdeki "NNN_name.sql" olabilir; down dosyası "NNN_name.down.sql" olarak opsiyoneldir.
+type Migration struct {
+	Version  int
+	Name     string
+	UpFile   string
+	DownFile string
+	// Checksum up dosyasının SHA-256 özetidir
+	Checksum string
+}
+
+// HasDown kontrol eder migration'ın geri alınabilir olup olmadığını
+func (m Migration) HasDown() bool {
+	return m.DownFile != ""
+}
+
+// MigrationRecord migrations tablosundaki bir kaydı temsil eder. Name, çalıştırılan up dosyasının adıdır.
+type MigrationRecord struct {
+	ID         uint      `gorm:"primarykey"`
+	Name       string    `gorm:"type:varchar(255);not null;unique"`
+	Checksum   *string   `gorm:"type:varchar(64)"`
+	ExecutedAt time.Time `gorm:"type:timestamp with time zone"`
+}
+
+func (MigrationRecord) TableName() string {
+	return "migrations"
+}
+
+// MigrationStatus bir migration'ın veritabanındaki durumunu gösterir
+type MigrationStatus struct {
+	Migration
+	Applied    bool
+	ExecutedAt *time.Time
+	// Modified uygulanmış dosyanın içeriğinin kayıtlı checksum'dan farklı olduğunu belirtir
+	Modified bool
+}
+
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_(.+?)(\.up|\.down)?\.sql$`)
+
+// migrationKey kayıtlı dosya adını uzantısız migration adına çevirir, böylece eski ve yeni biçimler eşleşir
+func migrationKey(file string) string {
+	file = strings.TrimSuffix(file, ".sql")
+	file = strings.TrimSuffix(file, ".up")
+	return strings.TrimSuffix(file, ".down")
+}
+
+// RunMigrations source içindeki bekleyen tüm migration'ları uygulamanın bağlantısı üzerinden sırayla çalıştırır
+func RunMigrations(db *gorm.DB, source fs.FS, options MigratorOptions) error {
+	_, err := NewMigrator(db, source, options).Up(0)
+	return err
+}
+
+// Migrator kaynaktaki migration dosyalarını veritabanına uygular ve geri alır
+type Migrator struct {
+	db          *gorm.DB
+	source      fs.FS
+	driftPolicy DriftPolicy
+	lockTimeout time.Duration
+}
+
+// MigratorOptions migrator davranışını belirler
+type MigratorOptions struct {
+	DriftPolicy DriftPolicy `yaml:"drift"`
+	// LockTimeout başka bir süreç migration çalıştırırken advisory lock için beklenecek en uzun süredir
+	LockTimeout time.Duration `yaml:"lock_timeout"`
+}
+
+func NewMigrator(db *gorm.DB, source fs.FS, options MigratorOptions) *Migrator {
+	if options.LockTimeout <= 0 {
+		options.LockTimeout = defaultMigrationLockTimeout
 	}
+	return &Migrator{db: db, source: source, driftPolicy: options.DriftPolicy, lockTimeout: options.LockTimeout}
+}
 
-	// Migration klasörünü oku
-	files, err := os.ReadDir("migrations")
+// Migrations kaynaktaki migration'ları versiyona göre sıralı döner
+func (m *Migrator) Migrations() ([]Migration, error) {
+	files, err := fs.ReadDir(m.source, ".")
 	if err != nil {
-		return fmt.Errorf("error reading migrations directory: %v", err)
+		return nil, fmt.Errorf("error reading migrations directory: %v", err)
 	}
 
-	// SQL dosyalarını filtrele ve sırala
-	var sqlFiles []string
+	byName := make(map[string]*Migration)
 	for _, file := range files {
-		if !file.IsDir() && strings.HasSuffix(file.Name(), ".sql") {
-			sqlFiles = append(sqlFiles, file.Name())
+		if file.IsDir() {
+			continue
+		}
+		match := migrationFilePattern.FindStringSubmatch(file.Name())
+		if match == nil {
+			continue
 		}
+
+		version, err := strconv.Atoi(match[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %s: %v", file.Name(), err)
+		}
+
+		name := migrationKey(file.Name())
+		migration, ok := byName[name]
+		if !ok {
+			migration = &Migration{Version: version, Name: name}
+			byName[name] = migration
+		}
+
+		if match[3] == ".down" {
+			migration.DownFile = file.Name()
+			continue
+		}
+		if migration.UpFile != "" {
+			return nil, fmt.Errorf("migration %s has both %s and %s", name, migration.UpFile, file.Name())
+		}
+		migration.UpFile = file.Name()
 	}
-	sort.Strings(sqlFiles)
 
-	// Her migration dosyasını çalıştır
-	for _, file := range sqlFiles {
-		// Migration daha önce çalıştırılmış mı kontrol et
-		var count int64
-		if err := db.Table("migrations").Where("name = ?", file).Count(&count).Error; err == nil && count > 0 {
-			log.Printf("Skipping migration %s: already executed", file)
+	migrations := make([]Migration, 0, len(byName))
+	versions := make(map[int]string)
+	for name, migration := range byName {
+		if migration.UpFile == "" {
+			return nil, fmt.Errorf("migration %s has no up file", name)
+		}
+		content, err := fs.ReadFile(m.source, migration.UpFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading migration file %s: %v", migration.UpFile, err)
+		}
+		migration.Checksum = checksum(content)
+
+		if other, ok := versions[migration.Version]; ok {
+			return nil, fmt.Errorf("duplicate migration version %d: %s and %s", migration.Version, other, name)
+		}
+		versions[migration.Version] = name
+		migrations = append(migrations, *migration)
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+	return migrations, nil
+}
+
+// Status her migration'ın uygulanıp uygulanmadığını döner
+func (m *Migrator) Status() ([]MigrationStatus, error) {
+	migrations, err := m.Migrations()
+	if err != nil {
+		return nil, err
+	}
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(migrations))
+	for _, migration := range migrations {
+		status := MigrationStatus{Migration: migration}
+		if record, ok := applied[migration.Name]; ok {
+			executedAt := record.ExecutedAt
+			status.Applied = true
+			status.ExecutedAt = &executedAt
+			status.Modified = record.Checksum != nil && *record.Checksum != migration.Checksum
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// Up bekleyen migration'lardan en fazla n tanesini çalıştırır. n <= 0 ise hepsini çalıştırır.
+// Çalıştırmadan önce uygulanmış dosyalarda değişiklik olup olmadığı drift politikasına göre kontrol edilir.
+func (m *Migrator) Up(n int) (count int, err error) {
+	err = m.withLock(func(locked *Migrator) error {
+		count, err = locked.up(n)
+		return err
+	})
+	return count, err
+}
+
+func (m *Migrator) up(n int) (int, error) {
+	if err := m.backfillChecksums(); err != nil {
+		return 0, err
+	}
+	if err := m.checkDrift(); err != nil {
+		return 0, err
+	}
+
+	statuses, err := m.Status()
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, status := range statuses {
+		if status.Applied {
 			continue
 		}
+		if n > 0 && count >= n {
+			break
+		}
+		if err := m.apply(status.Migration); err != nil {
+			return count, err
+		}
+		count++
+	}
+	return count, nil
+}
 
-		log.Printf("Running migration: %s", file)
+// Down uygulanmış son n migration'ı tersten geri alır. n <= 0 ise 1 kabul edilir.
+func (m *Migrator) Down(n int) (count int, err error) {
+	err = m.withLock(func(locked *Migrator) error {
+		count, err = locked.down(n)
+		return err
+	})
+	return count, err
+}
 
-		// Migration dosyasını oku
-		content, err := os.ReadFile(filepath.Join("migrations", file))
-		if err != nil {
-			return fmt.Errorf("error reading migration file %s: %v", file, err)
+func (m *Migrator) down(n int) (int, error) {
+	if n <= 0 {
+		n = 1
+	}
+
+	statuses, err := m.Status()
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for i := len(statuses) - 1; i >= 0 && count < n; i-- {
+		if !statuses[i].Applied {
+			continue
+		}
+		if err := m.revert(statuses[i].Migration); err != nil {
+			return count, err
 		}
+		count++
+	}
+	return count, nil
+}
 
-		// Migration'ı transaction içinde çalıştır
-		tx := db.Begin()
+// Redo son uygulanan migration'ı geri alıp tekrar çalıştırır
+func (m *Migrator) Redo() (name string, err error) {
+	err = m.withLock(func(locked *Migrator) error {
+		name, err = locked.redo()
+		return err
+	})
+	return name, err
+}
 
-		// Migration'ı çalıştır
-		if err := tx.Exec(string(content)).Error; err != nil {
-			tx.Rollback()
-			return fmt.Errorf("error executing migration %s: %v", file, err)
+func (m *Migrator) redo() (string, error) {
+	statuses, err := m.Status()
+	if err != nil {
+		return "", err
+	}
+
+	for i := len(statuses) - 1; i >= 0; i-- {
+		if !statuses[i].Applied {
+			continue
 		}
+		migration := statuses[i].Migration
+		if err := m.revert(migration); err != nil {
+			return "", err
+		}
+		return migration.Name, m.apply(migration)
+	}
+	return "", fmt.Errorf("no applied migration to redo")
+}
+
+// Force SQL çalıştırmadan migrations tablosunu verilen versiyona getirir: versiyona kadar olanlar uygulanmış,
+// sonrakiler uygulanmamış olarak işaretlenir. Yarım kalmış bir migration elle düzeltildikten sonra kullanılır.
+func (m *Migrator) Force(version int) error {
+	return m.withLock(func(locked *Migrator) error {
+		return locked.force(version)
+	})
+}
+
+func (m *Migrator) force(version int) error {
+	statuses, err := m.Status()
+	if err != nil {
+		return err
+	}
+
+	return m.db.Transaction(func(tx *gorm.DB) error {
+		for _, status := range statuses {
+			switch {
+			case status.Version <= version && !status.Applied:
+				if err := tx.Create(&MigrationRecord{Name: status.UpFile, Checksum: &status.Checksum, ExecutedAt: utils.Now()}).Error; err != nil {
+					return err
+				}
+			case status.Version > version && status.Applied:
+				if err := m.deleteRecord(tx, status.Name); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// applied migrations tablosundaki kayıtları uzantısız migration adına göre döner
+func (m *Migrator) applied() (map[string]MigrationRecord, error) {
+	if err := m.ensureTable(); err != nil {
+		return nil, err
+	}
+
+	var records []MigrationRecord
+	if err := m.db.Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("error reading migrations table: %v", err)
+	}
+
+	applied := make(map[string]MigrationRecord, len(records))
+	for _, record := range records {
+		applied[migrationKey(record.Name)] = record
+	}
+	return applied, nil
+}
 
-		// Migration kaydını ekle
-		if err := tx.Table("migrations").Create(map[string]interface{}{
-			"name": file,
-		}).Error; err != nil {
-			tx.Rollback()
-			return fmt.Errorf("error recording migration %s: %v", file, err)
+// ensureTable migrations tablosunu 000 migration'ı ile aynı tanımla oluşturur, böylece boş veritabanında da durum okunabilir.
+// checksum kolonu migrator'ın kendi kayıt tutma alanı olduğu için burada eklenir.
+func (m *Migrator) ensureTable() error {
+	if err := m.db.Exec(`CREATE TABLE IF NOT EXISTS migrations (
+    id SERIAL PRIMARY KEY,
+    name VARCHAR(255) NOT NULL UNIQUE,
+    executed_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+)`).Error; err != nil {
+		return err
+	}
+	return m.db.Exec(`ALTER TABLE migrations ADD COLUMN IF NOT EXISTS checksum VARCHAR(64)`).Error
+}
+
+// apply migration'ı ve kaydını aynı transaction içinde çalıştırır
+func (m *Migrator) apply(migration Migration) error {
+	slog.Info("Running migration", "file", migration.UpFile)
+
+	err := m.execute(migration.UpFile, func(tx *gorm.DB) error {
+		if err := tx.Create(&MigrationRecord{Name: migration.UpFile, Checksum: &migration.Checksum, ExecutedAt: utils.Now()}).Error; err != nil {
+			return fmt.Errorf("error recording migration %s: %v", migration.UpFile, err)
 		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
 
-		tx.Commit()
-		log.Printf("Migration completed: %s", file)
+	slog.Info("Migration completed", "file", migration.UpFile)
+	return nil
+}
+
+// revert migration'ın down dosyasını çalıştırır ve kaydını siler
+func (m *Migrator) revert(migration Migration) error {
+	if !migration.HasDown() {
+		return fmt.Errorf("migration %s has no down file", migration.Name)
 	}
 
+	slog.Info("Reverting migration", "file", migration.DownFile)
+
+	err := m.execute(migration.DownFile, func(tx *gorm.DB) error {
+		return m.deleteRecord(tx, migration.Name)
+	})
+	if err != nil {
+		return err
+	}
+
+	slog.Info("Migration reverted", "file", migration.DownFile)
 	return nil
 }
+
+// execute dosyayı çalıştırır ve record ile migrations tablosunu günceller. Dosya normalde kayıt ile aynı
+// transaction'da çalışır; "-- migrate:no-transaction" başlığı taşıyan dosyalar (örneğin CREATE INDEX CONCURRENTLY)
+// ifade ifade transaction dışında çalıştırılır ve kayıt sonradan yazılır. Bu dosyalar yarıda kalırsa
+// tekrar çalıştırılabilir olmalıdır (IF NOT EXISTS vb.).
+func (m *Migrator) execute(file string, record func(tx *gorm.DB) error) error {
+	content, err := fs.ReadFile(m.source, file)
+	if err != nil {
+		return fmt.Errorf("error reading migration file %s: %v", file, err)
+	}
+
+	if !isNoTransaction(string(content)) {
+		return m.db.Transaction(func(tx *gorm.DB) error {
+			if err := tx.Exec(string(content)).Error; err != nil {
+				return fmt.Errorf("error executing migration %s: %v", file, err)
+			}
+			return record(tx)
+		})
+	}
+
+	// Dosya hiçbir ifade çalıştırılmadan önce ayrıştırılır; hatalı bir dosya yarıda kalmaz
+	statements, err := splitStatements(string(content))
+	if err != nil {
+		return fmt.Errorf("error parsing migration %s: %v", file, err)
+	}
+
+	slog.Info("Running migration outside of a transaction", "file", file)
+	for _, statement := range statements {
+		if err := m.db.Exec(statement).Error; err != nil {
+			return fmt.Errorf("error executing migration %s: %v", file, err)
+		}
+	}
+	return m.db.Transaction(record)
+}
+
+// deleteRecord migration'a ait kaydı hem eski (.sql) hem yeni (.up.sql) ad biçiminde siler
+func (m *Migrator) deleteRecord(tx *gorm.DB, name string) error {
+	return tx.Where("name IN ?", []string{name + ".sql", name + ".up.sql"}).Delete(&MigrationRecord{}).Error
+}
+
+var migrationNameSanitizer = regexp.MustCompile(`[^a-z0-9]+`)
+
+// CreateMigration klasörde bir sonraki versiyon numarasıyla boş up/down dosya çifti oluşturur
+func CreateMigration(dir string, name string) (string, string, error) {
+	name = strings.Trim(migrationNameSanitizer.ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if name == "" {
+		return "", "", fmt.Errorf("migration name is required")
+	}
+
+	existing, err := NewMigrator(nil, os.DirFS(dir), MigratorOptions{}).Migrations()
+	if err != nil {
+		return "", "", err
+	}
+
+	version := 0
+	if len(existing) > 0 {
+		version = existing[len(existing)-1].Version + 1
+	}
+
+	base := fmt.Sprintf("%03d_%s", version, name)
+	upFile := filepath.Join(dir, base+".up.sql")
+	downFile := filepath.Join(dir, base+".down.sql")
+
+	if err := os.WriteFile(upFile, []byte(fmt.Sprintf("-- %s\n", base)), 0644); err != nil {
+		return "", "", err
+	}
+	if err := os.WriteFile(downFile, []byte(fmt.Sprintf("-- %s geri alma\n", base)), 0644); err != nil {
+		os.Remove(upFile)
+		return "", "", err
+	}
+	return upFile, downFile, nil
+}
+
+// CompareSources iki kaynaktaki .sql dosyalarını karşılaştırır ve farklılıkları açıklayan satırlar döner.
+// Gömülü dosyaların diskteki klasörle aynı olduğunu doğrulamak için kullanılır.
+func CompareSources(expected fs.FS, actual fs.FS) ([]string, error) {
+	expectedFiles, err := sqlFiles(expected)
+	if err != nil {
+		return nil, err
+	}
+	actualFiles, err := sqlFiles(actual)
+	if err != nil {
+		return nil, err
+	}
+
+	var diffs []string
+	for name, content := range expectedFiles {
+		other, ok := actualFiles[name]
+		switch {
+		case !ok:
+			diffs = append(diffs, fmt.Sprintf("missing: %s", name))
+		case other != content:
+			diffs = append(diffs, fmt.Sprintf("modified: %s", name))
+		}
+	}
+	for name := range actualFiles {
+		if _, ok := expectedFiles[name]; !ok {
+			diffs = append(diffs, fmt.Sprintf("unexpected: %s", name))
+		}
+	}
+	sort.Strings(diffs)
+	return diffs, nil
+}
+
+func sqlFiles(source fs.FS) (map[string]string, error) {
+	entries, err := fs.ReadDir(source, ".")
+	if err != nil {
+		return nil, fmt.Errorf("error reading migrations: %v", err)
+	}
+
+	files := make(map[string]string)
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".sql") {
+			continue
+		}
+		content, err := fs.ReadFile(source, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		files[entry.Name()] = string(content)
+	}
+	return files, nil
+}
@@ -0,0 +1,180 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+	"unicode"
+
+	"gorm.io/gorm"
+)
+
+// migrationLockKey migration çalıştıran tüm süreçlerin paylaştığı advisory lock anahtarıdır
+const migrationLockKey int64 = 7301946258
+
+const (
+	defaultMigrationLockTimeout = time.Minute
+	migrationLockRetryInterval  = 500 * time.Millisecond
+)
+
+// noTransactionDirective dosyanın başındaki yorum satırlarında bulunursa migration transaction dışında çalışır
+const noTransactionDirective = "-- migrate:no-transaction"
+
+// DefaultMigratorOptions drift'te durmayı ve bir dakika lock beklemeyi varsayılan kabul eder
+func DefaultMigratorOptions() MigratorOptions {
+	return MigratorOptions{DriftPolicy: DriftFail, LockTimeout: defaultMigrationLockTimeout}
+}
+
+// withLock fn'i Postgres session seviyesinde advisory lock tutan tek bir bağlantı üzerinde çalıştırır.
+// Böylece aynı anda açılan birden fazla replika migration'ları sırayla ve bir kez çalıştırır.
+// Lock timeout süresi içinde alınamazsa hata döner.
+func (m *Migrator) withLock(fn func(locked *Migrator) error) error {
+	return m.db.Connection(func(conn *gorm.DB) error {
+		if err := acquireMigrationLock(conn, m.lockTimeout); err != nil {
+			return err
+		}
+		defer func() {
+			if err := conn.Exec("SELECT pg_advisory_unlock(?)", migrationLockKey).Error; err != nil {
+				slog.Error("Error releasing migration lock", "error", err)
+			}
+		}()
+
+		locked := *m
+		locked.db = conn
+		return fn(&locked)
+	})
+}
+
+func acquireMigrationLock(conn *gorm.DB, timeout time.Duration) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	waiting := false
+	for {
+		var acquired bool
+		if err := conn.Raw("SELECT pg_try_advisory_lock(?)", migrationLockKey).Scan(&acquired).Error; err != nil {
+			return fmt.Errorf("error acquiring migration lock: %v", err)
+		}
+		if acquired {
+			return nil
+		}
+
+		if !waiting {
+			slog.Info("Waiting for another process to finish running migrations")
+			waiting = true
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out after %s waiting for migration lock", timeout)
+		case <-time.After(migrationLockRetryInterval):
+		}
+	}
+}
+
+// isNoTransaction dosyanın SQL'den önceki yorum satırlarında no-transaction direktifi olup olmadığını kontrol eder
+func isNoTransaction(content string) bool {
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if !strings.HasPrefix(line, "--") {
+			return false
+		}
+		if line == noTransactionDirective {
+			return true
+		}
+	}
+	return false
+}
+
+// splitStatements SQL'i noktalı virgüllerden ifadelere ayırır. Tırnak içindeki, yorumlardaki ve
+// dollar-quoted ($$ ... $$) bloklardaki noktalı virgüller ayırıcı sayılmaz. Kapanmayan bir tırnak veya
+// dollar-quoted blok hata döner.
+func splitStatements(content string) ([]string, error) {
+	var statements []string
+	var current strings.Builder
+	runes := []rune(content)
+
+	flush := func() {
+		statement := strings.TrimSpace(current.String())
+		if statement != "" && !onlyComments(statement) {
+			statements = append(statements, statement)
+		}
+		current.Reset()
+	}
+
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == '-' && i+1 < len(runes) && runes[i+1] == '-':
+			end := i
+			for end < len(runes) && runes[end] != '\n' {
+				end++
+			}
+			current.WriteString(string(runes[i:end]))
+			i = end - 1
+
+		case r == '\'' || r == '"':
+			end := i + 1
+			for end < len(runes) && runes[end] != r {
+				end++
+			}
+			if end >= len(runes) {
+				return nil, fmt.Errorf("unterminated %c quote starting at offset %d", r, i)
+			}
+			current.WriteString(string(runes[i : end+1]))
+			i = end
+
+		case r == '$':
+			tag, ok := dollarTag(runes[i:])
+			if !ok {
+				current.WriteRune(r)
+				continue
+			}
+			body := string(runes[i+len(tag):])
+			end := strings.Index(body, string(tag))
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated %s block starting at offset %d", string(tag), i)
+			}
+			block := string(tag) + body[:end] + string(tag)
+			current.WriteString(block)
+			i += len([]rune(block)) - 1
+
+		case r == ';':
+			flush()
+
+		default:
+			current.WriteRune(r)
+		}
+	}
+	flush()
+
+	return statements, nil
+}
+
+// dollarTag s'nin başındaki $tag$ veya $$ ayracını döner
+func dollarTag(s []rune) ([]rune, bool) {
+	for i := 1; i < len(s); i++ {
+		if s[i] == '$' {
+			return s[:i+1], true
+		}
+		if !(unicode.IsLetter(s[i]) || unicode.IsDigit(s[i]) || s[i] == '_') {
+			return nil, false
+		}
+	}
+	return nil, false
+}
+
+func onlyComments(statement string) bool {
+	for _, line := range strings.Split(statement, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "--") {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,131 @@
+package database
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    []string
+	}{
+		{
+			name:    "simple",
+			content: "CREATE TABLE a (id INT);\nCREATE TABLE b (id INT);\n",
+			want:    []string{"CREATE TABLE a (id INT)", "CREATE TABLE b (id INT)"},
+		},
+		{
+			name:    "no trailing semicolon",
+			content: "SELECT 1;\nSELECT 2",
+			want:    []string{"SELECT 1", "SELECT 2"},
+		},
+		{
+			name:    "semicolon in single quotes",
+			content: "INSERT INTO a VALUES ('x;y');\nSELECT 1;",
+			want:    []string{"INSERT INTO a VALUES ('x;y')", "SELECT 1"},
+		},
+		{
+			name:    "escaped single quote",
+			content: "INSERT INTO a VALUES ('it''s; fine');",
+			want:    []string{"INSERT INTO a VALUES ('it''s; fine')"},
+		},
+		{
+			name:    "semicolon in double quotes",
+			content: `CREATE TABLE "a;b" (id INT);`,
+			want:    []string{`CREATE TABLE "a;b" (id INT)`},
+		},
+		{
+			name:    "semicolon in $$ block",
+			content: "DO $$ BEGIN PERFORM 1; PERFORM 2; END $$;\nSELECT 1;",
+			want:    []string{"DO $$ BEGIN PERFORM 1; PERFORM 2; END $$", "SELECT 1"},
+		},
+		{
+			name:    "semicolon in tagged $fn$ block",
+			content: "CREATE FUNCTION f() RETURNS INT AS $fn$ BEGIN RETURN 1; END $fn$ LANGUAGE plpgsql;",
+			want:    []string{"CREATE FUNCTION f() RETURNS INT AS $fn$ BEGIN RETURN 1; END $fn$ LANGUAGE plpgsql"},
+		},
+		{
+			name:    "$$ inside tagged block",
+			content: "DO $outer$ BEGIN EXECUTE $$SELECT 1;$$; END $outer$;",
+			want:    []string{"DO $outer$ BEGIN EXECUTE $$SELECT 1;$$; END $outer$"},
+		},
+		{
+			name:    "positional parameter is not a dollar tag",
+			content: "PREPARE p AS SELECT $1;",
+			want:    []string{"PREPARE p AS SELECT $1"},
+		},
+		{
+			name:    "semicolon in comment",
+			content: "-- drop a; then b\nDROP TABLE a;",
+			want:    []string{"-- drop a; then b\nDROP TABLE a"},
+		},
+		{
+			name:    "comment-only statements are skipped",
+			content: "-- sadece yorum\n;\nCREATE INDEX CONCURRENTLY i ON a(id);\n-- son yorum\n",
+			want:    []string{"CREATE INDEX CONCURRENTLY i ON a(id)"},
+		},
+		{
+			name:    "empty",
+			content: "\n  ;\n",
+			want:    nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := splitStatements(tt.content)
+			if err != nil {
+				t.Fatalf("splitStatements: %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("splitStatements = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSplitStatementsUnterminated(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+	}{
+		{"single quote", "INSERT INTO a VALUES ('x);\nSELECT 1;"},
+		{"double quote", `CREATE TABLE "a (id INT);`},
+		{"$$ block", "DO $$ BEGIN PERFORM 1; END;"},
+		{"tagged block", "DO $fn$ BEGIN PERFORM 1; END $other$;"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := splitStatements(tt.content); err == nil {
+				t.Fatalf("splitStatements = %q, want error", got)
+			}
+		})
+	}
+}
+
+func TestIsNoTransaction(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    bool
+	}{
+		{"first line", "-- migrate:no-transaction\nCREATE INDEX CONCURRENTLY i ON a(id);", true},
+		{"after other comments", "-- açıklama\n\n-- migrate:no-transaction\nCREATE INDEX CONCURRENTLY i ON a(id);", true},
+		{"surrounding whitespace", "  -- migrate:no-transaction  \nSELECT 1;", true},
+		{"missing", "-- açıklama\nCREATE TABLE a (id INT);", false},
+		{"after SQL", "CREATE TABLE a (id INT);\n-- migrate:no-transaction\n", false},
+		{"inside a longer comment", "-- not: -- migrate:no-transaction kullanılmaz\nSELECT 1;", false},
+		{"empty", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isNoTransaction(tt.content); got != tt.want {
+				t.Fatalf("isNoTransaction = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package database
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+)
+
+// DriftPolicy uygulanmış bir migration dosyası sonradan değiştirildiğinde ne yapılacağını belirler
+type DriftPolicy string
+
+const (
+	// DriftFail değişiklik bulunduğunda migration çalıştırmayı (ve uygulamanın açılmasını) durdurur
+	DriftFail DriftPolicy = "fail"
+	// DriftWarn değişikliği sadece loglar
+	DriftWarn DriftPolicy = "warn"
+)
+
+// ParseDriftPolicy boş değeri varsayılan olan DriftFail'e çevirir
+func ParseDriftPolicy(value string) (DriftPolicy, error) {
+	switch DriftPolicy(strings.ToLower(strings.TrimSpace(value))) {
+	case "", DriftFail:
+		return DriftFail, nil
+	case DriftWarn:
+		return DriftWarn, nil
+	}
+	return "", fmt.Errorf("unknown migration drift policy %q", value)
+}
+
+// MigrationReport kaynak dosyalar ile migrations tablosu arasındaki farkları listeler
+type MigrationReport struct {
+	// Missing henüz uygulanmamış migration'lar
+	Missing []string
+	// Modified uygulandıktan sonra içeriği değişmiş migration'lar
+	Modified []string
+	// Unknown veritabanında kaydı olan ama kaynakta dosyası bulunmayan migration'lar
+	Unknown []string
+	// Unverified checksum'ı kaydedilmemiş (checksum desteğinden önce uygulanmış) migration'lar
+	Unverified []string
+}
+
+// Clean kontrol eder veritabanının kaynakla birebir uyumlu olup olmadığını
+func (r MigrationReport) Clean() bool {
+	return len(r.Missing) == 0 && len(r.Modified) == 0 && len(r.Unknown) == 0
+}
+
+// Verify kaynak dosyaları migrations tablosuyla karşılaştırır
+func (m *Migrator) Verify() (MigrationReport, error) {
+	var report MigrationReport
+
+	migrations, err := m.Migrations()
+	if err != nil {
+		return report, err
+	}
+	applied, err := m.applied()
+	if err != nil {
+		return report, err
+	}
+
+	known := make(map[string]bool, len(migrations))
+	for _, migration := range migrations {
+		known[migration.Name] = true
+
+		record, ok := applied[migration.Name]
+		switch {
+		case !ok:
+			report.Missing = append(report.Missing, migration.UpFile)
+		case record.Checksum == nil:
+			report.Unverified = append(report.Unverified, migration.UpFile)
+		case *record.Checksum != migration.Checksum:
+			report.Modified = append(report.Modified, migration.UpFile)
+		}
+	}
+
+	for name, record := range applied {
+		if !known[name] {
+			report.Unknown = append(report.Unknown, record.Name)
+		}
+	}
+	sort.Strings(report.Unknown)
+	return report, nil
+}
+
+// Pending kaynakta olup migrations tablosunda kaydı bulunmayan migration dosyalarını döner.
+// Verify'dan farklı olarak tabloyu oluşturmaz ve kilit almaz; readiness kontrolü gibi sık çağrılan yerler içindir.
+// Veritabanında kaynakta olmayan (daha yeni sürümün uyguladığı) migration'lar bekleyen sayılmaz.
+func (m *Migrator) Pending(ctx context.Context) ([]string, error) {
+	migrations, err := m.Migrations()
+	if err != nil {
+		return nil, err
+	}
+
+	var names []string
+	if err := m.db.WithContext(ctx).Model(&MigrationRecord{}).Pluck("name", &names).Error; err != nil {
+		return nil, fmt.Errorf("error reading migrations table: %v", err)
+	}
+	applied := make(map[string]bool, len(names))
+	for _, name := range names {
+		applied[migrationKey(name)] = true
+	}
+
+	var pending []string
+	for _, migration := range migrations {
+		if !applied[migration.Name] {
+			pending = append(pending, migration.UpFile)
+		}
+	}
+	return pending, nil
+}
+
+// checkDrift uygulanmış dosyalardaki değişiklikleri drift politikasına göre hata veya uyarı olarak bildirir
+func (m *Migrator) checkDrift() error {
+	report, err := m.Verify()
+	if err != nil {
+		return err
+	}
+
+	if len(report.Unknown) > 0 {
+		slog.Warn("Migrations applied in database but not found in source", "migrations", report.Unknown)
+	}
+	if len(report.Modified) == 0 {
+		return nil
+	}
+
+	message := fmt.Sprintf("applied migrations have been modified: %s", strings.Join(report.Modified, ", "))
+	if m.driftPolicy == DriftWarn {
+		slog.Warn(message)
+		return nil
+	}
+	return fmt.Errorf("%s (run 'migrate verify' for details)", message)
+}
+
+// backfillChecksums checksum desteğinden önce uygulanmış kayıtlara mevcut dosyanın checksum'ını yazar.
+// Eski içerik bilinmediği için bu kayıtlar mevcut dosya ile referans alınır.
+func (m *Migrator) backfillChecksums() error {
+	migrations, err := m.Migrations()
+	if err != nil {
+		return err
+	}
+	applied, err := m.applied()
+	if err != nil {
+		return err
+	}
+
+	for _, migration := range migrations {
+		record, ok := applied[migration.Name]
+		if !ok || record.Checksum != nil {
+			continue
+		}
+		if err := m.db.Model(&MigrationRecord{}).Where("id = ?", record.ID).Update("checksum", migration.Checksum).Error; err != nil {
+			return fmt.Errorf("error recording checksum for %s: %v", record.Name, err)
+		}
+		slog.Info("Recorded baseline checksum for migration", "migration", record.Name)
+	}
+	return nil
+}
+
+func checksum(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,132 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	_ "github.com/jackc/pgx/v5/stdlib"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+)
+
+const (
+	replicaResolverName = "replica"
+	// Replica sağlık durumu bu süre boyunca önbellekte tutulur, her sorguda ping atılmaz
+	replicaHealthInterval = 5 * time.Second
+	replicaPingTimeout    = time.Second
+)
+
+// registerReplicas read replica'ları "replica" adlı resolver olarak kaydeder.
+// Primary bağlantı havuzu listenin sonuna eklenir; sağlıklı replica kalmazsa okuma sorguları primary'ye düşer.
+func registerReplicas(db *gorm.DB, config *Config) error {
+	primary, err := db.DB()
+	if err != nil {
+		return err
+	}
+
+	// Replica havuzları Close ile kapatılabilmesi için burada açılır
+	replicaConfig := *config
+	replicaConfig.URL = ""
+	dialectors := make([]gorm.Dialector, 0, len(config.ReplicaURLs)+1)
+	pools := make([]*sql.DB, 0, len(config.ReplicaURLs))
+	for _, replicaURL := range config.ReplicaURLs {
+		dsn, err := replicaConfig.withOptions(replicaURL)
+		if err != nil {
+			return err
+		}
+		pool, err := sql.Open("pgx", dsn)
+		if err != nil {
+			return err
+		}
+		pools = append(pools, pool)
+		dialectors = append(dialectors, postgres.New(postgres.Config{Conn: pool}))
+	}
+	replicaPools.Store(db.Config, pools)
+	dialectors = append(dialectors, postgres.New(postgres.Config{Conn: primary}))
+
+	resolver := dbresolver.Register(dbresolver.Config{
+		Replicas: dialectors,
+		Policy:   &fallbackPolicy{health: make(map[gorm.ConnPool]replicaHealth)},
+	}, replicaResolverName).
+		SetMaxOpenConns(config.MaxOpenConns).
+		SetMaxIdleConns(config.MaxIdleConns).
+		SetConnMaxLifetime(config.ConnMaxLifetime).
+		SetConnMaxIdleTime(config.ConnMaxIdleTime)
+
+	return db.Use(resolver)
+}
+
+// replicaPools bağlantıya (*gorm.Config) ait replica havuzlarıdır
+var replicaPools sync.Map
+
+// ReadOnly sorguyu read replica'ya yönlendirir. Replica tanımlı değilse db aynen döner.
+// Sadece tutarlılık gerektirmeyen okuma sorgularında (listeler, istatistikler) kullanılmalıdır.
+func ReadOnly(db *gorm.DB) *gorm.DB {
+	if _, ok := db.Config.Plugins[(&dbresolver.DBResolver{}).Name()]; !ok {
+		return db
+	}
+	return db.Clauses(dbresolver.Use(replicaResolverName))
+}
+
+type replicaHealth struct {
+	healthy   bool
+	checkedAt time.Time
+}
+
+// fallbackPolicy replica'lar arasında round-robin seçim yapar, ulaşılamayan replica'ları atlar.
+// Listenin son elemanı primary'dir ve hiçbir replica sağlıklı değilse kullanılır.
+type fallbackPolicy struct {
+	next   uint64
+	mu     sync.Mutex
+	health map[gorm.ConnPool]replicaHealth
+}
+
+func (p *fallbackPolicy) Resolve(connPools []gorm.ConnPool) gorm.ConnPool {
+	primary := connPools[len(connPools)-1]
+	replicas := connPools[:len(connPools)-1]
+	if len(replicas) == 0 {
+		return primary
+	}
+
+	start := int(atomic.AddUint64(&p.next, 1))
+	for i := range replicas {
+		replica := replicas[(start+i)%len(replicas)]
+		if p.healthy(replica) {
+			return replica
+		}
+	}
+	return primary
+}
+
+func (p *fallbackPolicy) healthy(connPool gorm.ConnPool) bool {
+	p.mu.Lock()
+	state, checked := p.health[connPool]
+	p.mu.Unlock()
+	if checked && time.Since(state.checkedAt) < replicaHealthInterval {
+		return state.healthy
+	}
+
+	healthy := true
+	if pinger, ok := connPool.(interface{ PingContext(context.Context) error }); ok {
+		ctx, cancel := context.WithTimeout(context.Background(), replicaPingTimeout)
+		err := pinger.PingContext(ctx)
+		cancel()
+		if err != nil {
+			healthy = false
+			// Sadece durum değiştiğinde loglanır
+			if !checked || state.healthy {
+				slog.Warn("Read replica unavailable, falling back", "error", err)
+			}
+		}
+	}
+
+	p.mu.Lock()
+	p.health[connPool] = replicaHealth{healthy: healthy, checkedAt: time.Now()}
+	p.mu.Unlock()
+	return healthy
+}
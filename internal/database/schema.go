@@ -0,0 +1,234 @@
+package database
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"prototurk/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// schemaModels migration'larla oluşturulan tablolara karşılık gelen GORM modelleridir
+var schemaModels = []interface{}{
+	&models.User{},
+	&models.Admin{},
+	&models.AdminPasswordHistory{},
+	&models.AdminInvitation{},
+	&models.AdminNetworkRule{},
+	&models.AdminSetupToken{},
+	&models.AuditLog{},
+	&models.ApprovalRequest{},
+	&MigrationRecord{},
+	&AppMetadata{},
+}
+
+// SchemaIssue GORM modeli ile veritabanı şeması arasındaki tek bir uyumsuzluktur
+type SchemaIssue struct {
+	Table   string
+	Column  string
+	Kind    string
+	Message string
+}
+
+const (
+	SchemaIssueMissingTable   = "missing_table"
+	SchemaIssueMissingColumn  = "missing_column"
+	SchemaIssueTypeMismatch   = "type_mismatch"
+	SchemaIssueMissingIndex   = "missing_index"
+	SchemaIssueUnmappedColumn = "unmapped_column"
+)
+
+type dbColumn struct {
+	ColumnName             string
+	DataType               string
+	UdtName                string
+	CharacterMaximumLength *int
+}
+
+// CheckSchema GORM model metadata'sını information_schema ile karşılaştırır.
+// Modelde olup veritabanında olmayan tablo, kolon ve index'ler ile tip farklılıklarını raporlar;
+// veritabanında olup modelde karşılığı olmayan kolonlar bilgi amaçlı listelenir.
+// Tamsayı kolonları genişlikten bağımsız (serial/integer/bigint) uyumlu kabul edilir.
+func CheckSchema(db *gorm.DB) ([]SchemaIssue, error) {
+	var issues []SchemaIssue
+
+	for _, model := range schemaModels {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(model); err != nil {
+			return nil, fmt.Errorf("error parsing model %T: %v", model, err)
+		}
+		table := stmt.Schema.Table
+
+		columns, err := tableColumns(db, table)
+		if err != nil {
+			return nil, err
+		}
+		if len(columns) == 0 {
+			issues = append(issues, SchemaIssue{Table: table, Kind: SchemaIssueMissingTable, Message: "table does not exist"})
+			continue
+		}
+
+		mapped := make(map[string]bool)
+		for _, field := range stmt.Schema.Fields {
+			if field.DBName == "" {
+				continue
+			}
+			mapped[field.DBName] = true
+
+			column, ok := columns[field.DBName]
+			if !ok {
+				issues = append(issues, SchemaIssue{Table: table, Column: field.DBName, Kind: SchemaIssueMissingColumn, Message: "column does not exist"})
+				continue
+			}
+
+			expected := normalizeColumnType(db.Dialector.DataTypeOf(field))
+			actual := normalizeColumnType(column.typeName())
+			if expected != actual {
+				issues = append(issues, SchemaIssue{
+					Table:   table,
+					Column:  field.DBName,
+					Kind:    SchemaIssueTypeMismatch,
+					Message: fmt.Sprintf("model type %s, database type %s", expected, actual),
+				})
+			}
+		}
+
+		for name := range columns {
+			if !mapped[name] {
+				issues = append(issues, SchemaIssue{Table: table, Column: name, Kind: SchemaIssueUnmappedColumn, Message: "column is not mapped by the model"})
+			}
+		}
+
+		indexIssues, err := checkIndexes(db, stmt.Schema)
+		if err != nil {
+			return nil, err
+		}
+		issues = append(issues, indexIssues...)
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].Table != issues[j].Table {
+			return issues[i].Table < issues[j].Table
+		}
+		return issues[i].Column < issues[j].Column
+	})
+	return issues, nil
+}
+
+// HasSchemaErrors bilgi amaçlı olanlar dışında uyumsuzluk olup olmadığını döner
+func HasSchemaErrors(issues []SchemaIssue) bool {
+	for _, issue := range issues {
+		if issue.Kind != SchemaIssueUnmappedColumn {
+			return true
+		}
+	}
+	return false
+}
+
+func tableColumns(db *gorm.DB, table string) (map[string]dbColumn, error) {
+	var rows []dbColumn
+	if err := db.Raw(`SELECT column_name, data_type, udt_name, character_maximum_length
+		FROM information_schema.columns
+		WHERE table_schema = current_schema() AND table_name = ?`, table).Scan(&rows).Error; err != nil {
+		return nil, fmt.Errorf("error reading columns of %s: %v", table, err)
+	}
+
+	columns := make(map[string]dbColumn, len(rows))
+	for _, row := range rows {
+		columns[row.ColumnName] = row
+	}
+	return columns, nil
+}
+
+// checkIndexes modelde tanımlı index'lerin ve unique alanların veritabanında karşılığı olup olmadığını kontrol eder.
+// Partial unique index'ler (örneğin soft delete için WHERE deleted_at IS NULL) unique alanı karşılar.
+func checkIndexes(db *gorm.DB, sch *schema.Schema) ([]SchemaIssue, error) {
+	var issues []SchemaIssue
+
+	var names []string
+	if err := db.Raw(`SELECT indexname FROM pg_indexes WHERE schemaname = current_schema() AND tablename = ?`, sch.Table).
+		Scan(&names).Error; err != nil {
+		return nil, fmt.Errorf("error reading indexes of %s: %v", sch.Table, err)
+	}
+	existing := make(map[string]bool, len(names))
+	for _, name := range names {
+		existing[name] = true
+	}
+
+	for name, index := range sch.ParseIndexes() {
+		if existing[name] {
+			continue
+		}
+		column := ""
+		if len(index.Fields) > 0 {
+			column = index.Fields[0].DBName
+		}
+		issues = append(issues, SchemaIssue{Table: sch.Table, Column: column, Kind: SchemaIssueMissingIndex, Message: fmt.Sprintf("index %s does not exist", name)})
+	}
+
+	var uniqueColumns []string
+	if err := db.Raw(`SELECT a.attname
+		FROM pg_index i
+		JOIN pg_class t ON t.oid = i.indrelid
+		JOIN pg_namespace n ON n.oid = t.relnamespace
+		JOIN pg_attribute a ON a.attrelid = t.oid AND a.attnum = i.indkey[0]
+		WHERE n.nspname = current_schema() AND t.relname = ? AND i.indisunique AND i.indnatts = 1`, sch.Table).
+		Scan(&uniqueColumns).Error; err != nil {
+		return nil, fmt.Errorf("error reading unique indexes of %s: %v", sch.Table, err)
+	}
+	unique := make(map[string]bool, len(uniqueColumns))
+	for _, column := range uniqueColumns {
+		unique[column] = true
+	}
+
+	for _, field := range sch.Fields {
+		if field.Unique && !field.PrimaryKey && !unique[field.DBName] {
+			issues = append(issues, SchemaIssue{Table: sch.Table, Column: field.DBName, Kind: SchemaIssueMissingIndex, Message: "unique index does not exist"})
+		}
+	}
+	return issues, nil
+}
+
+// typeName information_schema kaydını Postgres tip adına çevirir
+func (c dbColumn) typeName() string {
+	switch c.DataType {
+	case "USER-DEFINED":
+		return c.UdtName
+	case "character varying":
+		if c.CharacterMaximumLength != nil {
+			return fmt.Sprintf("varchar(%d)", *c.CharacterMaximumLength)
+		}
+		return "varchar"
+	}
+	return c.DataType
+}
+
+var whitespace = regexp.MustCompile(`\s+`)
+
+// normalizeColumnType model ve veritabanı tarafındaki eşdeğer tip yazımlarını aynı biçime getirir
+func normalizeColumnType(t string) string {
+	t = whitespace.ReplaceAllString(strings.ToLower(strings.TrimSpace(t)), " ")
+	t = strings.ReplaceAll(t, " (", "(")
+
+	switch t {
+	case "smallint", "integer", "bigint", "smallserial", "serial", "bigserial", "int2", "int4", "int8":
+		return "integer"
+	case "timestamptz", "timestamp with time zone":
+		return "timestamptz"
+	case "timestamp", "timestamp without time zone":
+		return "timestamp"
+	case "bool", "boolean":
+		return "boolean"
+	case "decimal", "numeric":
+		return "numeric"
+	}
+
+	if strings.HasPrefix(t, "character varying") {
+		return "varchar" + strings.TrimPrefix(t, "character varying")
+	}
+	return t
+}
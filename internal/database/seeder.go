@@ -1,40 +1,136 @@
 package database
 
 import (
-	"log"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"sync"
+	"time"
 
 	"prototurk/internal/models"
+	"prototurk/pkg/utils"
 
 	"golang.org/x/crypto/bcrypt"
 	"gorm.io/gorm"
 )
 
-// SeedDefaultAdmin varsayılan super admin'i ekler
-func SeedDefaultAdmin(db *gorm.DB) error {
+// setupTokenTTL log'a yazılan kurulum token'ının geçerlilik süresidir
+const setupTokenTTL = 24 * time.Hour
+
+// KnownDefaultPasswords production ortamında aktif bir admin tarafından kullanılmaması gereken parolalardır
+var KnownDefaultPasswords = []string{"123123", "123456", "12345678", "password", "admin", "admin123"}
+
+type BootstrapConfig struct {
+	Email    string
+	Name     string
+	Password string
+}
+
+// BootstrapAdmin hiç admin yoksa ilk super admin'i oluşturur.
+// Email ve parola verilmişse admin doğrudan oluşturulur ve ilk girişte parolasını değiştirmesi istenir,
+// verilmemişse /api/admin/setup için tek kullanımlık bir kurulum token'ı log'a yazılır.
+func BootstrapAdmin(db *gorm.DB, config *BootstrapConfig) error {
 	var count int64
-	db.Model(&models.Admin{}).Count(&count)
+	if err := db.Unscoped().Model(&models.Admin{}).Count(&count).Error; err != nil {
+		return err
+	}
 	if count > 0 {
 		return nil // Eğer admin varsa ekleme
 	}
 
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte("123123"), bcrypt.DefaultCost)
+	if config.Email == "" || config.Password == "" {
+		return createSetupToken(db)
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(config.Password), bcrypt.DefaultCost)
 	if err != nil {
 		return err
 	}
 
+	name := config.Name
+	if name == "" {
+		name = "Super Admin"
+	}
+
+	now := utils.Now()
 	admin := models.Admin{
-		Email:    "tayfunerbilen@gmail.com",
-		Name:     "Tayfun Erbilen",
-		Password: string(hashedPassword),
-		Role:     models.AdminRoleSuperAdmin,
-		Status:   models.AdminStatusActive,
-		// LastLogin alanını boş bırak, ilk girişte güncellenecek
+		Email:              config.Email,
+		Name:               name,
+		Password:           string(hashedPassword),
+		Role:               models.AdminRoleSuperAdmin,
+		Status:             models.AdminStatusActive,
+		MustChangePassword: true,
+		PasswordChangedAt:  &now,
 	}
 
 	if err := db.Create(&admin).Error; err != nil {
 		return err
 	}
 
-	log.Println("Default super admin created successfully!")
+	slog.Info("Initial super admin created, password must be changed on first login", "email", admin.Email)
+	return nil
+}
+
+// createSetupToken önceki kullanılmamış token'ları geçersiz kılar ve yenisini log'a yazar
+func createSetupToken(db *gorm.DB) error {
+	token, err := utils.RandomToken(32)
+	if err != nil {
+		return err
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("used_at IS NULL").Delete(&models.AdminSetupToken{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.AdminSetupToken{
+			TokenHash: utils.HashToken(token),
+			ExpiresAt: utils.Now().Add(setupTokenTTL),
+		}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("error creating setup token: %v", err)
+	}
+
+	slog.Warn("No admin exists. Create the initial super admin with POST /api/admin/setup using the setup token", "setup_token", token, "valid_for", setupTokenTTL.String())
 	return nil
 }
+
+// FindDefaultPasswordAdmins bilinen varsayılan parolalardan birini kullanan aktif adminleri döner. Parola geçmişi
+// veya must_change_password bayrağı parolanın değiştirildiğini garanti etmediği için tüm aktif adminlerin hash'i
+// kontrol edilir. Her karşılaştırma bir bcrypt hesaplaması olduğundan adminler paralel kontrol edilir.
+func FindDefaultPasswordAdmins(db *gorm.DB) ([]models.Admin, error) {
+	var admins []models.Admin
+	if err := db.Where("status = ?", models.AdminStatusActive).Order("id ASC").Find(&admins).Error; err != nil {
+		return nil, err
+	}
+
+	matched := make([]bool, len(admins))
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, runtime.NumCPU())
+	for i := range admins {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer func() { <-sem; wg.Done() }()
+			matched[i] = usesDefaultPassword(admins[i].Password)
+		}(i)
+	}
+	wg.Wait()
+
+	var matches []models.Admin
+	for i, admin := range admins {
+		if matched[i] {
+			matches = append(matches, admin)
+		}
+	}
+	return matches, nil
+}
+
+func usesDefaultPassword(hash string) bool {
+	for _, password := range KnownDefaultPasswords {
+		if bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil {
+			return true
+		}
+	}
+	return false
+}
@@ -1,26 +1,40 @@
 package handlers
 
 import (
+	"errors"
+	"log/slog"
 	"net/http"
 	"strconv"
 	"time"
 
+	"prototurk/internal/audit"
+	"prototurk/internal/metrics"
+	"prototurk/internal/middleware"
 	"prototurk/internal/models"
+	"prototurk/internal/service"
 	"prototurk/pkg/response"
 	"prototurk/pkg/utils"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
-	"golang.org/x/crypto/bcrypt"
-	"gorm.io/gorm"
+)
+
+const (
+	adminTokenTTL          = time.Hour * 24 * 7 // 7 days
+	passwordChangeTokenTTL = time.Minute * 15
 )
 
 type AdminHandler struct {
-	db *gorm.DB
+	admins       *service.AdminService
+	approvals    *service.ApprovalService
+	audit        audit.Recorder
+	network      middleware.AdminNetworkChecker
+	reauthWindow time.Duration
+	jwtSecret    []byte
 }
 
-func NewAdminHandler(db *gorm.DB) *AdminHandler {
-	return &AdminHandler{db: db}
+func NewAdminHandler(admins *service.AdminService, approvals *service.ApprovalService, recorder audit.Recorder, network middleware.AdminNetworkChecker, reauthWindow time.Duration, jwtSecret []byte) *AdminHandler {
+	return &AdminHandler{admins: admins, approvals: approvals, audit: recorder, network: network, reauthWindow: reauthWindow, jwtSecret: jwtSecret}
 }
 
 // Create yeni bir admin oluşturur (Sadece super admin yapabilir)
@@ -37,51 +51,9 @@ func (h *AdminHandler) Create(c *gin.Context) {
 		return
 	}
 
-	// Role ve status validasyonu
-	if !req.Role.ValidateRole() {
-		c.JSON(http.StatusBadRequest, response.Error("VALIDATION_ERROR", "Invalid admin role", nil))
-		return
-	}
-
-	// İlk super admin'in rolünü kontrol et
-	var firstSuperAdmin models.Admin
-	if err := h.db.Where("role = ?", models.AdminRoleSuperAdmin).Order("created_at ASC").First(&firstSuperAdmin).Error; err == nil {
-		// Eğer ilk super admin varsa ve yeni admin super admin olarak oluşturulmaya çalışılıyorsa
-		if req.Role == models.AdminRoleSuperAdmin {
-			c.JSON(http.StatusForbidden, response.Error("FORBIDDEN", "Cannot create another super admin while first super admin exists", nil))
-			return
-		}
-	}
-
-	if !req.Status.ValidateStatus() {
-		c.JSON(http.StatusBadRequest, response.Error("VALIDATION_ERROR", "Invalid admin status", nil))
-		return
-	}
-
-	// Email kontrolü - sadece aktif (silinmemiş) admin'lerde kontrol et
-	var existingAdmin models.Admin
-	if err := h.db.Unscoped().Where("email = ? AND deleted_at IS NULL", req.Email).First(&existingAdmin).Error; err == nil {
-		c.JSON(http.StatusConflict, response.Error("EMAIL_EXISTS", "Email already exists", nil))
-		return
-	}
-
-	// Parolayı hashle
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	newAdmin, err := h.admins.Create(c.Request.Context(), &admin, req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, response.Error("SERVER_ERROR", "Error processing request", nil))
-		return
-	}
-
-	newAdmin := models.Admin{
-		Email:    req.Email,
-		Name:     req.Name,
-		Password: string(hashedPassword),
-		Role:     req.Role,
-		Status:   req.Status,
-	}
-
-	if err := h.db.Create(&newAdmin).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, response.Error("SERVER_ERROR", "Error creating admin", nil))
+		writeAdminError(c, err, "Error creating admin")
 		return
 	}
 
@@ -90,8 +62,8 @@ func (h *AdminHandler) Create(c *gin.Context) {
 
 // List tüm adminleri listeler
 func (h *AdminHandler) List(c *gin.Context) {
-	var admins []models.Admin
-	if err := h.db.Find(&admins).Error; err != nil {
+	admins, err := h.admins.List(c.Request.Context())
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, response.Error("SERVER_ERROR", "Error listing admins", nil))
 		return
 	}
@@ -101,11 +73,14 @@ func (h *AdminHandler) List(c *gin.Context) {
 
 // Get tek bir admin'i getirir
 func (h *AdminHandler) Get(c *gin.Context) {
-	id := c.Param("id")
+	id, ok := adminIDParam(c)
+	if !ok {
+		return
+	}
 
-	var admin models.Admin
-	if err := h.db.First(&admin, id).Error; err != nil {
-		c.JSON(http.StatusNotFound, response.Error("NOT_FOUND", "Admin not found", nil))
+	admin, err := h.admins.Get(c.Request.Context(), id)
+	if err != nil {
+		writeAdminError(c, err, "Error getting admin")
 		return
 	}
 
@@ -115,23 +90,14 @@ func (h *AdminHandler) Get(c *gin.Context) {
 // Update admin bilgilerini günceller
 func (h *AdminHandler) Update(c *gin.Context) {
 	currentAdmin := c.MustGet("admin").(models.Admin)
-	id := c.Param("id")
-
-	var admin models.Admin
-	if err := h.db.First(&admin, id).Error; err != nil {
-		c.JSON(http.StatusNotFound, response.Error("NOT_FOUND", "Admin not found", nil))
+	id, ok := adminIDParam(c)
+	if !ok {
 		return
 	}
 
-	// İlk super admin'i güncellemeye çalışıyorsa ve kendisi değilse engelle
-	if admin.IsFirstSuperAdmin(h.db) && currentAdmin.ID != admin.ID {
-		c.JSON(http.StatusForbidden, response.Error("FORBIDDEN", "Cannot update first super admin", nil))
-		return
-	}
-
-	// Sadece super admin başka bir admin'i güncelleyebilir
-	if currentAdmin.ID != admin.ID && currentAdmin.Role != models.AdminRoleSuperAdmin {
-		c.JSON(http.StatusForbidden, response.Error("FORBIDDEN", "Super admin permission required", nil))
+	admin, err := h.admins.AuthorizeUpdate(c.Request.Context(), &currentAdmin, id)
+	if err != nil {
+		writeAdminError(c, err, "Error updating admin")
 		return
 	}
 
@@ -141,75 +107,30 @@ func (h *AdminHandler) Update(c *gin.Context) {
 		return
 	}
 
-	updates := make(map[string]interface{})
-
-	if req.Email != "" && req.Email != admin.Email {
-		var existingAdmin models.Admin
-		if err := h.db.Unscoped().Where("email = ? AND deleted_at IS NULL AND id != ?", req.Email, id).First(&existingAdmin).Error; err == nil {
-			c.JSON(http.StatusConflict, response.Error("EMAIL_EXISTS", "Email already exists", nil))
-			return
-		}
-		updates["email"] = req.Email
-	}
-
-	if req.Name != "" {
-		updates["name"] = req.Name
-	}
-
-	if req.Password != "" {
-		hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
-		if err != nil {
-			c.JSON(http.StatusInternalServerError, response.Error("SERVER_ERROR", "Error processing request", nil))
-			return
-		}
-		updates["password"] = string(hashedPassword)
-	}
-
-	// Role ve status güncellemelerini kontrol et
-	if req.Role != "" {
-		// İlk super admin rolünü değiştirmeye çalışıyorsa engelle
-		if admin.IsFirstSuperAdmin(h.db) {
-			c.JSON(http.StatusForbidden, response.Error("FORBIDDEN", "First super admin role cannot be changed", nil))
-			return
-		}
-
-		// Kendisi super admin değilse veya kendi rolünü değiştirmeye çalışıyorsa engelle
-		if !currentAdmin.CanUpdateRole() || (currentAdmin.ID == admin.ID && currentAdmin.Role != models.AdminRoleSuperAdmin) {
-			c.JSON(http.StatusForbidden, response.Error("FORBIDDEN", "Cannot update role", nil))
-			return
-		}
-		if !req.Role.ValidateRole() {
-			c.JSON(http.StatusBadRequest, response.Error("VALIDATION_ERROR", "Invalid admin role", nil))
-			return
-		}
-		updates["role"] = req.Role
+	result, err := h.admins.Update(c.Request.Context(), &currentAdmin, admin, req, service.AdminUpdateOptions{
+		Reauthenticated: middleware.RecentlyReauthenticated(c, h.reauthWindow),
+		DeferRoleChange: h.approvals.Requires(models.ApprovalActionAdminRoleChange),
+	})
+	if err != nil {
+		writeAdminError(c, err, "Error updating admin")
+		return
 	}
 
-	if req.Status != "" {
-		// İlk super admin statusünü değiştirmeye çalışıyorsa engelle
-		if admin.IsFirstSuperAdmin(h.db) {
-			c.JSON(http.StatusForbidden, response.Error("FORBIDDEN", "First super admin status cannot be changed", nil))
-			return
-		}
-
-		// Kendisi super admin değilse veya kendi statusünü değiştirmeye çalışıyorsa engelle
-		if !currentAdmin.CanUpdateStatus() || (currentAdmin.ID == admin.ID && currentAdmin.Role != models.AdminRoleSuperAdmin) {
-			c.JSON(http.StatusForbidden, response.Error("FORBIDDEN", "Cannot update status", nil))
-			return
-		}
-		if !req.Status.ValidateStatus() {
-			c.JSON(http.StatusBadRequest, response.Error("VALIDATION_ERROR", "Invalid admin status", nil))
-			return
-		}
-		updates["status"] = req.Status
+	if result.DeferredRole != "" {
+		requestApproval(c, h.approvals, h.audit, models.ApprovalActionAdminRoleChange, "admin", admin.ID, map[string]interface{}{
+			"from": result.PreviousRole,
+			"role": result.DeferredRole,
+		})
+		return
 	}
 
-	if err := h.db.Model(&admin).Updates(updates).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, response.Error("SERVER_ERROR", "Error updating admin", nil))
-		return
+	if result.RoleChanged {
+		h.audit.Record(c, models.AuditActionAdminRoleChange, "admin", admin.ID, map[string]interface{}{
+			"role": result.Admin.Role,
+		})
 	}
 
-	c.JSON(http.StatusOK, response.Success(admin))
+	c.JSON(http.StatusOK, response.Success(result.Admin))
 }
 
 // Delete bir admin'i siler (Sadece super admin yapabilir)
@@ -220,36 +141,32 @@ func (h *AdminHandler) Delete(c *gin.Context) {
 		return
 	}
 
-	id := c.Param("id")
-	idUint, err := strconv.ParseUint(id, 10, 32)
+	idUint, err := strconv.ParseUint(c.Param("id"), 10, 32)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, response.Error("VALIDATION_ERROR", "Invalid admin ID", nil))
 		return
 	}
 
-	// Admin'in kendisini silmesini engelle
-	if admin.ID == uint(idUint) {
-		c.JSON(http.StatusForbidden, response.Error("FORBIDDEN", "You cannot delete yourself", nil))
-		return
-	}
-
-	var targetAdmin models.Admin
-	if err := h.db.First(&targetAdmin, idUint).Error; err != nil {
-		c.JSON(http.StatusNotFound, response.Error("NOT_FOUND", "Admin not found", nil))
+	targetAdmin, err := h.admins.AuthorizeDelete(c.Request.Context(), &admin, uint(idUint))
+	if err != nil {
+		writeAdminError(c, err, "Error deleting admin")
 		return
 	}
 
-	// İlk super admin'i silmeye çalışıyorsa engelle
-	if targetAdmin.IsFirstSuperAdmin(h.db) {
-		c.JSON(http.StatusForbidden, response.Error("FORBIDDEN", "Cannot delete first super admin", nil))
+	if h.approvals.Requires(models.ApprovalActionAdminDelete) {
+		requestApproval(c, h.approvals, h.audit, models.ApprovalActionAdminDelete, "admin", targetAdmin.ID, nil)
 		return
 	}
 
-	if err := h.db.Delete(&targetAdmin).Error; err != nil {
+	if err := h.admins.Delete(c.Request.Context(), targetAdmin); err != nil {
 		c.JSON(http.StatusInternalServerError, response.Error("SERVER_ERROR", "Error deleting admin", nil))
 		return
 	}
 
+	h.audit.Record(c, models.AuditActionAdminDelete, "admin", targetAdmin.ID, map[string]interface{}{
+		"email": targetAdmin.Email,
+	})
+
 	c.JSON(http.StatusOK, response.Success(gin.H{"message": "Admin deleted successfully"}))
 }
 
@@ -261,46 +178,140 @@ func (h *AdminHandler) Login(c *gin.Context) {
 		return
 	}
 
-	var admin models.Admin
-	if err := h.db.Where("email = ?", req.Email).First(&admin).Error; err != nil {
-		c.JSON(http.StatusUnauthorized, response.Error("INVALID_CREDENTIALS", "Invalid email or password", nil))
+	admin, err := h.admins.Authenticate(c.Request.Context(), req.Email, req.Password, req.Code)
+	if err != nil {
+		metrics.LoginFailed(metrics.SubjectAdmin, adminLoginFailureReason(err))
+		writeAdminError(c, err, "Error processing request")
 		return
 	}
 
-	if !admin.IsActive() {
-		c.JSON(http.StatusForbidden, response.Error("ACCOUNT_INACTIVE", "Admin account is not active", nil))
+	// Admin bazlı IP allowlist'ini kontrol et (global liste middleware'de uygulanır)
+	if !h.network.CheckAdmin(c, admin.ID) {
+		metrics.LoginFailed(metrics.SubjectAdmin, "network_denied")
 		return
 	}
 
-	if err := bcrypt.CompareHashAndPassword([]byte(admin.Password), []byte(req.Password)); err != nil {
-		c.JSON(http.StatusUnauthorized, response.Error("INVALID_CREDENTIALS", "Invalid email or password", nil))
-		return
+	// Son giriş tarihini güncelle
+	if err := h.admins.RecordLogin(c.Request.Context(), admin); err != nil {
+		slog.ErrorContext(c.Request.Context(), "Error updating last login of admin", "admin_id", admin.ID, "error", err)
 	}
 
-	// Son giriş tarihini güncelle
-	h.db.Model(&admin).Update("last_login", utils.Now())
+	// Parolasını değiştirmesi gereken veya parolasının süresi dolmuş admin sadece parola değiştirebilen kısıtlı bir token alır
+	if h.admins.RequiresPasswordChange(admin) {
+		tokenString, err := h.issueToken(c, *admin, models.AdminTokenScopePasswordChange, passwordChangeTokenTTL)
+		if err != nil {
+			metrics.LoginFailed(metrics.SubjectAdmin, "error")
+			c.JSON(http.StatusInternalServerError, response.Error("SERVER_ERROR", "Error generating token", nil))
+			return
+		}
 
-	// JWT token oluştur
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"admin_id": admin.ID,
-		"role":     admin.Role,
-		"exp":      time.Now().Add(time.Hour * 24 * 7).Unix(), // 7 days
-	})
+		metrics.LoginSucceeded(metrics.SubjectAdmin)
+		c.JSON(http.StatusOK, response.Success(gin.H{
+			"token":                tokenString,
+			"admin":                admin,
+			"must_change_password": true,
+		}))
+		return
+	}
 
-	tokenString, err := token.SignedString([]byte(c.MustGet("jwt_secret").(string)))
+	tokenString, err := h.issueToken(c, *admin, "", adminTokenTTL)
 	if err != nil {
+		metrics.LoginFailed(metrics.SubjectAdmin, "error")
 		c.JSON(http.StatusInternalServerError, response.Error("SERVER_ERROR", "Error generating token", nil))
 		return
 	}
 
+	metrics.LoginSucceeded(metrics.SubjectAdmin)
 	c.JSON(http.StatusOK, response.Success(gin.H{
 		"token": tokenString,
 		"admin": admin,
 	}))
 }
 
+// adminLoginFailureReason giriş hatasını metrik etiketi olarak kullanılabilecek sabit bir sebebe çevirir
+func adminLoginFailureReason(err error) string {
+	switch {
+	case errors.Is(err, service.ErrInvalidCredentials):
+		return "invalid_credentials"
+	case errors.Is(err, models.ErrAdminNotActive):
+		return "inactive"
+	case errors.Is(err, service.ErrTwoFactorRequired):
+		return "two_factor_required"
+	case errors.Is(err, service.ErrInvalidTwoFactorCode):
+		return "invalid_two_factor_code"
+	default:
+		return "error"
+	}
+}
+
+// issueToken admin için JWT token oluşturur. scope boş değilse token sadece o kapsamda kullanılabilir.
+func (h *AdminHandler) issueToken(c *gin.Context, admin models.Admin, scope string, ttl time.Duration) (string, error) {
+	extra := map[string]interface{}{}
+	if scope != "" {
+		extra["scope"] = scope
+	}
+	return h.issueTokenWithClaims(c, admin, ttl, extra)
+}
+
+// issueTokenWithClaims standart admin claim'lerine ek claim'ler ekleyerek token oluşturur
+func (h *AdminHandler) issueTokenWithClaims(c *gin.Context, admin models.Admin, ttl time.Duration, extra map[string]interface{}) (string, error) {
+	claims := jwt.MapClaims{
+		"admin_id":                admin.ID,
+		"role":                    admin.Role,
+		middleware.TokenTypeClaim: middleware.TokenTypeAdmin,
+		"exp":                     utils.Now().Add(ttl).Unix(),
+	}
+	for k, v := range extra {
+		claims[k] = v
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	return token.SignedString(h.jwtSecret)
+}
+
 // Me giriş yapmış admin bilgilerini getirir
 func (h *AdminHandler) Me(c *gin.Context) {
 	admin := c.MustGet("admin").(models.Admin)
 	c.JSON(http.StatusOK, response.Success(admin))
 }
+
+// adminIDParam path'teki admin ID'sini okur. Geçersiz ID'ler bulunamayan admin gibi yanıtlanır.
+func adminIDParam(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusNotFound, response.Error("NOT_FOUND", "Admin not found", nil))
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// writeAdminError AdminService hatalarını HTTP yanıtına çevirir. Bilinmeyen hatalar serverMessage ile 500 döner.
+func writeAdminError(c *gin.Context, err error, serverMessage string) {
+	var permissionErr *service.PermissionError
+	switch {
+	case errors.As(err, &permissionErr):
+		c.JSON(http.StatusForbidden, response.Error("FORBIDDEN", permissionErr.Message, nil))
+	case errors.Is(err, models.ErrAdminNotFound):
+		c.JSON(http.StatusNotFound, response.Error("NOT_FOUND", "Admin not found", nil))
+	case errors.Is(err, models.ErrInvalidRole):
+		c.JSON(http.StatusBadRequest, response.Error("VALIDATION_ERROR", "Invalid admin role", nil))
+	case errors.Is(err, models.ErrInvalidStatus):
+		c.JSON(http.StatusBadRequest, response.Error("VALIDATION_ERROR", "Invalid admin status", nil))
+	case errors.Is(err, models.ErrEmailExists):
+		c.JSON(http.StatusConflict, response.Error("EMAIL_EXISTS", "Email already exists", nil))
+	case errors.Is(err, models.ErrPasswordReused):
+		c.JSON(http.StatusBadRequest, response.Error("PASSWORD_REUSED", "New password must be different from recent passwords", nil))
+	case errors.Is(err, service.ErrReauthRequired):
+		c.JSON(http.StatusForbidden, response.Error("REAUTH_REQUIRED", "Recent re-authentication required", nil))
+	case errors.Is(err, service.ErrInvalidCredentials):
+		c.JSON(http.StatusUnauthorized, response.Error("INVALID_CREDENTIALS", "Invalid email or password", nil))
+	case errors.Is(err, models.ErrAdminNotActive):
+		c.JSON(http.StatusForbidden, response.Error("ACCOUNT_INACTIVE", "Admin account is not active", nil))
+	case errors.Is(err, service.ErrTwoFactorRequired):
+		c.JSON(http.StatusUnauthorized, response.Error("TWO_FACTOR_REQUIRED", "Two-factor code required", nil))
+	case errors.Is(err, service.ErrInvalidTwoFactorCode):
+		c.JSON(http.StatusUnauthorized, response.Error("INVALID_TWO_FACTOR_CODE", "Invalid two-factor code", nil))
+	default:
+		c.JSON(http.StatusInternalServerError, response.Error("SERVER_ERROR", serverMessage, nil))
+	}
+}
@@ -0,0 +1,258 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"prototurk/internal/audit"
+	"prototurk/internal/models"
+	"prototurk/internal/service"
+	"prototurk/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminApprovalHandler struct {
+	approvals *service.ApprovalService
+	audit     audit.Recorder
+}
+
+func NewAdminApprovalHandler(approvals *service.ApprovalService, recorder audit.Recorder) *AdminApprovalHandler {
+	return &AdminApprovalHandler{approvals: approvals, audit: recorder}
+}
+
+// List onay isteklerini listeler, status query parametresi ile filtrelenebilir (Sadece super admin yapabilir)
+func (h *AdminApprovalHandler) List(c *gin.Context) {
+	admin := c.MustGet("admin").(models.Admin)
+
+	requests, err := h.approvals.List(c.Request.Context(), &admin, models.ApprovalStatus(c.Query("status")))
+	if err != nil {
+		writeApprovalError(c, err, "Error listing approval requests")
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(requests))
+}
+
+// Get tek bir onay isteğini döner (Super admin veya isteği oluşturan admin görebilir)
+func (h *AdminApprovalHandler) Get(c *gin.Context) {
+	admin := c.MustGet("admin").(models.Admin)
+
+	id, ok := approvalIDParam(c)
+	if !ok {
+		return
+	}
+
+	request, err := h.approvals.Get(c.Request.Context(), &admin, id)
+	if err != nil {
+		writeApprovalError(c, err, "Error getting approval request")
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(request))
+}
+
+// Approve isteği onaylar ve ertelenmiş işlemi çalıştırır.
+// İsteği oluşturan admin kendi isteğini onaylayamaz.
+func (h *AdminApprovalHandler) Approve(c *gin.Context) {
+	admin := c.MustGet("admin").(models.Admin)
+	if !admin.CanReviewApprovals() {
+		c.JSON(http.StatusForbidden, response.Error("FORBIDDEN", "Super admin permission required", nil))
+		return
+	}
+
+	var req models.ReviewApprovalRequest
+	if !bindOptionalJSON(c, &req) {
+		return
+	}
+
+	id, ok := approvalIDParam(c)
+	if !ok {
+		return
+	}
+
+	request, err := h.approvals.Approve(c.Request.Context(), &admin, id, req.Reason)
+	var execErr *service.ApprovalExecutionError
+	if errors.As(err, &execErr) {
+		details := approvalAuditDetails(*request)
+		details["error"] = execErr.Error()
+		h.audit.Record(c, models.AuditActionApprovalFail, "approval_request", request.ID, details)
+		c.JSON(http.StatusConflict, response.Error("APPROVAL_FAILED", "Approved action could not be executed", request))
+		return
+	}
+	if err != nil {
+		writeApprovalError(c, err, "Error updating approval request")
+		return
+	}
+
+	details := approvalAuditDetails(*request)
+	h.audit.Record(c, models.AuditActionApprovalApprove, "approval_request", request.ID, details)
+	h.audit.Record(c, approvalAuditAction(request.Action), request.TargetType, request.TargetID, details)
+
+	c.JSON(http.StatusOK, response.Success(request))
+}
+
+// Reject isteği reddeder, ertelenmiş işlem çalıştırılmaz
+func (h *AdminApprovalHandler) Reject(c *gin.Context) {
+	admin := c.MustGet("admin").(models.Admin)
+	if !admin.CanReviewApprovals() {
+		c.JSON(http.StatusForbidden, response.Error("FORBIDDEN", "Super admin permission required", nil))
+		return
+	}
+
+	var req models.ReviewApprovalRequest
+	if !bindOptionalJSON(c, &req) {
+		return
+	}
+
+	id, ok := approvalIDParam(c)
+	if !ok {
+		return
+	}
+
+	request, err := h.approvals.Reject(c.Request.Context(), &admin, id, req.Reason)
+	if err != nil {
+		writeApprovalError(c, err, "Error updating approval request")
+		return
+	}
+
+	h.audit.Record(c, models.AuditActionApprovalReject, "approval_request", request.ID, approvalAuditDetails(*request))
+
+	c.JSON(http.StatusOK, response.Success(request))
+}
+
+// Expire bekleyen bir isteği süresini beklemeden geçersiz kılar (Super admin veya isteği oluşturan admin yapabilir)
+func (h *AdminApprovalHandler) Expire(c *gin.Context) {
+	admin := c.MustGet("admin").(models.Admin)
+
+	id, ok := approvalIDParam(c)
+	if !ok {
+		return
+	}
+
+	request, err := h.approvals.Expire(c.Request.Context(), &admin, id)
+	if err != nil {
+		writeApprovalError(c, err, "Error updating approval request")
+		return
+	}
+
+	h.audit.Record(c, models.AuditActionApprovalExpire, "approval_request", request.ID, approvalAuditDetails(*request))
+
+	c.JSON(http.StatusOK, response.Success(request))
+}
+
+// approvalIDParam path'teki onay isteği ID'sini okur. Geçersiz ID'ler bulunamayan istek gibi yanıtlanır.
+func approvalIDParam(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusNotFound, response.Error("NOT_FOUND", "Approval request not found", nil))
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// writeApprovalError ApprovalService hatalarını HTTP yanıtına çevirir. Bilinmeyen hatalar serverMessage ile 500 döner.
+func writeApprovalError(c *gin.Context, err error, serverMessage string) {
+	var permissionErr *service.PermissionError
+	switch {
+	case errors.As(err, &permissionErr):
+		c.JSON(http.StatusForbidden, response.Error("FORBIDDEN", permissionErr.Message, nil))
+	case errors.Is(err, models.ErrApprovalNotFound):
+		c.JSON(http.StatusNotFound, response.Error("NOT_FOUND", "Approval request not found", nil))
+	case errors.Is(err, service.ErrSelfApproval):
+		c.JSON(http.StatusForbidden, response.Error("SELF_APPROVAL_FORBIDDEN", "Approval requests must be reviewed by another admin", nil))
+	case errors.Is(err, service.ErrApprovalExpired):
+		c.JSON(http.StatusConflict, response.Error("APPROVAL_EXPIRED", "Approval request has expired", nil))
+	case errors.Is(err, service.ErrApprovalNotPending):
+		c.JSON(http.StatusConflict, response.Error("APPROVAL_NOT_PENDING", "Approval request is not pending", nil))
+	default:
+		c.JSON(http.StatusInternalServerError, response.Error("SERVER_ERROR", serverMessage, nil))
+	}
+}
+
+// requestApproval işlemi ertelemek için bir onay isteği oluşturur ve 202 yanıtını yazar.
+// Aynı hedef için bekleyen bir istek varsa APPROVAL_PENDING döner.
+func requestApproval(c *gin.Context, approvals *service.ApprovalService, recorder audit.Recorder, action models.ApprovalAction, targetType string, targetID uint, payload map[string]interface{}) {
+	requester := c.MustGet("admin").(models.Admin)
+
+	request, err := approvals.Request(c.Request.Context(), &requester, action, targetType, targetID, payload)
+	writeApprovalRequested(c, recorder, request, err)
+}
+
+// requestSetApproval birden fazla hedefi etkileyen işlem için onay isteği oluşturur ve yanıtı yazar
+func requestSetApproval(c *gin.Context, approvals *service.ApprovalService, recorder audit.Recorder, action models.ApprovalAction, targetType string, ids []uint, payload map[string]interface{}) {
+	requester := c.MustGet("admin").(models.Admin)
+
+	request, err := approvals.RequestSet(c.Request.Context(), &requester, action, targetType, ids, payload)
+	writeApprovalRequested(c, recorder, request, err)
+}
+
+// writeApprovalRequested oluşturulan istek için 202, aynı hedefte bekleyen bir istek varsa APPROVAL_PENDING yazar
+func writeApprovalRequested(c *gin.Context, recorder audit.Recorder, request *models.ApprovalRequest, err error) {
+	var pending *service.ApprovalPendingError
+	if errors.As(err, &pending) {
+		// Başka bir admin'in bekleyen isteğinin sadece ID'si ve durumu döner
+		var details interface{} = gin.H{"id": pending.ID, "status": pending.Status}
+		if pending.Request != nil {
+			details = pending.Request
+		}
+		c.JSON(http.StatusConflict, response.Error("APPROVAL_PENDING", "An approval request for this action is already pending", details))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.Error("SERVER_ERROR", "Error creating approval request", nil))
+		return
+	}
+
+	recorder.Record(c, models.AuditActionApprovalRequest, "approval_request", request.ID, approvalAuditDetails(*request))
+
+	c.JSON(http.StatusAccepted, response.Success(gin.H{
+		"message":          "Action requires approval by another super admin",
+		"approval_request": request,
+	}))
+}
+
+// approvalAuditAction onaylanan işlemin kendi audit action'ını döner
+func approvalAuditAction(action models.ApprovalAction) string {
+	switch action {
+	case models.ApprovalActionAdminDelete:
+		return models.AuditActionAdminDelete
+	case models.ApprovalActionAdminPurge:
+		return models.AuditActionAdminPurge
+	case models.ApprovalActionAdminRoleChange:
+		return models.AuditActionAdminRoleChange
+	case models.ApprovalActionUserBulkDelete:
+		return models.AuditActionUserBulkDelete
+	}
+	return string(action)
+}
+
+func approvalAuditDetails(request models.ApprovalRequest) map[string]interface{} {
+	details := map[string]interface{}{
+		"approval_request_id": request.ID,
+		"action":              request.Action,
+		"target_id":           request.TargetID,
+		"requested_by":        request.RequestedBy,
+	}
+	if request.TargetKey != "" {
+		details["target_key"] = request.TargetKey
+	}
+	if request.Payload != nil {
+		details["payload"] = json.RawMessage(*request.Payload)
+	}
+	return details
+}
+
+// bindOptionalJSON boş gövdeye izin vererek isteği bind eder. Hata durumunda yanıtı yazar ve false döner.
+func bindOptionalJSON(c *gin.Context, obj interface{}) bool {
+	if c.Request.ContentLength == 0 {
+		return true
+	}
+	if err := c.ShouldBindJSON(obj); err != nil {
+		c.JSON(http.StatusBadRequest, response.Error("VALIDATION_ERROR", "Invalid request", err.Error()))
+		return false
+	}
+	return true
+}
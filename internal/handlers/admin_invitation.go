@@ -0,0 +1,291 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"prototurk/internal/audit"
+	"prototurk/internal/mailer"
+	"prototurk/internal/models"
+	"prototurk/internal/repository"
+	"prototurk/pkg/response"
+	"prototurk/pkg/totp"
+	"prototurk/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+	"golang.org/x/crypto/bcrypt"
+)
+
+type AdminInvitationHandler struct {
+	invitations repository.InvitationRepository
+	admins      repository.AdminRepository
+	audit       audit.Recorder
+	mailer      mailer.Mailer
+	acceptURL   string
+	ttl         time.Duration
+}
+
+// NewAdminInvitationHandler davet linklerini acceptURL'e token query parametresi ekleyerek oluşturur
+func NewAdminInvitationHandler(invitations repository.InvitationRepository, admins repository.AdminRepository, recorder audit.Recorder, m mailer.Mailer, acceptURL string, ttl time.Duration) *AdminInvitationHandler {
+	return &AdminInvitationHandler{invitations: invitations, admins: admins, audit: recorder, mailer: m, acceptURL: acceptURL, ttl: ttl}
+}
+
+// Create bekleyen bir admin oluşturur ve davet emaili gönderir (Sadece super admin yapabilir)
+func (h *AdminInvitationHandler) Create(c *gin.Context) {
+	admin := c.MustGet("admin").(models.Admin)
+	if !admin.CanManageInvitations() {
+		c.JSON(http.StatusForbidden, response.Error("FORBIDDEN", "Super admin permission required", nil))
+		return
+	}
+
+	var req models.CreateAdminInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.Error("VALIDATION_ERROR", "Invalid request", err.Error()))
+		return
+	}
+
+	if !req.Role.ValidateRole() {
+		c.JSON(http.StatusBadRequest, response.Error("VALIDATION_ERROR", "Invalid admin role", nil))
+		return
+	}
+
+	// İlk super admin varken yeni super admin davet edilemez
+	if req.Role == models.AdminRoleSuperAdmin {
+		if _, err := h.admins.FirstSuperAdmin(c.Request.Context()); err == nil {
+			c.JSON(http.StatusForbidden, response.Error("FORBIDDEN", "Cannot invite another super admin while first super admin exists", nil))
+			return
+		}
+	}
+
+	// Email kontrolü - bekleyen davetler de dahil aktif (silinmemiş) admin'lerde kontrol et
+	if taken, err := h.admins.EmailExists(c.Request.Context(), req.Email, 0); err != nil {
+		c.JSON(http.StatusInternalServerError, response.Error("SERVER_ERROR", "Error creating invitation", nil))
+		return
+	} else if taken {
+		c.JSON(http.StatusConflict, response.Error("EMAIL_EXISTS", "Email already exists", nil))
+		return
+	}
+
+	token, err := utils.RandomToken(32)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.Error("SERVER_ERROR", "Error processing request", nil))
+		return
+	}
+
+	// Parola davet kabul edilene kadar boş kalır, bu yüzden bekleyen admin giriş yapamaz
+	pendingAdmin := models.Admin{
+		Email:  req.Email,
+		Name:   req.Name,
+		Role:   req.Role,
+		Status: models.AdminStatusPending,
+	}
+	invitation := models.AdminInvitation{
+		Email:     req.Email,
+		Role:      req.Role,
+		TokenHash: utils.HashToken(token),
+		InvitedBy: &admin.ID,
+		ExpiresAt: utils.Now().Add(h.ttl),
+	}
+
+	if err := h.invitations.Create(c.Request.Context(), &pendingAdmin, &invitation); err != nil {
+		c.JSON(http.StatusInternalServerError, response.Error("SERVER_ERROR", "Error creating invitation", nil))
+		return
+	}
+
+	h.send(c.Request.Context(), &invitation, pendingAdmin.Name, token)
+
+	h.audit.Record(c, models.AuditActionInvitationCreate, "admin", pendingAdmin.ID, map[string]interface{}{
+		"email": invitation.Email,
+		"role":  invitation.Role,
+	})
+
+	c.JSON(http.StatusCreated, response.Success(invitation))
+}
+
+// List kabul edilmemiş ve iptal edilmemiş davetleri listeler (Sadece super admin yapabilir)
+func (h *AdminInvitationHandler) List(c *gin.Context) {
+	admin := c.MustGet("admin").(models.Admin)
+	if !admin.CanManageInvitations() {
+		c.JSON(http.StatusForbidden, response.Error("FORBIDDEN", "Super admin permission required", nil))
+		return
+	}
+
+	invitations, err := h.invitations.ListOpen(c.Request.Context())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.Error("SERVER_ERROR", "Error listing invitations", nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(invitations))
+}
+
+// Resend daveti yeni bir token ve süre ile tekrar gönderir, eski link geçersiz olur (Sadece super admin yapabilir)
+func (h *AdminInvitationHandler) Resend(c *gin.Context) {
+	admin := c.MustGet("admin").(models.Admin)
+	if !admin.CanManageInvitations() {
+		c.JSON(http.StatusForbidden, response.Error("FORBIDDEN", "Super admin permission required", nil))
+		return
+	}
+
+	invitation, ok := h.findOpenInvitation(c)
+	if !ok {
+		return
+	}
+
+	if invitation.AdminID == nil {
+		c.JSON(http.StatusNotFound, response.Error("NOT_FOUND", "Invited admin not found", nil))
+		return
+	}
+	pendingAdmin, err := h.admins.FindByID(c.Request.Context(), *invitation.AdminID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, response.Error("NOT_FOUND", "Invited admin not found", nil))
+		return
+	}
+
+	token, err := utils.RandomToken(32)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.Error("SERVER_ERROR", "Error processing request", nil))
+		return
+	}
+
+	err = h.invitations.Update(c.Request.Context(), invitation, map[string]interface{}{
+		"token_hash": utils.HashToken(token),
+		"expires_at": utils.Now().Add(h.ttl),
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.Error("SERVER_ERROR", "Error updating invitation", nil))
+		return
+	}
+
+	h.send(c.Request.Context(), invitation, pendingAdmin.Name, token)
+
+	h.audit.Record(c, models.AuditActionInvitationResend, "admin", pendingAdmin.ID, map[string]interface{}{
+		"email": invitation.Email,
+	})
+
+	c.JSON(http.StatusOK, response.Success(invitation))
+}
+
+// Revoke daveti iptal eder ve bekleyen admin kaydını kaldırır (Sadece super admin yapabilir)
+func (h *AdminInvitationHandler) Revoke(c *gin.Context) {
+	admin := c.MustGet("admin").(models.Admin)
+	if !admin.CanManageInvitations() {
+		c.JSON(http.StatusForbidden, response.Error("FORBIDDEN", "Super admin permission required", nil))
+		return
+	}
+
+	invitation, ok := h.findOpenInvitation(c)
+	if !ok {
+		return
+	}
+
+	// Bekleyen admin hiç aktif olmadığı için çöp kutusuna değil, kalıcı olarak silinir
+	if err := h.invitations.Revoke(c.Request.Context(), invitation); err != nil {
+		c.JSON(http.StatusInternalServerError, response.Error("SERVER_ERROR", "Error revoking invitation", nil))
+		return
+	}
+
+	h.audit.Record(c, models.AuditActionInvitationRevoke, "admin_invitation", invitation.ID, map[string]interface{}{
+		"email": invitation.Email,
+	})
+
+	c.JSON(http.StatusOK, response.Success(gin.H{"message": "Invitation revoked successfully"}))
+}
+
+// Accept davet token'ı ile admin'in kendi parolasını belirlemesini sağlar
+func (h *AdminInvitationHandler) Accept(c *gin.Context) {
+	var req models.AcceptAdminInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.Error("VALIDATION_ERROR", "Invalid request", err.Error()))
+		return
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.Error("SERVER_ERROR", "Error processing request", nil))
+		return
+	}
+
+	updates := map[string]interface{}{
+		"password":            string(hashedPassword),
+		"password_changed_at": utils.Now(),
+		"status":              models.AdminStatusActive,
+	}
+
+	// 2FA secret'ı kaydedilir ama ilk kod doğrulanana kadar aktif edilmez
+	var secret string
+	if req.EnableTwoFactor {
+		secret, err = totp.GenerateSecret()
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, response.Error("SERVER_ERROR", "Error processing request", nil))
+			return
+		}
+		updates["two_factor_secret"] = secret
+	}
+
+	// Aynı token ile eşzamanlı istekler daveti iki kez kabul edemez
+	admin, err := h.invitations.Accept(c.Request.Context(), utils.HashToken(req.Token), updates)
+	if errors.Is(err, models.ErrInvitationInvalid) {
+		c.JSON(http.StatusBadRequest, response.Error("INVALID_INVITATION", "Invitation is invalid or expired", nil))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.Error("SERVER_ERROR", "Error accepting invitation", nil))
+		return
+	}
+
+	h.audit.Record(c, models.AuditActionInvitationAccept, "admin", admin.ID, map[string]interface{}{
+		"email": admin.Email,
+	})
+
+	data := gin.H{"admin": admin}
+	if secret != "" {
+		data["two_factor"] = gin.H{
+			"secret":      secret,
+			"otpauth_url": totp.URL(twoFactorIssuer, admin.Email, secret),
+		}
+	}
+
+	c.JSON(http.StatusOK, response.Success(data))
+}
+
+// findOpenInvitation URL'deki id ile kabul edilmemiş ve iptal edilmemiş daveti bulur
+func (h *AdminInvitationHandler) findOpenInvitation(c *gin.Context) (*models.AdminInvitation, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusNotFound, response.Error("NOT_FOUND", "Invitation not found", nil))
+		return nil, false
+	}
+
+	invitation, err := h.invitations.FindOpen(c.Request.Context(), uint(id))
+	if errors.Is(err, models.ErrInvitationNotFound) {
+		c.JSON(http.StatusNotFound, response.Error("NOT_FOUND", "Invitation not found", nil))
+		return nil, false
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.Error("SERVER_ERROR", "Error finding invitation", nil))
+		return nil, false
+	}
+	return invitation, true
+}
+
+// send davet emailini gönderir. Gönderim başarısız olursa sent_at boş kalır ve davet tekrar gönderilebilir.
+func (h *AdminInvitationHandler) send(ctx context.Context, invitation *models.AdminInvitation, name string, token string) {
+	link := fmt.Sprintf("%s?token=%s", h.acceptURL, token)
+	body := fmt.Sprintf("Merhaba %s,\n\nProtoTürk yönetim paneline davet edildiniz. Parolanızı belirlemek için aşağıdaki linki kullanın:\n\n%s\n\nBu link %s tarihine kadar geçerlidir ve sadece bir kez kullanılabilir.",
+		name, link, invitation.ExpiresAt.Format(time.RFC1123))
+
+	if err := h.mailer.Send(invitation.Email, "ProtoTürk admin daveti", body); err != nil {
+		slog.ErrorContext(ctx, "Error sending invitation", "invitation_id", invitation.ID, "error", err)
+		return
+	}
+
+	if err := h.invitations.Update(ctx, invitation, map[string]interface{}{"sent_at": utils.Now()}); err != nil {
+		slog.ErrorContext(ctx, "Error updating invitation", "invitation_id", invitation.ID, "error", err)
+	}
+}
@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"net/http"
+
+	"prototurk/internal/jobs"
+	"prototurk/internal/models"
+	"prototurk/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminJobHandler struct {
+	jobs *jobs.Manager
+}
+
+func NewAdminJobHandler(jobManager *jobs.Manager) *AdminJobHandler {
+	return &AdminJobHandler{jobs: jobManager}
+}
+
+// Get arka plan işinin ilerleme durumunu döner. İşi sadece başlatan admin veya super admin görebilir.
+func (h *AdminJobHandler) Get(c *gin.Context) {
+	admin := c.MustGet("admin").(models.Admin)
+
+	job, ok := h.jobs.Get(c.Param("id"))
+	if !ok {
+		c.JSON(http.StatusNotFound, response.Error("NOT_FOUND", "Job not found", nil))
+		return
+	}
+
+	state := job.Snapshot()
+	if state.CreatedBy != admin.ID && admin.Role != models.AdminRoleSuperAdmin {
+		c.JSON(http.StatusNotFound, response.Error("NOT_FOUND", "Job not found", nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(state))
+}
@@ -0,0 +1,129 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"prototurk/internal/audit"
+	"prototurk/internal/middleware"
+	"prototurk/internal/models"
+	"prototurk/internal/service"
+	"prototurk/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminNetworkRuleHandler struct {
+	rules  *service.NetworkRuleService
+	policy middleware.NetworkPolicyConfig
+	audit  audit.Recorder
+}
+
+func NewAdminNetworkRuleHandler(rules *service.NetworkRuleService, policy middleware.NetworkPolicyConfig, recorder audit.Recorder) *AdminNetworkRuleHandler {
+	return &AdminNetworkRuleHandler{rules: rules, policy: policy, audit: recorder}
+}
+
+// List ağ kurallarını listeler. admin_id verilirse sadece o admin'in kuralları döner (Sadece super admin yapabilir)
+func (h *AdminNetworkRuleHandler) List(c *gin.Context) {
+	admin := c.MustGet("admin").(models.Admin)
+
+	var adminID *uint
+	if value := c.Query("admin_id"); value != "" {
+		id, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, response.Error("VALIDATION_ERROR", "Invalid admin_id", nil))
+			return
+		}
+		parsed := uint(id)
+		adminID = &parsed
+	}
+
+	rules, err := h.rules.List(c.Request.Context(), &admin, adminID)
+	if err != nil {
+		writeNetworkRuleError(c, err, "Error listing network rules", "")
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(gin.H{
+		"rules":       rules,
+		"env_allowed": networkStrings(h.policy),
+		"bypass":      h.policy.Bypass,
+	}))
+}
+
+// Create yeni bir global veya admin bazlı ağ kuralı ekler (Sadece super admin yapabilir)
+func (h *AdminNetworkRuleHandler) Create(c *gin.Context) {
+	admin := c.MustGet("admin").(models.Admin)
+
+	var req models.CreateAdminNetworkRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.Error("VALIDATION_ERROR", "Invalid request", err.Error()))
+		return
+	}
+
+	rule, err := h.rules.Create(c.Request.Context(), &admin, req, c.ClientIP())
+	if err != nil {
+		writeNetworkRuleError(c, err, "Error creating network rule", "This rule would block your own access")
+		return
+	}
+
+	h.audit.Record(c, models.AuditActionNetworkRuleCreate, "admin_network_rule", rule.ID, map[string]interface{}{
+		"cidr":     rule.CIDR,
+		"admin_id": rule.AdminID,
+	})
+
+	c.JSON(http.StatusCreated, response.Success(rule))
+}
+
+// Delete bir ağ kuralını siler (Sadece super admin yapabilir)
+func (h *AdminNetworkRuleHandler) Delete(c *gin.Context) {
+	admin := c.MustGet("admin").(models.Admin)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusNotFound, response.Error("NOT_FOUND", "Network rule not found", nil))
+		return
+	}
+
+	rule, err := h.rules.Delete(c.Request.Context(), &admin, uint(id), c.ClientIP())
+	if err != nil {
+		writeNetworkRuleError(c, err, "Error deleting network rule", "Deleting this rule would block your own access")
+		return
+	}
+
+	h.audit.Record(c, models.AuditActionNetworkRuleDelete, "admin_network_rule", rule.ID, map[string]interface{}{
+		"cidr":     rule.CIDR,
+		"admin_id": rule.AdminID,
+	})
+
+	c.JSON(http.StatusOK, response.Success(gin.H{"message": "Network rule deleted successfully"}))
+}
+
+// writeNetworkRuleError NetworkRuleService hatalarını HTTP yanıtına çevirir. Bilinmeyen hatalar serverMessage ile
+// 500 döner.
+func writeNetworkRuleError(c *gin.Context, err error, serverMessage, lockoutMessage string) {
+	var permissionErr *service.PermissionError
+	switch {
+	case errors.As(err, &permissionErr):
+		c.JSON(http.StatusForbidden, response.Error("FORBIDDEN", permissionErr.Message, nil))
+	case errors.Is(err, models.ErrInvalidCIDR):
+		c.JSON(http.StatusBadRequest, response.Error("VALIDATION_ERROR", "Invalid CIDR", nil))
+	case errors.Is(err, models.ErrAdminNotFound):
+		c.JSON(http.StatusNotFound, response.Error("NOT_FOUND", "Admin not found", nil))
+	case errors.Is(err, models.ErrNetworkRuleNotFound):
+		c.JSON(http.StatusNotFound, response.Error("NOT_FOUND", "Network rule not found", nil))
+	case errors.Is(err, service.ErrNetworkLockout):
+		c.JSON(http.StatusBadRequest, response.Error("LOCKOUT_PREVENTED", lockoutMessage, nil))
+	default:
+		c.JSON(http.StatusInternalServerError, response.Error("SERVER_ERROR", serverMessage, nil))
+	}
+}
+
+func networkStrings(policy middleware.NetworkPolicyConfig) []string {
+	networks := make([]string, 0, len(policy.AllowedNetworks))
+	for _, network := range policy.AllowedNetworks {
+		networks = append(networks, network.String())
+	}
+	return networks
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"prototurk/internal/models"
+	"prototurk/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ChangePassword giriş yapmış admin'in kendi parolasını değiştirmesini sağlar.
+// Parola değiştirme zorunluluğu olan adminlerin kısıtlı token'ı sadece bu endpoint'i çağırabilir.
+func (h *AdminHandler) ChangePassword(c *gin.Context) {
+	admin := c.MustGet("admin").(models.Admin)
+
+	var req models.ChangeAdminPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.Error("VALIDATION_ERROR", "Invalid request", err.Error()))
+		return
+	}
+
+	err := h.admins.ChangePassword(c.Request.Context(), &admin, req.CurrentPassword, req.NewPassword)
+	if errors.Is(err, models.ErrInvalidPassword) {
+		c.JSON(http.StatusUnauthorized, response.Error("INVALID_PASSWORD", "Current password is incorrect", nil))
+		return
+	}
+	if errors.Is(err, models.ErrPasswordReused) {
+		c.JSON(http.StatusBadRequest, response.Error("PASSWORD_REUSED", "New password must be different from recent passwords", nil))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.Error("SERVER_ERROR", "Error updating password", nil))
+		return
+	}
+
+	h.audit.Record(c, models.AuditActionPasswordChange, "admin", admin.ID, nil)
+
+	// Kısıtlı token ile gelen admin yeni parolasıyla tekrar giriş yapmadan devam edebilsin
+	tokenString, err := h.issueToken(c, admin, "", adminTokenTTL)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.Error("SERVER_ERROR", "Error generating token", nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(gin.H{
+		"message": "Password updated successfully",
+		"token":   tokenString,
+	}))
+}
+
+// ForcePasswordChange bir admin'in bir sonraki isteğinde parolasını değiştirmesini zorunlu kılar (Sadece super admin yapabilir)
+func (h *AdminHandler) ForcePasswordChange(c *gin.Context) {
+	currentAdmin := c.MustGet("admin").(models.Admin)
+	if !currentAdmin.CanForcePasswordChange() {
+		c.JSON(http.StatusForbidden, response.Error("FORBIDDEN", "Super admin permission required", nil))
+		return
+	}
+
+	id, ok := adminIDParam(c)
+	if !ok {
+		return
+	}
+
+	admin, err := h.admins.ForcePasswordChange(c.Request.Context(), &currentAdmin, id)
+	if err != nil {
+		writeAdminError(c, err, "Error updating admin")
+		return
+	}
+
+	h.audit.Record(c, models.AuditActionForcePasswordChange, "admin", admin.ID, map[string]interface{}{
+		"email": admin.Email,
+	})
+
+	c.JSON(http.StatusOK, response.Success(admin))
+}
@@ -0,0 +1,50 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"prototurk/internal/models"
+	"prototurk/pkg/response"
+	"prototurk/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Reauth parolayı (ve aktifse 2FA kodunu) tekrar doğrulayıp reauth_at claim'i taşıyan, sadece reauth penceresi
+// boyunca geçerli yükseltilmiş bir token döner. Hassas işlemler bu token ile yapılır; oturum token'ı değişmez.
+func (h *AdminHandler) Reauth(c *gin.Context) {
+	admin := c.MustGet("admin").(models.Admin)
+
+	var req models.AdminReauthRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.Error("VALIDATION_ERROR", "Invalid request", err.Error()))
+		return
+	}
+
+	err := h.admins.Reauthenticate(&admin, req.Password, req.Code)
+	if errors.Is(err, models.ErrInvalidPassword) {
+		c.JSON(http.StatusUnauthorized, response.Error("INVALID_PASSWORD", "Password is incorrect", nil))
+		return
+	}
+	if err != nil {
+		writeAdminError(c, err, "Error processing request")
+		return
+	}
+
+	now := utils.Now()
+	tokenString, err := h.issueTokenWithClaims(c, admin, h.reauthWindow, map[string]interface{}{
+		"reauth_at": now.Unix(),
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.Error("SERVER_ERROR", "Error generating token", nil))
+		return
+	}
+
+	h.audit.Record(c, models.AuditActionReauth, "admin", admin.ID, nil)
+
+	c.JSON(http.StatusOK, response.Success(gin.H{
+		"reauth_token":      tokenString,
+		"reauth_expires_at": now.Add(h.reauthWindow),
+	}))
+}
@@ -0,0 +1,38 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"prototurk/internal/models"
+	"prototurk/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Setup log'a yazılan kurulum token'ı ile ilk super admin'i oluşturur.
+// Sadece hiç admin yokken ve token kullanılmamışken çalışır.
+func (h *AdminHandler) Setup(c *gin.Context) {
+	var req models.AdminSetupRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.Error("VALIDATION_ERROR", "Invalid request", err.Error()))
+		return
+	}
+
+	admin, err := h.admins.Setup(c.Request.Context(), req)
+	if errors.Is(err, models.ErrSetupUnavailable) {
+		c.JSON(http.StatusForbidden, response.Error("SETUP_UNAVAILABLE", "Setup token is invalid or setup is already completed", nil))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.Error("SERVER_ERROR", "Error creating admin", nil))
+		return
+	}
+
+	c.Set("admin_id", admin.ID)
+	h.audit.Record(c, models.AuditActionAdminSetup, "admin", admin.ID, map[string]interface{}{
+		"email": admin.Email,
+	})
+
+	c.JSON(http.StatusCreated, response.Success(admin))
+}
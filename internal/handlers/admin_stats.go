@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"prototurk/internal/models"
+	"prototurk/internal/service"
+	"prototurk/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// statsMaxDays tek istekte dönülebilecek en uzun zaman aralığıdır
+const statsMaxDays = 366
+
+// AdminStatsHandler admin paneli istatistiklerinin parametrelerini okur ve sonucu JSON veya CSV olarak döner
+type AdminStatsHandler struct {
+	stats *service.StatsService
+}
+
+func NewAdminStatsHandler(stats *service.StatsService) *AdminStatsHandler {
+	return &AdminStatsHandler{stats: stats}
+}
+
+// Get kullanıcı ve admin istatistiklerini döner. format=csv ile zaman serisi CSV olarak indirilir.
+func (h *AdminStatsHandler) Get(c *gin.Context) {
+	admin := c.MustGet("admin").(models.Admin)
+
+	interval := c.DefaultQuery("interval", models.StatsIntervalDay)
+	if interval != models.StatsIntervalDay && interval != models.StatsIntervalWeek {
+		c.JSON(http.StatusBadRequest, response.Error("VALIDATION_ERROR", "Interval must be day or week", nil))
+		return
+	}
+
+	days, err := strconv.Atoi(c.DefaultQuery("days", "30"))
+	if err != nil || days < 1 || days > statsMaxDays {
+		c.JSON(http.StatusBadRequest, response.Error("VALIDATION_ERROR", fmt.Sprintf("Days must be between 1 and %d", statsMaxDays), nil))
+		return
+	}
+
+	windows, err := parseWindows(c.DefaultQuery("windows", "7,30"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error("VALIDATION_ERROR", "Invalid windows", err.Error()))
+		return
+	}
+
+	stats, err := h.stats.Get(c.Request.Context(), &admin, interval, days, windows)
+	var permissionErr *service.PermissionError
+	if errors.As(err, &permissionErr) {
+		c.JSON(http.StatusForbidden, response.Error("FORBIDDEN", permissionErr.Message, nil))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.Error("SERVER_ERROR", "Error calculating stats", nil))
+		return
+	}
+
+	if c.Query("format") == "csv" {
+		writeStatsCSV(c, stats)
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(stats))
+}
+
+func writeStatsCSV(c *gin.Context, stats *models.AdminStats) {
+	c.Header("Content-Type", "text/csv; charset=utf-8")
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=stats-%s-%s.csv", stats.Interval, stats.GeneratedAt.Format("20060102")))
+	c.Status(http.StatusOK)
+
+	w := csv.NewWriter(c.Writer)
+	w.Write([]string{"bucket", "registrations", "logins"})
+	for _, point := range stats.Series {
+		w.Write([]string{
+			point.Bucket.Format(time.RFC3339),
+			strconv.FormatInt(point.Registrations, 10),
+			strconv.FormatInt(point.Logins, 10),
+		})
+	}
+	w.Flush()
+}
+
+func parseWindows(value string) ([]int, error) {
+	var windows []int
+	for _, part := range strings.Split(value, ",") {
+		window, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil || window < 1 || window > statsMaxDays {
+			return nil, fmt.Errorf("window must be between 1 and %d days", statsMaxDays)
+		}
+		windows = append(windows, window)
+	}
+	return windows, nil
+}
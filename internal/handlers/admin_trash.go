@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"prototurk/internal/models"
+	"prototurk/internal/service"
+	"prototurk/pkg/response"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Trash silinmiş (soft delete) adminleri listeler (Sadece super admin yapabilir)
+func (h *AdminHandler) Trash(c *gin.Context) {
+	admin := c.MustGet("admin").(models.Admin)
+
+	admins, err := h.admins.ListDeleted(c.Request.Context(), &admin)
+	if err != nil {
+		writeAdminError(c, err, "Error listing deleted admins")
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(admins))
+}
+
+// Restore silinmiş bir admin'i geri getirir (Sadece super admin yapabilir)
+func (h *AdminHandler) Restore(c *gin.Context) {
+	targetAdmin, ok := h.findDeletedAdmin(c)
+	if !ok {
+		return
+	}
+
+	// Aynı email ile sonradan oluşturulmuş aktif bir admin varsa geri getirme
+	err := h.admins.Restore(c.Request.Context(), targetAdmin)
+	var emailErr *service.EmailInUseError
+	if errors.As(err, &emailErr) {
+		c.JSON(http.StatusConflict, response.Error("EMAIL_EXISTS", "Another active admin uses this email", gin.H{
+			"admin_id": emailErr.AdminID,
+		}))
+		return
+	}
+	if errors.Is(err, models.ErrEmailExists) {
+		c.JSON(http.StatusConflict, response.Error("EMAIL_EXISTS", "Another active admin uses this email", nil))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.Error("SERVER_ERROR", "Error restoring admin", nil))
+		return
+	}
+
+	h.audit.Record(c, models.AuditActionAdminRestore, "admin", targetAdmin.ID, map[string]interface{}{
+		"email": targetAdmin.Email,
+	})
+
+	c.JSON(http.StatusOK, response.Success(targetAdmin))
+}
+
+// Purge silinmiş bir admin'i kalıcı olarak siler (Sadece super admin yapabilir)
+func (h *AdminHandler) Purge(c *gin.Context) {
+	targetAdmin, ok := h.findDeletedAdmin(c)
+	if !ok {
+		return
+	}
+
+	if h.approvals.Requires(models.ApprovalActionAdminPurge) {
+		requestApproval(c, h.approvals, h.audit, models.ApprovalActionAdminPurge, "admin", targetAdmin.ID, nil)
+		return
+	}
+
+	if err := h.admins.Purge(c.Request.Context(), targetAdmin); err != nil {
+		c.JSON(http.StatusInternalServerError, response.Error("SERVER_ERROR", "Error purging admin", nil))
+		return
+	}
+
+	h.audit.Record(c, models.AuditActionAdminPurge, "admin", targetAdmin.ID, map[string]interface{}{
+		"email": targetAdmin.Email,
+	})
+
+	c.JSON(http.StatusOK, response.Success(gin.H{"message": "Admin purged successfully"}))
+}
+
+// findDeletedAdmin URL'deki id ile sadece silinmiş adminler arasında arama yapar.
+// Bulunamazsa veya yetki yoksa yanıtı yazar ve false döner.
+func (h *AdminHandler) findDeletedAdmin(c *gin.Context) (*models.Admin, bool) {
+	admin := c.MustGet("admin").(models.Admin)
+	if !admin.CanManageTrash() {
+		c.JSON(http.StatusForbidden, response.Error("FORBIDDEN", "Super admin permission required", nil))
+		return nil, false
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error("VALIDATION_ERROR", "Invalid admin ID", nil))
+		return nil, false
+	}
+
+	targetAdmin, err := h.admins.FindDeleted(c.Request.Context(), &admin, uint(id))
+	if errors.Is(err, models.ErrAdminNotFound) {
+		c.JSON(http.StatusNotFound, response.Error("NOT_FOUND", "Deleted admin not found", nil))
+		return nil, false
+	}
+	if err != nil {
+		writeAdminError(c, err, "Error finding deleted admin")
+		return nil, false
+	}
+
+	return targetAdmin, true
+}
@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"net/http"
+
+	"prototurk/internal/models"
+	"prototurk/pkg/response"
+	"prototurk/pkg/totp"
+	"prototurk/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// twoFactorIssuer authenticator uygulamalarında görünen hesap sağlayıcı adıdır
+const twoFactorIssuer = "ProtoTürk"
+
+// SetupTwoFactor giriş yapmış admin için yeni bir 2FA secret'ı oluşturur
+func (h *AdminHandler) SetupTwoFactor(c *gin.Context) {
+	admin := c.MustGet("admin").(models.Admin)
+	if admin.TwoFactorEnabled {
+		c.JSON(http.StatusBadRequest, response.Error("TWO_FACTOR_ALREADY_ENABLED", "Two-factor authentication is already enabled", nil))
+		return
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.Error("SERVER_ERROR", "Error processing request", nil))
+		return
+	}
+
+	if err := h.admins.SetTwoFactorSecret(c.Request.Context(), &admin, secret); err != nil {
+		c.JSON(http.StatusInternalServerError, response.Error("SERVER_ERROR", "Error updating admin", nil))
+		return
+	}
+
+	c.JSON(http.StatusOK, response.Success(gin.H{
+		"secret":      secret,
+		"otpauth_url": totp.URL(twoFactorIssuer, admin.Email, secret),
+	}))
+}
+
+// ConfirmTwoFactor authenticator uygulamasından gelen ilk kodu doğrulayıp 2FA'yı aktif eder
+func (h *AdminHandler) ConfirmTwoFactor(c *gin.Context) {
+	admin := c.MustGet("admin").(models.Admin)
+
+	var req models.TwoFactorConfirmRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.Error("VALIDATION_ERROR", "Invalid request", err.Error()))
+		return
+	}
+
+	if admin.TwoFactorSecret == "" {
+		c.JSON(http.StatusBadRequest, response.Error("TWO_FACTOR_NOT_SETUP", "Two-factor authentication is not set up", nil))
+		return
+	}
+
+	if !totp.Validate(admin.TwoFactorSecret, req.Code, utils.Now()) {
+		c.JSON(http.StatusUnauthorized, response.Error("INVALID_TWO_FACTOR_CODE", "Invalid two-factor code", nil))
+		return
+	}
+
+	if err := h.admins.EnableTwoFactor(c.Request.Context(), &admin); err != nil {
+		c.JSON(http.StatusInternalServerError, response.Error("SERVER_ERROR", "Error updating admin", nil))
+		return
+	}
+
+	h.audit.Record(c, models.AuditActionTwoFactorEnable, "admin", admin.ID, nil)
+
+	c.JSON(http.StatusOK, response.Success(admin))
+}
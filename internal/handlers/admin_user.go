@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"prototurk/internal/audit"
+	"prototurk/internal/jobs"
+	"prototurk/internal/middleware"
+	"prototurk/internal/models"
+	"prototurk/internal/repository"
+	"prototurk/internal/service"
+	"prototurk/pkg/response"
+	"prototurk/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// AdminUserHandler admin panelinden kullanıcılar üzerinde yapılan işlemleri yönetir
+type AdminUserHandler struct {
+	users            *service.UserService
+	bulk             repository.UserBulkRepository
+	jobs             *jobs.Manager
+	approvals        *service.ApprovalService
+	audit            audit.Recorder
+	impersonationTTL time.Duration
+	jwtSecret        []byte
+}
+
+func NewAdminUserHandler(users *service.UserService, bulk repository.UserBulkRepository, jobManager *jobs.Manager, approvals *service.ApprovalService, recorder audit.Recorder, impersonationTTL time.Duration, jwtSecret []byte) *AdminUserHandler {
+	return &AdminUserHandler{users: users, bulk: bulk, jobs: jobManager, approvals: approvals, audit: recorder, impersonationTTL: impersonationTTL, jwtSecret: jwtSecret}
+}
+
+// Impersonate destek ekibi için kullanıcı adına kısa ömürlü bir token üretir
+func (h *AdminUserHandler) Impersonate(c *gin.Context) {
+	admin := c.MustGet("admin").(models.Admin)
+	if !admin.HasPermission(models.AdminPermissionImpersonateUsers) {
+		c.JSON(http.StatusForbidden, response.Error("FORBIDDEN", "Impersonation permission required", nil))
+		return
+	}
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		c.JSON(http.StatusNotFound, response.Error("USER_NOT_FOUND", "User not found", nil))
+		return
+	}
+
+	user, err := h.users.Get(c.Request.Context(), uint(id))
+	if errors.Is(err, models.ErrUserNotFound) {
+		c.JSON(http.StatusNotFound, response.Error("USER_NOT_FOUND", "User not found", nil))
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.Error("SERVER_ERROR", "Error fetching user", nil))
+		return
+	}
+
+	// Yasaklı kullanıcılar impersonation ile de giriş yapamaz
+	if user.Status == models.UserStatusBanned {
+		c.JSON(http.StatusForbidden, response.Error("USER_BANNED", "User is banned", nil))
+		return
+	}
+
+	expiresAt := utils.Now().Add(h.impersonationTTL)
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"user_id":                 user.ID,
+		"username":                user.Username,
+		middleware.TokenTypeClaim: middleware.TokenTypeUser,
+		"imp":                     true,
+		"act": map[string]interface{}{
+			"admin_id": admin.ID,
+		},
+		"exp": expiresAt.Unix(),
+	})
+
+	tokenString, err := token.SignedString(h.jwtSecret)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.Error("SERVER_ERROR", "Error generating token", nil))
+		return
+	}
+
+	h.audit.Record(c, models.AuditActionUserImpersonate, "user", user.ID, map[string]interface{}{
+		"username":   user.Username,
+		"expires_at": expiresAt,
+	})
+
+	c.JSON(http.StatusOK, response.Success(gin.H{
+		"token":         tokenString,
+		"user":          user,
+		"impersonation": true,
+		"expires_at":    expiresAt,
+	}))
+}
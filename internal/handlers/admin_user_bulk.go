@@ -0,0 +1,519 @@
+package handlers
+
+import (
+	"bufio"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"prototurk/internal/jobs"
+	"prototurk/internal/models"
+	"prototurk/pkg/response"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	importFormatCSV   = "csv"
+	importFormatJSONL = "jsonl"
+
+	// importMaxBytes tek seferde yüklenebilecek en büyük import dosyasıdır
+	importMaxBytes = 50 << 20
+	// importSyncLimit bu sayıdan fazla satır içeren importlar arka plan işi olarak çalışır
+	importSyncLimit = 100
+	importBatchSize = 500
+)
+
+// Import CSV veya JSON Lines formatındaki kullanıcıları içe aktarır.
+// dry_run=true ile sadece doğrulama yapılır, hiçbir kayıt oluşturulmaz.
+func (h *AdminUserHandler) Import(c *gin.Context) {
+	admin := c.MustGet("admin").(models.Admin)
+	if !admin.HasPermission(models.AdminPermissionManageUsers) {
+		c.JSON(http.StatusForbidden, response.Error("FORBIDDEN", "User management permission required", nil))
+		return
+	}
+
+	rows, err := readImportRows(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, response.Error("VALIDATION_ERROR", "Invalid import file", err.Error()))
+		return
+	}
+
+	dryRun := c.Query("dry_run") == "true"
+
+	if len(rows) <= importSyncLimit {
+		report, err := h.importUsers(c.Request.Context(), rows, dryRun, nil)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, response.Error("SERVER_ERROR", "Error importing users", nil))
+			return
+		}
+		h.auditImport(c, report)
+		c.JSON(http.StatusOK, response.Success(report))
+		return
+	}
+
+	// Büyük importlar arka planda çalışır, ilerleme /api/admin/jobs/:id ile takip edilir
+	ip := c.ClientIP()
+	job, err := h.jobs.Start(c.Request.Context(), "user_import", admin.ID, func(ctx context.Context, job *jobs.Job) (interface{}, error) {
+		report, err := h.importUsers(ctx, rows, dryRun, job)
+		if err != nil {
+			return report, err
+		}
+		h.audit.RecordSystem(ctx, models.AuditActionUserImport, "user", 0, map[string]interface{}{
+			"admin_id": admin.ID,
+			"ip":       ip,
+			"dry_run":  report.DryRun,
+			"total":    report.Total,
+			"imported": report.Imported,
+			"failed":   report.Failed,
+		})
+		return report, nil
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.Error("SERVER_ERROR", "Error starting import job", nil))
+		return
+	}
+
+	c.JSON(http.StatusAccepted, response.Success(job.Snapshot()))
+}
+
+// BulkStatus ID listesi veya filtre ile seçilen kullanıcıların durumunu değiştirir
+func (h *AdminUserHandler) BulkStatus(c *gin.Context) {
+	admin := c.MustGet("admin").(models.Admin)
+	if !admin.HasPermission(models.AdminPermissionManageUsers) {
+		c.JSON(http.StatusForbidden, response.Error("FORBIDDEN", "User management permission required", nil))
+		return
+	}
+
+	var req models.BulkUserStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.Error("VALIDATION_ERROR", "Invalid request", err.Error()))
+		return
+	}
+
+	if !req.Status.ValidateStatus() {
+		c.JSON(http.StatusBadRequest, response.Error("INVALID_STATUS", "Invalid user status", nil))
+		return
+	}
+
+	if !validBulkSelection(c, &req.BulkUserRequest) {
+		return
+	}
+
+	affected, err := h.bulk.UpdateStatus(c.Request.Context(), req.BulkUserRequest, req.Status)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.Error("SERVER_ERROR", "Error updating users", nil))
+		return
+	}
+
+	h.audit.Record(c, models.AuditActionUserBulkStatus, "user", 0, map[string]interface{}{
+		"ids":      req.IDs,
+		"filter":   req.Filter,
+		"status":   req.Status,
+		"affected": affected,
+	})
+
+	c.JSON(http.StatusOK, response.Success(gin.H{"affected": affected}))
+}
+
+// BulkDelete ID listesi veya filtre ile seçilen kullanıcıları siler
+func (h *AdminUserHandler) BulkDelete(c *gin.Context) {
+	admin := c.MustGet("admin").(models.Admin)
+	if !admin.HasPermission(models.AdminPermissionManageUsers) {
+		c.JSON(http.StatusForbidden, response.Error("FORBIDDEN", "User management permission required", nil))
+		return
+	}
+
+	var req models.BulkUserRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, response.Error("VALIDATION_ERROR", "Invalid request", err.Error()))
+		return
+	}
+
+	if !validBulkSelection(c, &req) {
+		return
+	}
+
+	// Eşikten fazla kullanıcıyı silen istekler ikinci bir super admin onaylayana kadar ertelenir. Onay isteği
+	// seçilen kullanıcıların ID'lerini taşır; onaylandığında sonradan filtreye uyan kullanıcılar silinmez. Farklı
+	// kullanıcı kümelerini seçen istekler aynı anda bekleyebilir.
+	if h.approvals.BulkDeleteThreshold() > 0 {
+		count, err := h.bulk.Count(c.Request.Context(), req)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, response.Error("SERVER_ERROR", "Error deleting users", nil))
+			return
+		}
+		if h.approvals.RequiresBulkDelete(count) {
+			ids, err := h.bulk.IDs(c.Request.Context(), req)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, response.Error("SERVER_ERROR", "Error deleting users", nil))
+				return
+			}
+			payload := map[string]interface{}{}
+			if req.Filter != nil {
+				payload["filter"] = req.Filter
+			}
+			requestSetApproval(c, h.approvals, h.audit, models.ApprovalActionUserBulkDelete, "user", ids, payload)
+			return
+		}
+	}
+
+	affected, err := h.bulk.Delete(c.Request.Context(), req)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.Error("SERVER_ERROR", "Error deleting users", nil))
+		return
+	}
+
+	h.audit.Record(c, models.AuditActionUserBulkDelete, "user", 0, map[string]interface{}{
+		"ids":      req.IDs,
+		"filter":   req.Filter,
+		"affected": affected,
+	})
+
+	c.JSON(http.StatusOK, response.Success(gin.H{"affected": affected}))
+}
+
+// validBulkSelection toplu işlemlerin kullanıcı seçimini doğrular, geçersizse yanıtı yazar.
+// Yanlışlıkla tüm kullanıcıların etkilenmemesi için ID listesi veya filtre zorunludur.
+func validBulkSelection(c *gin.Context, req *models.BulkUserRequest) bool {
+	hasFilter := req.Filter != nil && !req.Filter.IsEmpty()
+	if len(req.IDs) == 0 && !hasFilter {
+		c.JSON(http.StatusBadRequest, response.Error("VALIDATION_ERROR", "Either ids or filter must be provided", nil))
+		return false
+	}
+	if hasFilter && req.Filter.Status != "" && !req.Filter.Status.ValidateStatus() {
+		c.JSON(http.StatusBadRequest, response.Error("INVALID_STATUS", "Invalid user status", nil))
+		return false
+	}
+	return true
+}
+
+func (h *AdminUserHandler) auditImport(c *gin.Context, report *models.UserImportReport) {
+	h.audit.Record(c, models.AuditActionUserImport, "user", 0, map[string]interface{}{
+		"dry_run":  report.DryRun,
+		"total":    report.Total,
+		"imported": report.Imported,
+		"failed":   report.Failed,
+	})
+}
+
+// importRow dosyadaki bir satırı, satır numarası ve varsa okuma hatası ile birlikte tutar
+type importRow struct {
+	models.UserImportRow
+	number   int
+	parseErr string
+}
+
+// importUsers satırları RegisterRequest kurallarına göre doğrular ve dry run değilse geçerli olanları kaydeder.
+// job boş değilse ilerleme her batch sonunda güncellenir.
+func (h *AdminUserHandler) importUsers(ctx context.Context, rows []importRow, dryRun bool, job *jobs.Job) (*models.UserImportReport, error) {
+	report := &models.UserImportReport{DryRun: dryRun, Total: len(rows), Errors: []models.UserImportError{}}
+
+	valid, err := h.validateImportRows(ctx, rows, report)
+	if err != nil {
+		return report, err
+	}
+	report.Valid = len(valid)
+
+	if dryRun {
+		if job != nil {
+			job.SetProgress(len(rows), len(rows))
+		}
+		return report, nil
+	}
+
+	processed := len(rows) - len(valid)
+	for start := 0; start < len(valid); start += importBatchSize {
+		if err := ctx.Err(); err != nil {
+			return report, err
+		}
+
+		batch := valid[start:min(start+importBatchSize, len(valid))]
+		users := make([]models.User, 0, len(batch))
+		numbers := make([]int, 0, len(batch))
+		for _, row := range batch {
+			hashedPassword, err := bcrypt.GenerateFromPassword([]byte(row.Password), bcrypt.DefaultCost)
+			if err != nil {
+				report.AddError(row.number, "password", "Error processing password")
+				continue
+			}
+			users = append(users, models.User{
+				Username: row.Username,
+				Email:    row.Email,
+				Password: string(hashedPassword),
+				Status:   row.Status,
+			})
+			numbers = append(numbers, row.number)
+		}
+
+		// Batch başarısız olursa (örneğin eşzamanlı kayıt) hatalı satırı bulmak için tek tek dene
+		if err := h.bulk.CreateBatch(ctx, users); err != nil {
+			// Kapanış sırasında iptal edildiyse satırları tek tek denemenin anlamı yok
+			if ctx.Err() != nil {
+				return report, ctx.Err()
+			}
+			for i := range users {
+				users[i].ID = 0
+				if err := h.bulk.CreateBatch(ctx, users[i:i+1]); err != nil {
+					report.AddError(numbers[i], "", "Error creating user")
+					continue
+				}
+				report.Imported++
+			}
+		} else {
+			report.Imported += len(users)
+		}
+
+		processed += len(batch)
+		if job != nil {
+			job.SetProgress(processed, len(rows))
+		}
+	}
+
+	return report, nil
+}
+
+// validateImportRows geçerli satırları döner, hataları rapora ekler
+func (h *AdminUserHandler) validateImportRows(ctx context.Context, rows []importRow, report *models.UserImportReport) ([]importRow, error) {
+	seenUsernames := make(map[string]int)
+	seenEmails := make(map[string]int)
+
+	var candidates []importRow
+	for _, row := range rows {
+		if row.parseErr != "" {
+			report.AddError(row.number, "", row.parseErr)
+			continue
+		}
+		if row.Status == "" {
+			row.Status = models.UserStatusActive
+		}
+
+		if errs := validateImportRow(row.UserImportRow); len(errs) > 0 {
+			for _, e := range errs {
+				report.AddError(row.number, e.Field, e.Message)
+			}
+			continue
+		}
+
+		if first, ok := seenUsernames[row.Username]; ok {
+			report.AddError(row.number, "username", fmt.Sprintf("Duplicate username, first used on row %d", first))
+			continue
+		}
+		if first, ok := seenEmails[row.Email]; ok {
+			report.AddError(row.number, "email", fmt.Sprintf("Duplicate email, first used on row %d", first))
+			continue
+		}
+		seenUsernames[row.Username] = row.number
+		seenEmails[row.Email] = row.number
+
+		candidates = append(candidates, row)
+	}
+
+	// Veritabanında (silinmiş kullanıcılar dahil) zaten olan kullanıcı adı ve emailleri ele
+	existingUsernames := make(map[string]bool)
+	existingEmails := make(map[string]bool)
+	for start := 0; start < len(candidates); start += importBatchSize {
+		var usernames, emails []string
+		for _, row := range candidates[start:min(start+importBatchSize, len(candidates))] {
+			usernames = append(usernames, row.Username)
+			emails = append(emails, row.Email)
+		}
+
+		batchUsernames, batchEmails, err := h.bulk.ExistingIdentities(ctx, usernames, emails)
+		if err != nil {
+			return nil, err
+		}
+		for username := range batchUsernames {
+			existingUsernames[username] = true
+		}
+		for email := range batchEmails {
+			existingEmails[email] = true
+		}
+	}
+
+	var valid []importRow
+	for _, row := range candidates {
+		if existingUsernames[row.Username] {
+			report.AddError(row.number, "username", "Username already exists")
+			continue
+		}
+		if existingEmails[row.Email] {
+			report.AddError(row.number, "email", "Email already exists")
+			continue
+		}
+		valid = append(valid, row)
+	}
+
+	return valid, nil
+}
+
+// validateImportRow satırı kayıt ile aynı kurallara (RegisterRequest) göre doğrular
+func validateImportRow(row models.UserImportRow) []models.UserImportError {
+	var errs []models.UserImportError
+
+	err := binding.Validator.ValidateStruct(&models.RegisterRequest{
+		Username: row.Username,
+		Email:    row.Email,
+		Password: row.Password,
+	})
+	var validationErrors validator.ValidationErrors
+	if errors.As(err, &validationErrors) {
+		for _, fe := range validationErrors {
+			errs = append(errs, models.UserImportError{
+				Field:   strings.ToLower(fe.Field()),
+				Message: fmt.Sprintf("Failed on '%s' rule", fe.Tag()),
+			})
+		}
+	} else if err != nil {
+		errs = append(errs, models.UserImportError{Message: err.Error()})
+	}
+
+	if !row.Status.ValidateStatus() {
+		errs = append(errs, models.UserImportError{Field: "status", Message: "Invalid user status"})
+	}
+
+	return errs
+}
+
+// readImportRows isteği multipart "file" alanından veya doğrudan body'den okur.
+// Format, format query parametresinden, dosya uzantısından veya Content-Type'tan belirlenir.
+func readImportRows(c *gin.Context) ([]importRow, error) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, importMaxBytes)
+
+	format := c.Query("format")
+	var reader io.Reader = c.Request.Body
+
+	if strings.HasPrefix(c.ContentType(), "multipart/") {
+		file, header, err := c.Request.FormFile("file")
+		if err != nil {
+			return nil, errors.New("file field is required")
+		}
+		defer file.Close()
+		reader = file
+
+		if format == "" {
+			switch strings.ToLower(filepath.Ext(header.Filename)) {
+			case ".csv":
+				format = importFormatCSV
+			case ".jsonl", ".ndjson":
+				format = importFormatJSONL
+			}
+		}
+	}
+
+	if format == "" {
+		switch c.ContentType() {
+		case "text/csv":
+			format = importFormatCSV
+		case "application/jsonl", "application/x-ndjson", "application/x-jsonlines":
+			format = importFormatJSONL
+		}
+	}
+
+	switch format {
+	case importFormatCSV:
+		return readCSVRows(reader)
+	case importFormatJSONL:
+		return readJSONLRows(reader)
+	}
+	return nil, errors.New("format must be csv or jsonl")
+}
+
+// readCSVRows başlık satırındaki username, email, password ve status kolonlarını okur.
+// Satır numaraları başlık satırı dahil kaydın dosyada başladığı fiziksel satırı gösterir.
+func readCSVRows(r io.Reader) ([]importRow, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	reader.FieldsPerRecord = -1
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, errors.New("csv header is required")
+	}
+
+	columns := make(map[string]int)
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, required := range []string{"username", "email", "password"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("csv header must contain %s column", required)
+		}
+	}
+
+	value := func(record []string, column string) string {
+		i, ok := columns[column]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return strings.TrimSpace(record[i])
+	}
+
+	var rows []importRow
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+
+		row := importRow{}
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			row.number = parseErr.StartLine
+			row.parseErr = parseErr.Err.Error()
+			rows = append(rows, row)
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		row.number, _ = reader.FieldPos(0)
+		row.UserImportRow = models.UserImportRow{
+			Username: value(record, "username"),
+			Email:    value(record, "email"),
+			Password: value(record, "password"),
+			Status:   models.UserStatus(value(record, "status")),
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+// readJSONLRows her satırı ayrı bir JSON obje olarak okur. Boş satırlar atlanır ancak satır numaraları
+// hata raporunun dosyadaki satırlarla eşleşmesi için dosyadaki fiziksel satırı gösterir.
+func readJSONLRows(r io.Reader) ([]importRow, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var rows []importRow
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" {
+			continue
+		}
+
+		row := importRow{number: line}
+		if err := json.Unmarshal([]byte(text), &row.UserImportRow); err != nil {
+			row.parseErr = "Invalid JSON"
+		}
+		rows = append(rows, row)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
@@ -1,25 +1,28 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"time"
 
+	"prototurk/internal/metrics"
+	"prototurk/internal/middleware"
 	"prototurk/internal/models"
+	"prototurk/internal/service"
 	"prototurk/pkg/response"
 	"prototurk/pkg/utils"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
-	"golang.org/x/crypto/bcrypt"
-	"gorm.io/gorm"
 )
 
 type AuthHandler struct {
-	db *gorm.DB
+	users     *service.UserService
+	jwtSecret []byte
 }
 
-func NewAuthHandler(db *gorm.DB) *AuthHandler {
-	return &AuthHandler{db: db}
+func NewAuthHandler(users *service.UserService, jwtSecret []byte) *AuthHandler {
+	return &AuthHandler{users: users, jwtSecret: jwtSecret}
 }
 
 func (h *AuthHandler) Register(c *gin.Context) {
@@ -29,31 +32,16 @@ func (h *AuthHandler) Register(c *gin.Context) {
 		return
 	}
 
-	// Hash password
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, response.Error("SERVER_ERROR", "Error processing request", nil))
+	user, err := h.users.Register(c.Request.Context(), req)
+	if errors.Is(err, models.ErrUsernameExists) {
+		c.JSON(http.StatusConflict, response.Error("USERNAME_EXISTS", "Username already exists", nil))
 		return
 	}
-
-	// Create user
-	user := models.User{
-		Username: req.Username,
-		Email:    req.Email,
-		Password: string(hashedPassword),
-		Status:   models.UserStatusActive,
+	if errors.Is(err, models.ErrEmailExists) {
+		c.JSON(http.StatusConflict, response.Error("EMAIL_EXISTS", "Email already exists", nil))
+		return
 	}
-
-	result := h.db.Create(&user)
-	if result.Error != nil {
-		if h.db.Where("username = ?", req.Username).First(&models.User{}).Error == nil {
-			c.JSON(http.StatusConflict, response.Error("USERNAME_EXISTS", "Username already exists", nil))
-			return
-		}
-		if h.db.Where("email = ?", req.Email).First(&models.User{}).Error == nil {
-			c.JSON(http.StatusConflict, response.Error("EMAIL_EXISTS", "Email already exists", nil))
-			return
-		}
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, response.Error("SERVER_ERROR", "Error creating user", nil))
 		return
 	}
@@ -68,39 +56,39 @@ func (h *AuthHandler) Login(c *gin.Context) {
 		return
 	}
 
-	var user models.User
-	// Try to find user by username or email
-	if err := h.db.Where("username = ? OR email = ?", req.Identifier, req.Identifier).First(&user).Error; err != nil {
+	user, err := h.users.Authenticate(c.Request.Context(), req.Identifier, req.Password)
+	if errors.Is(err, service.ErrInvalidCredentials) {
+		metrics.LoginFailed(metrics.SubjectUser, "invalid_credentials")
 		c.JSON(http.StatusUnauthorized, response.Error("INVALID_CREDENTIALS", "Invalid username/email or password", nil))
 		return
 	}
-
-	if user.Status == models.UserStatusBanned {
+	if errors.Is(err, service.ErrUserBanned) {
+		metrics.LoginFailed(metrics.SubjectUser, "banned")
 		c.JSON(http.StatusForbidden, response.Error("USER_BANNED", "User is banned", nil))
 		return
 	}
-
-	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
-		c.JSON(http.StatusUnauthorized, response.Error("INVALID_CREDENTIALS", "Invalid username/email or password", nil))
+	if err != nil {
+		metrics.LoginFailed(metrics.SubjectUser, "error")
+		c.JSON(http.StatusInternalServerError, response.Error("SERVER_ERROR", "Error processing request", nil))
 		return
 	}
 
-	// Update last login date
-	h.db.Model(&user).Update("last_login_date", utils.Now())
-
 	// Generate JWT token
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
-		"user_id":  user.ID,
-		"username": user.Username,
-		"exp":      utils.Now().Add(time.Hour * 24 * 7).Unix(), // 7 days
+		"user_id":                 user.ID,
+		"username":                user.Username,
+		middleware.TokenTypeClaim: middleware.TokenTypeUser,
+		"exp":                     utils.Now().Add(time.Hour * 24 * 7).Unix(), // 7 days
 	})
 
-	tokenString, err := token.SignedString([]byte(c.MustGet("jwt_secret").(string)))
+	tokenString, err := token.SignedString(h.jwtSecret)
 	if err != nil {
+		metrics.LoginFailed(metrics.SubjectUser, "error")
 		c.JSON(http.StatusInternalServerError, response.Error("SERVER_ERROR", "Error generating token", nil))
 		return
 	}
 
+	metrics.LoginSucceeded(metrics.SubjectUser)
 	c.JSON(http.StatusOK, response.Success(gin.H{
 		"token": tokenString,
 		"user":  user,
@@ -114,8 +102,8 @@ func (h *AuthHandler) Me(c *gin.Context) {
 		return
 	}
 
-	var user models.User
-	if err := h.db.First(&user, userID).Error; err != nil {
+	user, err := h.users.Get(c.Request.Context(), userID.(uint))
+	if err != nil {
 		c.JSON(http.StatusNotFound, response.Error("USER_NOT_FOUND", "User not found", nil))
 		return
 	}
@@ -142,40 +130,24 @@ func (h *AuthHandler) UpdateProfile(c *gin.Context) {
 		return
 	}
 
-	var user models.User
-	if err := h.db.First(&user, userID).Error; err != nil {
-		c.JSON(http.StatusNotFound, response.Error("USER_NOT_FOUND", "User not found", nil))
+	// Email parola sıfırlamada kullanıldığı için impersonation token'ları ile değiştirilemez
+	if req.Email != "" && middleware.IsImpersonated(c) {
+		c.JSON(http.StatusForbidden, response.Error("IMPERSONATION_FORBIDDEN", "This action is not allowed while impersonating", nil))
 		return
 	}
 
-	// Check username uniqueness if provided
-	if req.Username != "" && req.Username != user.Username {
-		var existingUser models.User
-		if err := h.db.Where("username = ?", req.Username).First(&existingUser).Error; err == nil {
-			c.JSON(http.StatusConflict, response.Error("USERNAME_EXISTS", "Username already exists", nil))
-			return
-		}
-	}
-
-	// Check email uniqueness if provided
-	if req.Email != "" && req.Email != user.Email {
-		var existingUser models.User
-		if err := h.db.Where("email = ?", req.Email).First(&existingUser).Error; err == nil {
-			c.JSON(http.StatusConflict, response.Error("EMAIL_EXISTS", "Email already exists", nil))
-			return
-		}
-	}
-
-	// Update only provided fields
-	updates := make(map[string]interface{})
-	if req.Username != "" {
-		updates["username"] = req.Username
-	}
-	if req.Email != "" {
-		updates["email"] = req.Email
-	}
-
-	if err := h.db.Model(&user).Updates(updates).Error; err != nil {
+	user, err := h.users.UpdateProfile(c.Request.Context(), userID.(uint), req)
+	switch {
+	case errors.Is(err, models.ErrUserNotFound):
+		c.JSON(http.StatusNotFound, response.Error("USER_NOT_FOUND", "User not found", nil))
+		return
+	case errors.Is(err, models.ErrUsernameExists):
+		c.JSON(http.StatusConflict, response.Error("USERNAME_EXISTS", "Username already exists", nil))
+		return
+	case errors.Is(err, models.ErrEmailExists):
+		c.JSON(http.StatusConflict, response.Error("EMAIL_EXISTS", "Email already exists", nil))
+		return
+	case err != nil:
 		c.JSON(http.StatusInternalServerError, response.Error("SERVER_ERROR", "Error updating profile", nil))
 		return
 	}
@@ -191,33 +163,27 @@ func (h *AuthHandler) UpdatePassword(c *gin.Context) {
 		return
 	}
 
+	// Impersonation token'ları ile parola değiştirilemez
+	if middleware.IsImpersonated(c) {
+		c.JSON(http.StatusForbidden, response.Error("IMPERSONATION_FORBIDDEN", "This action is not allowed while impersonating", nil))
+		return
+	}
+
 	var req models.UpdatePasswordRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
 		c.JSON(http.StatusBadRequest, response.Error("VALIDATION_ERROR", "Invalid request body", err.Error()))
 		return
 	}
 
-	var user models.User
-	if err := h.db.First(&user, userID).Error; err != nil {
+	err := h.users.UpdatePassword(c.Request.Context(), userID.(uint), req.CurrentPassword, req.NewPassword)
+	switch {
+	case errors.Is(err, models.ErrUserNotFound):
 		c.JSON(http.StatusNotFound, response.Error("USER_NOT_FOUND", "User not found", nil))
 		return
-	}
-
-	// Mevcut parolayı kontrol et
-	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.CurrentPassword)); err != nil {
+	case errors.Is(err, models.ErrInvalidPassword):
 		c.JSON(http.StatusUnauthorized, response.Error("INVALID_PASSWORD", "Current password is incorrect", nil))
 		return
-	}
-
-	// Yeni parolayı hashle
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, response.Error("SERVER_ERROR", "Error processing request", nil))
-		return
-	}
-
-	// Parolayı güncelle
-	if err := h.db.Model(&user).Update("password", string(hashedPassword)).Error; err != nil {
+	case err != nil:
 		c.JSON(http.StatusInternalServerError, response.Error("SERVER_ERROR", "Error updating password", nil))
 		return
 	}
@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"prototurk/internal/database"
+	"prototurk/pkg/response"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	healthStatusUp   = "up"
+	healthStatusDown = "down"
+)
+
+// DependencyStatus bir bağımlılığın readiness kontrol sonucudur.
+// Endpoint kimlik doğrulama gerektirmediği için hata mesajları genel tutulur; ayrıntılar loglanır.
+type DependencyStatus struct {
+	Status    string `json:"status"`
+	LatencyMs int64  `json:"latency_ms"`
+	Error     string `json:"error,omitempty"`
+	Pending   int    `json:"pending,omitempty"`
+}
+
+// HealthHandler load balancer ve orkestratör için liveness ve readiness endpoint'lerini sağlar
+type HealthHandler struct {
+	db       *gorm.DB
+	migrator *database.Migrator
+	timeout  time.Duration
+	draining func() bool
+}
+
+func NewHealthHandler(db *gorm.DB, migrator *database.Migrator, timeout time.Duration, draining func() bool) *HealthHandler {
+	return &HealthHandler{db: db, migrator: migrator, timeout: timeout, draining: draining}
+}
+
+// Live süreç istek karşılayabildiği sürece başarılı döner; bağımlılıkları kontrol etmez
+func (h *HealthHandler) Live(c *gin.Context) {
+	c.JSON(http.StatusOK, response.Success(gin.H{"status": healthStatusUp}))
+}
+
+// Ready veritabanına erişilebildiğini ve tüm migration'ların uygulandığını kontrol eder.
+// Kapanış sinyali alındıktan sonra trafik bu instance'tan çekilsin diye başarısız döner.
+func (h *HealthHandler) Ready(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), h.timeout)
+	defer cancel()
+
+	checks := map[string]DependencyStatus{"database": h.checkDatabase(ctx)}
+	if checks["database"].Status == healthStatusUp {
+		checks["migrations"] = h.checkMigrations(ctx)
+	} else {
+		checks["migrations"] = DependencyStatus{Status: healthStatusDown, Error: "database unavailable"}
+	}
+	if h.draining() {
+		checks["server"] = DependencyStatus{Status: healthStatusDown, Error: "shutting down"}
+	} else {
+		checks["server"] = DependencyStatus{Status: healthStatusUp}
+	}
+
+	for _, check := range checks {
+		if check.Status != healthStatusUp {
+			c.JSON(http.StatusServiceUnavailable, response.Error("NOT_READY", "Service is not ready", checks))
+			return
+		}
+	}
+	c.JSON(http.StatusOK, response.Success(gin.H{"status": healthStatusUp, "checks": checks}))
+}
+
+func (h *HealthHandler) checkDatabase(ctx context.Context) DependencyStatus {
+	start := time.Now()
+	sqlDB, err := h.db.DB()
+	if err == nil {
+		err = sqlDB.PingContext(ctx)
+	}
+	return dependencyStatus(ctx, "database", start, err, "ping failed")
+}
+
+func (h *HealthHandler) checkMigrations(ctx context.Context) DependencyStatus {
+	start := time.Now()
+	pending, err := h.migrator.Pending(ctx)
+	status := dependencyStatus(ctx, "migrations", start, err, "error reading migrations")
+	if err == nil && len(pending) > 0 {
+		status.Status = healthStatusDown
+		status.Error = "pending migrations"
+		status.Pending = len(pending)
+	}
+	return status
+}
+
+func dependencyStatus(ctx context.Context, name string, start time.Time, err error, message string) DependencyStatus {
+	status := DependencyStatus{Status: healthStatusUp, LatencyMs: time.Since(start).Milliseconds()}
+	if err != nil {
+		slog.WarnContext(ctx, "Readiness check failed", "check", name, "error", err)
+		status.Status = healthStatusDown
+		status.Error = message
+		if errors.Is(err, context.DeadlineExceeded) {
+			status.Error = "timeout"
+		}
+	}
+	return status
+}
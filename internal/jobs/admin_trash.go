@@ -0,0 +1,53 @@
+package jobs
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"prototurk/internal/audit"
+	"prototurk/internal/models"
+	"prototurk/pkg/utils"
+
+	"gorm.io/gorm"
+)
+
+// PurgeDeletedAdmins retention süresinden daha önce silinmiş adminleri kalıcı olarak siler.
+// Context iptal edilene kadar her interval'de bir çalışır.
+func PurgeDeletedAdmins(ctx context.Context, db *gorm.DB, retention time.Duration, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		purgeDeletedAdmins(db, retention)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func purgeDeletedAdmins(db *gorm.DB, retention time.Duration) {
+	cutoff := utils.Now().Add(-retention)
+
+	var admins []models.Admin
+	if err := db.Unscoped().Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff).Find(&admins).Error; err != nil {
+		slog.Error("Error finding expired deleted admins", "error", err)
+		return
+	}
+
+	for _, admin := range admins {
+		if err := db.Unscoped().Delete(&admin).Error; err != nil {
+			slog.Error("Error purging admin", "admin_id", admin.ID, "error", err)
+			continue
+		}
+
+		audit.RecordSystem(db, models.AuditActionAdminPurge, "admin", admin.ID, map[string]interface{}{
+			"email":  admin.Email,
+			"reason": "retention",
+		})
+		slog.Info("Purged deleted admin after retention period", "admin_id", admin.ID)
+	}
+}
@@ -0,0 +1,57 @@
+package jobs
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"prototurk/internal/audit"
+	"prototurk/internal/models"
+	"prototurk/pkg/utils"
+
+	"gorm.io/gorm"
+)
+
+// ExpireApprovalRequests süresi dolmuş bekleyen onay isteklerini expired olarak işaretler.
+// Context iptal edilene kadar her interval'de bir çalışır.
+func ExpireApprovalRequests(ctx context.Context, db *gorm.DB, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		expireApprovalRequests(db)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func expireApprovalRequests(db *gorm.DB) {
+	var requests []models.ApprovalRequest
+	if err := db.Where("status = ? AND expires_at <= ?", models.ApprovalStatusPending, utils.Now()).Find(&requests).Error; err != nil {
+		slog.Error("Error finding expired approval requests", "error", err)
+		return
+	}
+
+	for _, request := range requests {
+		result := db.Model(&models.ApprovalRequest{}).
+			Where("id = ? AND status = ?", request.ID, models.ApprovalStatusPending).
+			Update("status", models.ApprovalStatusExpired)
+		if result.Error != nil {
+			slog.Error("Error expiring approval request", "approval_request_id", request.ID, "error", result.Error)
+			continue
+		}
+		if result.RowsAffected == 0 {
+			continue
+		}
+
+		audit.RecordSystem(db, models.AuditActionApprovalExpire, "approval_request", request.ID, map[string]interface{}{
+			"action":    request.Action,
+			"target_id": request.TargetID,
+			"reason":    "timeout",
+		})
+	}
+}
@@ -0,0 +1,171 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	"prototurk/pkg/utils"
+)
+
+type Status string
+
+const (
+	StatusRunning   Status = "running"
+	StatusCompleted Status = "completed"
+	StatusFailed    Status = "failed"
+)
+
+var errJobPanicked = errors.New("job failed unexpectedly")
+
+// finishedJobTTL tamamlanan işlerin sorgulanabilmesi için bellekte tutulma süresidir
+const finishedJobTTL = 24 * time.Hour
+
+// State bir arka plan işinin ilerleme durumudur
+type State struct {
+	ID         string      `json:"id"`
+	Type       string      `json:"type"`
+	Status     Status      `json:"status"`
+	Total      int         `json:"total"`
+	Processed  int         `json:"processed"`
+	Result     interface{} `json:"result,omitempty"`
+	Error      string      `json:"error,omitempty"`
+	CreatedBy  uint        `json:"created_by"`
+	StartedAt  time.Time   `json:"started_at"`
+	FinishedAt *time.Time  `json:"finished_at,omitempty"`
+}
+
+// Job arka planda çalışan uzun bir iştir
+type Job struct {
+	mu    sync.Mutex
+	state State
+}
+
+// SetProgress işlenen kayıt sayısını günceller
+func (j *Job) SetProgress(processed int, total int) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.state.Processed = processed
+	j.state.Total = total
+}
+
+// Snapshot iş hala çalışırken güvenle JSON'a çevrilebilecek bir kopya döner
+func (j *Job) Snapshot() State {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return j.state
+}
+
+func (j *Job) finish(result interface{}, err error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	now := utils.Now()
+	j.state.FinishedAt = &now
+	j.state.Result = result
+	if err != nil {
+		j.state.Status = StatusFailed
+		j.state.Error = err.Error()
+		return
+	}
+	j.state.Status = StatusCompleted
+}
+
+// Manager arka plan işlerini başlatır ve ilerlemelerini bellekte tutar.
+// İşler sadece başlatıldıkları instance üzerinden sorgulanabilir.
+type Manager struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+
+	mu   sync.Mutex
+	jobs map[string]*Job
+}
+
+func NewManager() *Manager {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Manager{ctx: ctx, cancel: cancel, jobs: make(map[string]*Job)}
+}
+
+// Start fn'i arka planda çalıştırır. fn'in döndüğü sonuç işin Result alanına yazılır.
+// ctx'teki değerler (request ID gibi) işe aktarılır ama iptali aktarılmaz; iş istek bittikten sonra da sürer
+// ve sadece Shutdown ile iptal edilir.
+func (m *Manager) Start(ctx context.Context, jobType string, createdBy uint, fn func(ctx context.Context, job *Job) (interface{}, error)) (*Job, error) {
+	id, err := utils.RandomToken(16)
+	if err != nil {
+		return nil, err
+	}
+
+	job := &Job{state: State{
+		ID:        id,
+		Type:      jobType,
+		Status:    StatusRunning,
+		CreatedBy: createdBy,
+		StartedAt: utils.Now(),
+	}}
+
+	m.mu.Lock()
+	m.cleanup()
+	m.jobs[id] = job
+	m.mu.Unlock()
+
+	jobCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	stop := context.AfterFunc(m.ctx, cancel)
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		defer cancel()
+		defer stop()
+		defer func() {
+			if r := recover(); r != nil {
+				slog.ErrorContext(jobCtx, "Job panicked", "job_id", id, "job_type", jobType, "panic", r, "stack", string(debug.Stack()))
+				job.finish(nil, errJobPanicked)
+			}
+		}()
+
+		result, err := fn(jobCtx, job)
+		job.finish(result, err)
+	}()
+
+	return job, nil
+}
+
+// Get verilen ID'ye sahip işi döner
+func (m *Manager) Get(id string) (*Job, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	job, ok := m.jobs[id]
+	return job, ok
+}
+
+// Shutdown çalışan işlere iptal sinyali gönderir ve bitmelerini bekler
+func (m *Manager) Shutdown(ctx context.Context) error {
+	m.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		m.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// cleanup süresi dolan tamamlanmış işleri siler. m.mu tutulurken çağrılmalıdır.
+func (m *Manager) cleanup() {
+	cutoff := utils.Now().Add(-finishedJobTTL)
+	for id, job := range m.jobs {
+		snapshot := job.Snapshot()
+		if snapshot.FinishedAt != nil && snapshot.FinishedAt.Before(cutoff) {
+			delete(m.jobs, id)
+		}
+	}
+}
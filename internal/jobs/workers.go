@@ -0,0 +1,46 @@
+package jobs
+
+import (
+	"context"
+	"sync"
+)
+
+// Workers PurgeDeletedAdmins gibi periyodik işleri ortak bir context ile başlatır
+// ve kapanışta mevcut turlarını bitirip çıkmalarını bekler
+type Workers struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func NewWorkers() *Workers {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Workers{ctx: ctx, cancel: cancel}
+}
+
+// Go fn'i arka planda çalıştırır. fn context iptal edildiğinde dönmelidir.
+func (w *Workers) Go(fn func(ctx context.Context)) {
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		fn(w.ctx)
+	}()
+}
+
+// Shutdown worker'lara iptal sinyali gönderir ve ctx süresi dolana kadar bitmelerini bekler
+func (w *Workers) Shutdown(ctx context.Context) error {
+	w.cancel()
+
+	done := make(chan struct{})
+	go func() {
+		w.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,111 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+)
+
+// GormLogger GORM loglarını slog'a yönlendirir. Hatalı sorgular error, SlowThreshold'u aşanlar warn,
+// log seviyesi debug ise tüm sorgular debug seviyesinde loglanır. Request ID sorgunun context'inden alınır.
+type GormLogger struct {
+	logger        *slog.Logger
+	level         gormlogger.LogLevel
+	slowThreshold time.Duration
+	logParams     bool
+}
+
+// NewGormLogger slowThreshold sıfırsa yavaş sorgu logu kapalıdır. logParams false ise SQL parametreleri
+// (parola hash'i, token hash'i, email gibi) loga yazılmaz, sorgu $1, $2 yer tutucularıyla loglanır.
+func NewGormLogger(logger *slog.Logger, slowThreshold time.Duration, logParams bool) *GormLogger {
+	return &GormLogger{logger: logger, level: gormlogger.Info, slowThreshold: slowThreshold, logParams: logParams}
+}
+
+func (l *GormLogger) LogMode(level gormlogger.LogLevel) gormlogger.Interface {
+	clone := *l
+	clone.level = level
+	return &clone
+}
+
+func (l *GormLogger) Info(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= gormlogger.Info {
+		l.logger.InfoContext(ctx, fmt.Sprintf(msg, data...), "source", callerSource())
+	}
+}
+
+func (l *GormLogger) Warn(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= gormlogger.Warn {
+		l.logger.WarnContext(ctx, fmt.Sprintf(msg, data...), "source", callerSource())
+	}
+}
+
+func (l *GormLogger) Error(ctx context.Context, msg string, data ...interface{}) {
+	if l.level >= gormlogger.Error {
+		l.logger.ErrorContext(ctx, fmt.Sprintf(msg, data...), "source", callerSource())
+	}
+}
+
+func (l *GormLogger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	if l.level <= gormlogger.Silent {
+		return
+	}
+
+	elapsed := time.Since(begin)
+	attrs := func() []any {
+		sql, rows := fc()
+		if !l.logParams {
+			sql = unfilledPlaceholder.ReplaceAllString(sql, "$$$1")
+		}
+		return []any{
+			"sql", sql,
+			"rows", rows,
+			"duration_ms", float64(elapsed.Microseconds()) / 1000,
+			"source", callerSource(),
+		}
+	}
+
+	switch {
+	// Kayıt bulunamaması uygulama akışının parçasıdır (404, "zaten var mı" kontrolleri), hata değildir
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound) && l.level >= gormlogger.Error:
+		l.logger.ErrorContext(ctx, "Database query failed", append(attrs(), "error", err.Error())...)
+	case l.slowThreshold > 0 && elapsed > l.slowThreshold && l.level >= gormlogger.Warn:
+		l.logger.WarnContext(ctx, "Slow database query", append(attrs(), "threshold_ms", l.slowThreshold.Milliseconds())...)
+	case l.level >= gormlogger.Info && l.logger.Enabled(ctx, slog.LevelDebug):
+		l.logger.DebugContext(ctx, "Database query", attrs()...)
+	}
+}
+
+// unfilledPlaceholder GORM'un parametresi verilmeyen $1 yer tutucularını yazdığı $1$ biçimidir
+var unfilledPlaceholder = regexp.MustCompile(`\$(\d+)\$`)
+
+// ParamsFilter GORM tarafından SQL loglanmadan önce çağrılır
+func (l *GormLogger) ParamsFilter(ctx context.Context, sql string, params ...interface{}) (string, []interface{}) {
+	if l.logParams {
+		return sql, params
+	}
+	return sql, nil
+}
+
+// callerSource sorguyu başlatan uygulama kodunun dosya ve satırını döner; GORM, plugin'leri ve bu paket atlanır
+func callerSource() string {
+	pcs := make([]uintptr, 32)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(3, pcs)])
+	for {
+		frame, more := frames.Next()
+		if !strings.Contains(frame.File, "gorm.io/") && filepath.Base(filepath.Dir(frame.File)) != "logging" {
+			return fmt.Sprintf("%s:%d", frame.File, frame.Line)
+		}
+		if !more {
+			return ""
+		}
+	}
+}
@@ -0,0 +1,148 @@
+// Package logging log/slog tabanlı yapılandırılmış loglamayı kurar. Loglar varsayılan olarak JSON'dur,
+// context'teki request ID her kayda eklenir ve hassas alanlar maskelenir.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+
+	// Redacted maskelenen değerlerin yerine yazılır
+	Redacted = "[REDACTED]"
+)
+
+type Config struct {
+	// Level debug, info, warn veya error
+	Level  string `yaml:"level"`
+	Format string `yaml:"format"`
+	// SlowQueryThreshold bu süreyi aşan SQL sorguları warn seviyesinde loglanır (0 = kapalı)
+	SlowQueryThreshold time.Duration `yaml:"slow_query_threshold"`
+	// SQLParams true ise SQL loglarına parametre değerleri de yazılır; sadece development içindir
+	SQLParams bool `yaml:"sql_params"`
+}
+
+// Validate seviye ve formatın bilinen değerler olduğunu kontrol eder
+func (c Config) Validate() error {
+	if _, err := ParseLevel(c.Level); err != nil {
+		return err
+	}
+	switch strings.ToLower(c.Format) {
+	case "", FormatJSON, FormatText:
+	default:
+		return fmt.Errorf("unknown log format %q", c.Format)
+	}
+	if c.SlowQueryThreshold < 0 {
+		return fmt.Errorf("slow query threshold must not be negative")
+	}
+	return nil
+}
+
+// ParseLevel boş değeri info kabul eder
+func ParseLevel(value string) (slog.Level, error) {
+	var level slog.Level
+	if value == "" {
+		return slog.LevelInfo, nil
+	}
+	if err := level.UnmarshalText([]byte(value)); err != nil {
+		return level, fmt.Errorf("unknown log level %q", value)
+	}
+	return level, nil
+}
+
+// New config'e göre w'ye yazan bir logger oluşturur
+func New(config Config, w io.Writer) (*slog.Logger, error) {
+	level, err := ParseLevel(config.Level)
+	if err != nil {
+		return nil, err
+	}
+
+	options := &slog.HandlerOptions{Level: level, ReplaceAttr: redactAttr}
+	var handler slog.Handler
+	switch strings.ToLower(config.Format) {
+	case "", FormatJSON:
+		handler = slog.NewJSONHandler(w, options)
+	case FormatText:
+		handler = slog.NewTextHandler(w, options)
+	default:
+		return nil, fmt.Errorf("unknown log format %q", config.Format)
+	}
+	return slog.New(&contextHandler{Handler: handler}), nil
+}
+
+// sensitiveKeys değeri loglara hiçbir zaman yazılmaması gereken alan, başlık ve query parametresi adlarıdır
+var sensitiveKeys = map[string]bool{
+	"password":          true,
+	"current_password":  true,
+	"new_password":      true,
+	"secret":            true,
+	"jwt_secret":        true,
+	"two_factor_secret": true,
+	"token":             true,
+	"access_token":      true,
+	"refresh_token":     true,
+	"authorization":     true,
+	"cookie":            true,
+	"set-cookie":        true,
+	"code":              true,
+}
+
+// IsSensitive verilen alan adının maskelenmesi gerekip gerekmediğini döner
+func IsSensitive(key string) bool {
+	return sensitiveKeys[strings.ToLower(key)]
+}
+
+func redactAttr(groups []string, attr slog.Attr) slog.Attr {
+	if IsSensitive(attr.Key) {
+		return slog.String(attr.Key, Redacted)
+	}
+	return attr
+}
+
+// contextHandler context'te request ID ve trace varsa kayda ekler
+type contextHandler struct {
+	slog.Handler
+}
+
+func (h *contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if id := RequestID(ctx); id != "" {
+		record.AddAttrs(slog.String("request_id", id))
+	}
+	if span := trace.SpanContextFromContext(ctx); span.IsValid() {
+		record.AddAttrs(slog.String("trace_id", span.TraceID().String()), slog.String("span_id", span.SpanID().String()))
+	}
+	return h.Handler.Handle(ctx, record)
+}
+
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithGroup(name)}
+}
+
+type requestIDKey struct{}
+
+// WithRequestID request ID'yi context'e ekler; bu context ile yapılan loglama ve GORM sorguları ID'yi taşır
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID context'teki request ID'yi döner, yoksa boş döner
+func RequestID(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
@@ -0,0 +1,64 @@
+package mailer
+
+import (
+	"fmt"
+	"log/slog"
+	"net/smtp"
+	"strings"
+)
+
+// Mailer email gönderimi için kullanılan arayüz
+type Mailer interface {
+	Send(to string, subject string, body string) error
+}
+
+type Config struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+// New SMTP ayarlanmışsa SMTP mailer, aksi halde emailleri loga yazan bir mailer döner
+func New(config *Config) Mailer {
+	if config.Host == "" {
+		return &LogMailer{}
+	}
+	return &SMTPMailer{config: config}
+}
+
+type SMTPMailer struct {
+	config *Config
+}
+
+func (m *SMTPMailer) Send(to string, subject string, body string) error {
+	var auth smtp.Auth
+	if m.config.Username != "" {
+		auth = smtp.PlainAuth("", m.config.Username, m.config.Password, m.config.Host)
+	}
+
+	msg := strings.Join([]string{
+		"From: " + m.config.From,
+		"To: " + to,
+		"Subject: " + subject,
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=UTF-8",
+		"",
+		body,
+	}, "\r\n")
+
+	addr := fmt.Sprintf("%s:%s", m.config.Host, m.config.Port)
+	if err := smtp.SendMail(addr, auth, m.config.From, []string{to}, []byte(msg)); err != nil {
+		return fmt.Errorf("error sending email to %s: %v", to, err)
+	}
+	return nil
+}
+
+// LogMailer development ortamı için emailleri göndermek yerine loga yazar
+type LogMailer struct{}
+
+func (m *LogMailer) Send(to string, subject string, body string) error {
+	slog.Info("Email not sent, SMTP is not configured", "to", to, "subject", subject, "body", body)
+	return nil
+}
@@ -0,0 +1,123 @@
+// Package metrics Prometheus metriklerini tanımlar ve /metrics için handler sağlar.
+// Etiket değerleri sınırlı kümelerden gelir (route şablonu, sabit sebepler); ham path, kullanıcı adı
+// veya hata mesajı gibi sınırsız değerler etiket olarak kullanılmamalıdır.
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"prototurk/internal/database"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"gorm.io/gorm"
+)
+
+const namespace = "prototurk"
+
+// Kimlik doğrulama metriklerinde subject etiketi
+const (
+	SubjectUser  = "user"
+	SubjectAdmin = "admin"
+)
+
+// UnmatchedRoute hiçbir route'a uymayan isteklerin (404) route etiketidir
+const UnmatchedRoute = "unmatched"
+
+var registry = prometheus.NewRegistry()
+
+var (
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests by method, route template and status code.",
+	}, []string{"method", "route", "status"})
+
+	httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "HTTP request latency by method, route template and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	httpInFlight = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "http_requests_in_flight",
+		Help:      "HTTP requests currently being served.",
+	})
+
+	logins = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "auth_logins_total",
+		Help:      "Login attempts by subject (user, admin), result (success, failure) and failure reason.",
+	}, []string{"subject", "result", "reason"})
+
+	tokenFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "auth_token_validation_failures_total",
+		Help:      "Rejected bearer tokens by subject (user, admin) and reason.",
+	}, []string{"subject", "reason"})
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequests,
+		httpDuration,
+		httpInFlight,
+		logins,
+		tokenFailures,
+	)
+}
+
+// Handler kayıtlı metrikleri Prometheus text formatında sunar
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{Registry: registry})
+}
+
+// RegisterDatabase primary ve replica bağlantı havuzlarının sql.DB.Stats değerlerini
+// go_sql_* metrikleri olarak db_name etiketiyle (primary, replica_1...) kaydeder
+func RegisterDatabase(db *gorm.DB) error {
+	pools, err := database.Pools(db)
+	if err != nil {
+		return err
+	}
+	for name, pool := range pools {
+		if err := registry.Register(collectors.NewDBStatsCollector(pool, name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// HTTPRequestStarted devam eden istek sayısını artırır; dönen fonksiyon istek bitince çağrılmalıdır
+func HTTPRequestStarted() func() {
+	httpInFlight.Inc()
+	return httpInFlight.Dec
+}
+
+// ObserveHTTPRequest tamamlanan isteği kaydeder. route ham path değil, route şablonu olmalıdır (/api/admin/:id).
+func ObserveHTTPRequest(method, route string, status int, duration time.Duration) {
+	code := strconv.Itoa(status)
+	httpRequests.WithLabelValues(method, route, code).Inc()
+	httpDuration.WithLabelValues(method, route, code).Observe(duration.Seconds())
+}
+
+// LoginSucceeded başarılı girişi kaydeder
+func LoginSucceeded(subject string) {
+	logins.WithLabelValues(subject, "success", "").Inc()
+}
+
+// LoginFailed başarısız girişi sebebiyle kaydeder (invalid_credentials, banned, inactive...)
+func LoginFailed(subject, reason string) {
+	logins.WithLabelValues(subject, "failure", reason).Inc()
+}
+
+// TokenRejected doğrulanamayan veya kabul edilmeyen token'ı sebebiyle kaydeder (missing, expired, invalid_signature...)
+func TokenRejected(subject, reason string) {
+	tokenFailures.WithLabelValues(subject, reason).Inc()
+}
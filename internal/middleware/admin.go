@@ -2,27 +2,42 @@ package middleware
 
 import (
 	"net/http"
-	"os"
 	"strings"
+	"time"
 
+	"prototurk/internal/metrics"
 	"prototurk/internal/models"
 	"prototurk/pkg/response"
+	"prototurk/pkg/utils"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
 	"gorm.io/gorm"
 )
 
-func AdminJWT() gin.HandlerFunc {
+// adminPublicPaths token gerektirmeyen admin route'larıdır
+var adminPublicPaths = map[string]bool{
+	"/api/admin/login":              true,
+	"/api/admin/invitations/accept": true,
+	"/api/admin/setup":              true,
+}
+
+// adminPasswordChangePath kısıtlı (password_change) token'ların erişebildiği tek route'tur
+const adminPasswordChangePath = "/api/admin/me/password"
+
+// AdminJWT admin token'larını secret ile doğrular ve admin'i veritabanından yükler.
+// Parolası passwordPolicy'ye göre süresi dolan admin'in token'ı da sadece parola değiştirebilir.
+func AdminJWT(db *gorm.DB, secret []byte, passwordPolicy models.AdminPasswordPolicy) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Public routes için middleware'i atla
-		if c.Request.URL.Path == "/api/admin/login" {
+		if adminPublicPaths[c.Request.URL.Path] {
 			c.Next()
 			return
 		}
 
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
+			metrics.TokenRejected(metrics.SubjectAdmin, "missing")
 			c.JSON(http.StatusUnauthorized, response.Error("UNAUTHORIZED", "Authorization header required", nil))
 			c.Abort()
 			return
@@ -30,10 +45,11 @@ func AdminJWT() gin.HandlerFunc {
 
 		tokenString := strings.Replace(authHeader, "Bearer ", "", 1)
 		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			return []byte(os.Getenv("JWT_SECRET")), nil
+			return secret, nil
 		})
 
 		if err != nil || !token.Valid {
+			metrics.TokenRejected(metrics.SubjectAdmin, tokenFailureReason(err))
 			c.JSON(http.StatusUnauthorized, response.Error("UNAUTHORIZED", "Invalid token", nil))
 			c.Abort()
 			return
@@ -41,6 +57,7 @@ func AdminJWT() gin.HandlerFunc {
 
 		claims, ok := token.Claims.(jwt.MapClaims)
 		if !ok {
+			metrics.TokenRejected(metrics.SubjectAdmin, "invalid_claims")
 			c.JSON(http.StatusUnauthorized, response.Error("UNAUTHORIZED", "Invalid token claims", nil))
 			c.Abort()
 			return
@@ -48,16 +65,17 @@ func AdminJWT() gin.HandlerFunc {
 
 		// Admin ID'yi context'e ekle
 		adminID, ok := claims["admin_id"].(float64)
-		if !ok {
+		if typ, _ := claims[TokenTypeClaim].(string); !ok || typ != TokenTypeAdmin {
+			metrics.TokenRejected(metrics.SubjectAdmin, "invalid_claims")
 			c.JSON(http.StatusUnauthorized, response.Error("UNAUTHORIZED", "Invalid admin token", nil))
 			c.Abort()
 			return
 		}
 
 		// Admin'i veritabanından kontrol et
-		db := c.MustGet("db").(*gorm.DB)
 		var admin models.Admin
-		if err := db.First(&admin, uint(adminID)).Error; err != nil {
+		if err := db.WithContext(c.Request.Context()).First(&admin, uint(adminID)).Error; err != nil {
+			metrics.TokenRejected(metrics.SubjectAdmin, "admin_not_found")
 			c.JSON(http.StatusUnauthorized, response.Error("UNAUTHORIZED", "Admin not found", nil))
 			c.Abort()
 			return
@@ -65,11 +83,27 @@ func AdminJWT() gin.HandlerFunc {
 
 		// Admin aktif mi kontrol et
 		if !admin.IsActive() {
+			metrics.TokenRejected(metrics.SubjectAdmin, "admin_inactive")
 			c.JSON(http.StatusForbidden, response.Error("FORBIDDEN", "Admin account is not active", nil))
 			c.Abort()
 			return
 		}
 
+		// Parola değiştirmesi gereken admin'in kısıtlı token'ı sadece parola değiştirebilir.
+		// Token alındıktan sonra super admin tarafından parola değişikliği zorunlu kılınmış veya parolanın süresi dolmuş olabilir.
+		scope, _ := claims["scope"].(string)
+		mustChange := scope == models.AdminTokenScopePasswordChange || passwordPolicy.RequiresPasswordChange(&admin)
+		if mustChange && c.Request.URL.Path != adminPasswordChangePath {
+			c.JSON(http.StatusForbidden, response.Error("PASSWORD_CHANGE_REQUIRED", "Password must be changed before continuing", nil))
+			c.Abort()
+			return
+		}
+
+		// Son yeniden doğrulama zamanı (step-up) hassas işlemler için context'e eklenir
+		if reauthAt, ok := claims["reauth_at"].(float64); ok {
+			c.Set("admin_reauth_at", time.Unix(int64(reauthAt), 0).UTC())
+		}
+
 		// Admin bilgilerini context'e ekle
 		c.Set("admin_id", uint(adminID))
 		c.Set("admin_role", admin.Role)
@@ -78,3 +112,24 @@ func AdminJWT() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// RecentlyReauthenticated admin'in son window süresi içinde /api/admin/reauth ile parolasını doğrulayıp doğrulamadığını döner
+func RecentlyReauthenticated(c *gin.Context, window time.Duration) bool {
+	value, exists := c.Get("admin_reauth_at")
+	if !exists {
+		return false
+	}
+	return utils.Now().Sub(value.(time.Time)) <= window
+}
+
+// RequireReauth hassas route'lar için yakın zamanda yeniden doğrulama yapılmasını zorunlu kılar
+func RequireReauth(window time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !RecentlyReauthenticated(c, window) {
+			c.JSON(http.StatusForbidden, response.Error("REAUTH_REQUIRED", "Recent re-authentication required", nil))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,207 @@
+package middleware
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CORSConfig farklı origin'de çalışan frontend'lerin API'ye tarayıcıdan erişimini tanımlar.
+// AllowedOrigins boşsa CORS başlığı yazılmaz ve tarayıcılar sadece aynı origin'den erişebilir.
+type CORSConfig struct {
+	// AllowedOrigins tam origin'ler (https://app.example.com), alt alan adı wildcard'ları
+	// (https://*.example.com) veya tüm origin'ler için "*" içerebilir
+	AllowedOrigins   []string      `yaml:"allowed_origins"`
+	AllowedMethods   []string      `yaml:"allowed_methods"`
+	AllowedHeaders   []string      `yaml:"allowed_headers"`
+	ExposedHeaders   []string      `yaml:"exposed_headers"`
+	AllowCredentials bool          `yaml:"allow_credentials"`
+	MaxAge           time.Duration `yaml:"max_age"`
+}
+
+// DefaultCORSConfig origin tanımlanmadığı için CORS'u kapalı, diğer ayarları frontend'in ihtiyacına göre döner
+func DefaultCORSConfig() CORSConfig {
+	return CORSConfig{
+		AllowedMethods: []string{http.MethodGet, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete},
+		AllowedHeaders: []string{"Authorization", "Content-Type", RequestIDHeader, "traceparent"},
+		ExposedHeaders: []string{RequestIDHeader, "traceparent"},
+		MaxAge:         10 * time.Minute,
+	}
+}
+
+// Validate origin'lerin geçerli olduğunu ve credential'ların "*" ile birlikte açılmadığını kontrol eder
+func (c CORSConfig) Validate() error {
+	var errs []error
+	origins, err := parseOrigins(c.AllowedOrigins)
+	if err != nil {
+		errs = append(errs, err)
+	}
+	if origins.any && c.AllowCredentials {
+		errs = append(errs, errors.New("cors allowed origin \"*\" cannot be used with credentials"))
+	}
+	if c.MaxAge < 0 {
+		errs = append(errs, errors.New("cors max age must not be negative"))
+	}
+	return errors.Join(errs...)
+}
+
+// originPattern https://*.example.com gibi bir alt alan adı wildcard'ıdır
+type originPattern struct {
+	scheme string
+	// suffix ".example.com" veya port ile ".example.com:8443"
+	suffix string
+}
+
+type originList struct {
+	any      bool
+	exact    map[string]bool
+	patterns []originPattern
+}
+
+func (l originList) allows(origin string) bool {
+	if l.any {
+		return true
+	}
+	if l.exact[origin] {
+		return true
+	}
+	scheme, host, ok := strings.Cut(origin, "://")
+	if !ok {
+		return false
+	}
+	for _, pattern := range l.patterns {
+		if scheme == pattern.scheme && len(host) > len(pattern.suffix) && strings.HasSuffix(host, pattern.suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// parseOrigins origin listesini karşılaştırılabilir hale getirir. Path, query veya kullanıcı bilgisi içeren
+// değerler origin olmadığı için reddedilir.
+func parseOrigins(values []string) (originList, error) {
+	list := originList{exact: make(map[string]bool)}
+	for _, value := range values {
+		value = strings.ToLower(strings.TrimSpace(value))
+		if value == "*" {
+			list.any = true
+			continue
+		}
+
+		u, err := url.Parse(value)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" ||
+			(u.Path != "" && u.Path != "/") || u.RawQuery != "" || u.User != nil {
+			return list, fmt.Errorf("invalid cors allowed origin %q", value)
+		}
+
+		if rest, ok := strings.CutPrefix(u.Host, "*."); ok {
+			if rest == "" || strings.Contains(rest, "*") {
+				return list, fmt.Errorf("invalid cors allowed origin %q", value)
+			}
+			list.patterns = append(list.patterns, originPattern{scheme: u.Scheme, suffix: "." + rest})
+			continue
+		}
+		if strings.Contains(u.Host, "*") {
+			return list, fmt.Errorf("invalid cors allowed origin %q", value)
+		}
+		list.exact[u.Scheme+"://"+u.Host] = true
+	}
+	return list, nil
+}
+
+// CORS izin verilen origin'lerden gelen isteklere CORS başlıklarını ekler ve preflight (OPTIONS) isteklerini
+// route'a ve kimlik doğrulamaya ulaşmadan yanıtlar. İzin verilmeyen origin'lerin preflight'ı 403 ile reddedilir;
+// diğer istekleri CORS başlığı olmadan devam eder ve tarayıcı yanıtı sayfaya vermez.
+// Config açılışta Validate ile doğrulanmış olmalıdır.
+func CORS(config CORSConfig) gin.HandlerFunc {
+	origins, _ := parseOrigins(config.AllowedOrigins)
+	enabled := origins.any || len(origins.exact) > 0 || len(origins.patterns) > 0
+
+	methods := make(map[string]bool, len(config.AllowedMethods))
+	for _, method := range config.AllowedMethods {
+		methods[strings.ToUpper(method)] = true
+	}
+	headers := make(map[string]bool, len(config.AllowedHeaders))
+	for _, header := range config.AllowedHeaders {
+		headers[http.CanonicalHeaderKey(header)] = true
+	}
+	allowMethods := strings.Join(config.AllowedMethods, ", ")
+	allowHeaders := strings.Join(config.AllowedHeaders, ", ")
+	exposeHeaders := strings.Join(config.ExposedHeaders, ", ")
+	maxAge := strconv.Itoa(int(config.MaxAge.Seconds()))
+
+	return func(c *gin.Context) {
+		origin := c.GetHeader("Origin")
+		if !enabled || origin == "" {
+			c.Next()
+			return
+		}
+
+		header := c.Writer.Header()
+		// Yanıt origin'e göre değiştiği için cache'ler origin bazında ayrılmalıdır
+		header.Add("Vary", "Origin")
+		preflight := c.Request.Method == http.MethodOptions && c.GetHeader("Access-Control-Request-Method") != ""
+		if preflight {
+			header.Add("Vary", "Access-Control-Request-Method")
+			header.Add("Vary", "Access-Control-Request-Headers")
+		}
+
+		if !origins.allows(strings.ToLower(origin)) {
+			if preflight {
+				c.AbortWithStatus(http.StatusForbidden)
+				return
+			}
+			c.Next()
+			return
+		}
+		if preflight && !preflightAllowed(c, methods, headers) {
+			c.AbortWithStatus(http.StatusForbidden)
+			return
+		}
+
+		if origins.any && !config.AllowCredentials {
+			header.Set("Access-Control-Allow-Origin", "*")
+		} else {
+			header.Set("Access-Control-Allow-Origin", origin)
+		}
+		if config.AllowCredentials {
+			header.Set("Access-Control-Allow-Credentials", "true")
+		}
+
+		if !preflight {
+			if exposeHeaders != "" {
+				header.Set("Access-Control-Expose-Headers", exposeHeaders)
+			}
+			c.Next()
+			return
+		}
+
+		header.Set("Access-Control-Allow-Methods", allowMethods)
+		if allowHeaders != "" {
+			header.Set("Access-Control-Allow-Headers", allowHeaders)
+		}
+		if config.MaxAge > 0 {
+			header.Set("Access-Control-Max-Age", maxAge)
+		}
+		c.AbortWithStatus(http.StatusNoContent)
+	}
+}
+
+// preflightAllowed preflight'ta istenen method ve başlıkların izin verilenler arasında olup olmadığını döner
+func preflightAllowed(c *gin.Context, methods, headers map[string]bool) bool {
+	if !methods[strings.ToUpper(c.GetHeader("Access-Control-Request-Method"))] {
+		return false
+	}
+	for _, requested := range strings.Split(c.GetHeader("Access-Control-Request-Headers"), ",") {
+		if requested = strings.TrimSpace(requested); requested != "" && !headers[http.CanonicalHeaderKey(requested)] {
+			return false
+		}
+	}
+	return true
+}
@@ -2,26 +2,28 @@ package middleware
 
 import (
 	"net/http"
-	"os"
 	"strings"
 
+	"prototurk/internal/audit"
+	"prototurk/internal/metrics"
+	"prototurk/internal/models"
 	"prototurk/pkg/response"
 
 	"github.com/gin-gonic/gin"
 	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/gorm"
 )
 
-func JWT() gin.HandlerFunc {
-	return func(c *gin.Context) {
-		// Add JWT secret to context
-		jwtSecret := os.Getenv("JWT_SECRET")
-		if jwtSecret == "" {
-			c.JSON(http.StatusInternalServerError, response.Error("SERVER_ERROR", "JWT secret not configured", nil))
-			c.Abort()
-			return
-		}
-		c.Set("jwt_secret", jwtSecret)
+// Kullanıcı ve admin token'ları aynı secret ile imzalandığı için typ claim'i token'ın kime verildiğini belirtir
+const (
+	TokenTypeClaim = "typ"
+	TokenTypeUser  = "user"
+	TokenTypeAdmin = "admin"
+)
 
+// JWT kullanıcı token'larını secret ile doğrular. Secret açılışta config paketinde doğrulanır.
+func JWT(db *gorm.DB, secret []byte) gin.HandlerFunc {
+	return func(c *gin.Context) {
 		// Skip auth for login and register endpoints
 		if c.Request.URL.Path == "/api/auth/login" || c.Request.URL.Path == "/api/auth/register" {
 			c.Next()
@@ -30,6 +32,7 @@ func JWT() gin.HandlerFunc {
 
 		authHeader := c.GetHeader("Authorization")
 		if authHeader == "" {
+			metrics.TokenRejected(metrics.SubjectUser, "missing")
 			c.JSON(http.StatusUnauthorized, response.Error("UNAUTHORIZED", "No authorization header", nil))
 			c.Abort()
 			return
@@ -37,23 +40,95 @@ func JWT() gin.HandlerFunc {
 
 		tokenString := strings.Replace(authHeader, "Bearer ", "", 1)
 		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			return []byte(jwtSecret), nil
+			return secret, nil
 		})
 
 		if err != nil {
+			metrics.TokenRejected(metrics.SubjectUser, tokenFailureReason(err))
 			c.JSON(http.StatusUnauthorized, response.Error("UNAUTHORIZED", "Invalid token", err.Error()))
 			c.Abort()
 			return
 		}
 
-		if claims, ok := token.Claims.(jwt.MapClaims); ok && token.Valid {
-			c.Set("user_id", uint(claims["user_id"].(float64)))
-			c.Set("username", claims["username"].(string))
+		claims, ok := token.Claims.(jwt.MapClaims)
+		if !ok || !token.Valid {
+			metrics.TokenRejected(metrics.SubjectUser, "invalid_claims")
+			c.JSON(http.StatusUnauthorized, response.Error("UNAUTHORIZED", "Invalid token claims", nil))
+			c.Abort()
+			return
+		}
+
+		// Admin token'ları kullanıcı route'larında geçerli değildir
+		rawUserID, hasUserID := claims["user_id"].(float64)
+		username, hasUsername := claims["username"].(string)
+		if typ, _ := claims[TokenTypeClaim].(string); typ != TokenTypeUser || !hasUserID || !hasUsername {
+			metrics.TokenRejected(metrics.SubjectUser, "invalid_claims")
+			c.JSON(http.StatusUnauthorized, response.Error("INVALID_TOKEN", "Invalid user token", nil))
+			c.Abort()
+			return
+		}
+
+		userID := uint(rawUserID)
+		c.Set("user_id", userID)
+		c.Set("username", username)
+
+		// Impersonation token'ları imp claim'i ile işaretlenir ve act claim'inde işlemi yapan admin'i taşır
+		impersonated, _ := claims["imp"].(bool)
+		act, hasActor := claims["act"].(map[string]interface{})
+		if !impersonated && !hasActor {
 			c.Next()
-		} else {
+			return
+		}
+
+		impersonatorID, ok := act["admin_id"].(float64)
+		if !impersonated || !ok {
+			metrics.TokenRejected(metrics.SubjectUser, "invalid_claims")
 			c.JSON(http.StatusUnauthorized, response.Error("UNAUTHORIZED", "Invalid token claims", nil))
 			c.Abort()
 			return
 		}
+		c.Set("impersonator_id", uint(impersonatorID))
+
+		// Token alındıktan sonra silinen, pasife alınan veya yetkisi kaldırılan admin impersonation'a devam edemez
+		var impersonator models.Admin
+		if err := db.WithContext(c.Request.Context()).First(&impersonator, uint(impersonatorID)).Error; err != nil ||
+			!impersonator.IsActive() || !impersonator.HasPermission(models.AdminPermissionImpersonateUsers) {
+			metrics.TokenRejected(metrics.SubjectUser, "impersonation_forbidden")
+			c.JSON(http.StatusForbidden, response.Error("IMPERSONATION_FORBIDDEN", "Impersonation is no longer allowed", nil))
+			c.Abort()
+			return
+		}
+
+		// Token alındıktan sonra yasaklanan kullanıcılar impersonation ile de görüntülenemez
+		var user models.User
+		if err := db.WithContext(c.Request.Context()).First(&user, userID).Error; err != nil || user.Status == models.UserStatusBanned {
+			metrics.TokenRejected(metrics.SubjectUser, "impersonation_forbidden")
+			c.JSON(http.StatusForbidden, response.Error("IMPERSONATION_FORBIDDEN", "User cannot be impersonated", nil))
+			c.Abort()
+			return
+		}
+
+		c.Next()
+
+		audit.RecordForAdmin(c, db, uint(impersonatorID), models.AuditActionImpersonatedRequest, "user", userID, map[string]interface{}{
+			"method": c.Request.Method,
+			"path":   c.FullPath(),
+			"status": c.Writer.Status(),
+		})
+	}
+}
+
+// ImpersonatorID istek bir impersonation token'ı ile yapılıyorsa işlemi yapan admin'in ID'sini döner
+func ImpersonatorID(c *gin.Context) (uint, bool) {
+	id, exists := c.Get("impersonator_id")
+	if !exists {
+		return 0, false
 	}
+	return id.(uint), true
+}
+
+// IsImpersonated isteğin bir admin tarafından kullanıcı adına yapılıp yapılmadığını döner
+func IsImpersonated(c *gin.Context) bool {
+	_, ok := ImpersonatorID(c)
+	return ok
 }
@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"prototurk/internal/metrics"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// metricMethods dışındaki HTTP methodları etiket sayısını sınırlamak için "OTHER" olarak kaydedilir
+var metricMethods = map[string]bool{
+	http.MethodGet:     true,
+	http.MethodHead:    true,
+	http.MethodPost:    true,
+	http.MethodPut:     true,
+	http.MethodPatch:   true,
+	http.MethodDelete:  true,
+	http.MethodOptions: true,
+}
+
+// Metrics her isteği method, route şablonu ve status koduyla kaydeder.
+// Ham path kullanılmaz; route'a uymayan istekler tek bir "unmatched" etiketinde toplanır.
+func Metrics() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		done := metrics.HTTPRequestStarted()
+		defer done()
+
+		c.Next()
+
+		method := c.Request.Method
+		if !metricMethods[method] {
+			method = "OTHER"
+		}
+		route := c.FullPath()
+		if route == "" {
+			route = metrics.UnmatchedRoute
+		}
+		metrics.ObserveHTTPRequest(method, route, c.Writer.Status(), time.Since(start))
+	}
+}
+
+// tokenFailureReason jwt.Parse hatasını metrik etiketi olarak kullanılabilecek sabit bir sebebe çevirir
+func tokenFailureReason(err error) string {
+	switch {
+	case errors.Is(err, jwt.ErrTokenExpired):
+		return "expired"
+	case errors.Is(err, jwt.ErrTokenSignatureInvalid):
+		return "invalid_signature"
+	case errors.Is(err, jwt.ErrTokenMalformed):
+		return "malformed"
+	default:
+		return "invalid"
+	}
+}
@@ -0,0 +1,161 @@
+package middleware
+
+import (
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"strings"
+
+	"prototurk/internal/audit"
+	"prototurk/internal/models"
+	"prototurk/pkg/response"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// NetworkPolicyConfig admin API'sinin erişilebileceği ağları tanımlar.
+// AllowedNetworks, veritabanındaki global kurallarla birlikte değerlendirilir.
+type NetworkPolicyConfig struct {
+	AllowedNetworks []*net.IPNet
+	// Bypass acil durumlarda (örneğin yanlış kural ile kilitlenme) politikayı devre dışı bırakır
+	Bypass bool
+}
+
+// ParseNetworks virgülle ayrılmış CIDR veya IP listesini ağ aralıklarına çevirir
+func ParseNetworks(value string) ([]*net.IPNet, error) {
+	var networks []*net.IPNet
+	for _, part := range strings.Split(value, ",") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		network, err := models.ParseCIDR(part)
+		if err != nil {
+			return nil, fmt.Errorf("invalid network %q: %v", part, err)
+		}
+		networks = append(networks, network)
+	}
+	return networks, nil
+}
+
+// AdminNetworkGlobalPolicy admin isteklerini ortam değişkeni ve veritabanındaki global IP allowlist'lerine göre
+// filtreler. Engellenen ağlardan gelen isteklerin token doğrulamasına ve admin sorgusuna ulaşmaması için AdminJWT'den
+// önce kullanılmalıdır. İstemci IP'si Gin'in trusted proxy ayarına göre belirlenir.
+func AdminNetworkGlobalPolicy(db *gorm.DB, config NetworkPolicyConfig, recorder audit.Recorder) gin.HandlerFunc {
+	if config.Bypass {
+		slog.Warn("Admin network policy bypass is enabled")
+	}
+
+	return func(c *gin.Context) {
+		if config.Bypass {
+			c.Next()
+			return
+		}
+
+		allowed, err := GlobalNetworkAllowed(db.WithContext(c.Request.Context()), config, c.ClientIP())
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, response.Error("SERVER_ERROR", "Error checking network policy", nil))
+			c.Abort()
+			return
+		}
+
+		if !allowed {
+			denyNetwork(c, recorder, nil)
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// AdminNetworkPolicy admin bazlı IP allowlist'lerini uygular. Global kurallar AdminNetworkGlobalPolicy'de
+// kontrol edildiği için AdminJWT'den sonra kullanılır; login gibi public route'larda bir şey yapmaz.
+func AdminNetworkPolicy(db *gorm.DB, config NetworkPolicyConfig, recorder audit.Recorder) gin.HandlerFunc {
+	checker := NewAdminNetworkChecker(db, config, recorder)
+
+	return func(c *gin.Context) {
+		id, exists := c.Get("admin_id")
+		if !exists {
+			c.Next()
+			return
+		}
+
+		if !checker.CheckAdmin(c, id.(uint)) {
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
+
+// AdminNetworkChecker login gibi admin'in henüz context'te olmadığı yerlerde admin bazlı kuralları uygular
+type AdminNetworkChecker interface {
+	// CheckAdmin izin verilmezse yanıtı yazar ve false döner
+	CheckAdmin(c *gin.Context, adminID uint) bool
+}
+
+type adminNetworkChecker struct {
+	db       *gorm.DB
+	config   NetworkPolicyConfig
+	recorder audit.Recorder
+}
+
+// NewAdminNetworkChecker kuralları verilen veritabanından okuyan bir AdminNetworkChecker oluşturur
+func NewAdminNetworkChecker(db *gorm.DB, config NetworkPolicyConfig, recorder audit.Recorder) AdminNetworkChecker {
+	return &adminNetworkChecker{db: db, config: config, recorder: recorder}
+}
+
+func (n *adminNetworkChecker) CheckAdmin(c *gin.Context, adminID uint) bool {
+	if n.config.Bypass {
+		return true
+	}
+
+	allowed, err := AdminNetworkAllowed(n.db.WithContext(c.Request.Context()), c.ClientIP(), adminID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, response.Error("SERVER_ERROR", "Error checking network policy", nil))
+		return false
+	}
+
+	if !allowed {
+		denyNetwork(c, n.recorder, &adminID)
+		return false
+	}
+	return true
+}
+
+// GlobalNetworkAllowed verilen IP'nin ortam değişkeni ve veritabanındaki global kurallara uyup uymadığını döner
+func GlobalNetworkAllowed(db *gorm.DB, config NetworkPolicyConfig, ip string) (bool, error) {
+	var rules []models.AdminNetworkRule
+	if err := db.Where("admin_id IS NULL").Find(&rules).Error; err != nil {
+		return false, err
+	}
+	return models.NetworkAllowed(net.ParseIP(ip), config.AllowedNetworks, rules), nil
+}
+
+// AdminNetworkAllowed verilen IP'nin admin'e ait kurallara uyup uymadığını döner
+func AdminNetworkAllowed(db *gorm.DB, ip string, adminID uint) (bool, error) {
+	var rules []models.AdminNetworkRule
+	if err := db.Where("admin_id = ?", adminID).Find(&rules).Error; err != nil {
+		return false, err
+	}
+	return models.NetworkAllowed(net.ParseIP(ip), nil, rules), nil
+}
+
+func denyNetwork(c *gin.Context, recorder audit.Recorder, adminID *uint) {
+	slog.WarnContext(c.Request.Context(), "Admin network policy denied request", "method", c.Request.Method, "path", c.Request.URL.Path, "client_ip", c.ClientIP())
+
+	details := map[string]interface{}{
+		"method": c.Request.Method,
+		"path":   c.Request.URL.Path,
+	}
+	if adminID != nil {
+		recorder.RecordForAdmin(c, *adminID, models.AuditActionNetworkDenied, "admin", *adminID, details)
+	} else {
+		recorder.Record(c, models.AuditActionNetworkDenied, "", 0, details)
+	}
+
+	c.JSON(http.StatusForbidden, response.Error("NETWORK_NOT_ALLOWED", "Access from this network is not allowed", nil))
+	c.Abort()
+}
@@ -0,0 +1,118 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"net/url"
+	"regexp"
+	"runtime/debug"
+	"time"
+
+	"prototurk/internal/logging"
+	"prototurk/pkg/response"
+	"prototurk/pkg/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestIDHeader isteği load balancer, uygulama ve loglar arasında izlemek için kullanılan başlıktır
+const RequestIDHeader = "X-Request-ID"
+
+// validRequestID dışarıdan gelen ID'lerin loglara zarar vermeyecek karakter ve uzunlukta olmasını sağlar
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._:-]{1,128}$`)
+
+// quietPaths health check gibi sık çağrılan ve sadece debug seviyesinde loglanan route'lardır
+var quietPaths = map[string]bool{
+	"/healthz": true,
+	"/readyz":  true,
+}
+
+// RequestID gelen X-Request-ID başlığını kabul eder, yoksa veya geçersizse yeni bir ID üretir.
+// ID yanıt başlığına yazılır ve request context'ine eklenir; bu context ile yapılan loglar ve GORM sorguları ID'yi taşır.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(RequestIDHeader)
+		if !validRequestID.MatchString(id) {
+			generated, err := utils.RandomToken(16)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, response.Error("SERVER_ERROR", "Error processing request", nil))
+				c.Abort()
+				return
+			}
+			id = generated
+		}
+
+		c.Set("request_id", id)
+		c.Header(RequestIDHeader, id)
+		c.Request = c.Request.WithContext(logging.WithRequestID(c.Request.Context(), id))
+		c.Next()
+	}
+}
+
+// RequestLogger her isteği süre, status ve (varsa) kullanıcı/admin ID'si ile loglar.
+// 5xx yanıtlar error, 4xx yanıtlar warn seviyesindedir; query parametrelerindeki hassas değerler maskelenir.
+func RequestLogger(logger *slog.Logger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		status := c.Writer.Status()
+		attrs := []any{
+			"method", c.Request.Method,
+			"path", c.Request.URL.Path,
+			"route", c.FullPath(),
+			"status", status,
+			"latency_ms", float64(time.Since(start).Microseconds()) / 1000,
+			"bytes", c.Writer.Size(),
+			"client_ip", c.ClientIP(),
+			"user_agent", c.Request.UserAgent(),
+		}
+		if query := redactQuery(c.Request.URL.Query()); query != "" {
+			attrs = append(attrs, "query", query)
+		}
+		for _, key := range []string{"user_id", "admin_id", "impersonator_id"} {
+			if id, exists := c.Get(key); exists {
+				attrs = append(attrs, key, id)
+			}
+		}
+		if len(c.Errors) > 0 {
+			attrs = append(attrs, "errors", c.Errors.String())
+		}
+
+		level := slog.LevelInfo
+		switch {
+		case status >= http.StatusInternalServerError:
+			level = slog.LevelError
+		case status >= http.StatusBadRequest:
+			level = slog.LevelWarn
+		case quietPaths[c.Request.URL.Path]:
+			level = slog.LevelDebug
+		}
+		logger.Log(c.Request.Context(), level, "HTTP request", attrs...)
+	}
+}
+
+// Recovery panic'leri stack trace ile loglar ve standart hata yanıtı döner
+func Recovery(logger *slog.Logger) gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(nil, func(c *gin.Context, recovered any) {
+		logger.ErrorContext(c.Request.Context(), "Panic recovered",
+			"panic", recovered,
+			"method", c.Request.Method,
+			"path", c.Request.URL.Path,
+			"stack", string(debug.Stack()),
+		)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, response.Error("SERVER_ERROR", "Internal server error", nil))
+	})
+}
+
+func redactQuery(query url.Values) string {
+	if len(query) == 0 {
+		return ""
+	}
+	for key := range query {
+		if logging.IsSensitive(key) {
+			query[key] = []string{logging.Redacted}
+		}
+	}
+	return query.Encode()
+}
@@ -0,0 +1,118 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"prototurk/pkg/response"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/gin-gonic/gin/otelgin"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// untracedPaths orkestratör ve Prometheus'un sık çağırdığı, span üretilmeyen route'lardır
+var untracedPaths = map[string]bool{
+	"/healthz": true,
+	"/readyz":  true,
+	"/metrics": true,
+}
+
+// Tracing her istek için route şablonu adıyla bir span başlatır. Gelen W3C traceparent başlığı varsa
+// span çağıranın trace'ine bağlanır. Span request context'ine eklendiği için handler'lar, loglar ve
+// GORM sorguları aynı trace'i kullanır; bu yüzden zincirin başında kullanılmalıdır.
+func Tracing(serviceName string) gin.HandlerFunc {
+	return otelgin.Middleware(serviceName, otelgin.WithFilter(func(r *http.Request) bool {
+		return !untracedPaths[r.URL.Path]
+	}))
+}
+
+// TraceResponse isteğin trace-context'ini traceparent başlığı olarak yanıta yazar; istemciler ve
+// proxy'ler yanıtı trace ile eşleştirebilir. JSON hata yanıtlarına (response.Error) trace ID'yi error.trace_id
+// olarak ekler; böylece handler'ların trace ile ilgilenmesi gerekmez. Tracing'den sonra kullanılmalıdır.
+func TraceResponse() gin.HandlerFunc {
+	propagator := propagation.TraceContext{}
+	return func(c *gin.Context) {
+		propagator.Inject(c.Request.Context(), propagation.HeaderCarrier(c.Writer.Header()))
+
+		span := trace.SpanContextFromContext(c.Request.Context())
+		if !span.IsValid() {
+			c.Next()
+			return
+		}
+
+		writer := &errorTraceWriter{ResponseWriter: c.Writer}
+		c.Writer = writer
+		c.Next()
+		c.Writer = writer.ResponseWriter
+		writer.flush(c, span.TraceID().String())
+	}
+}
+
+// errorTraceWriter 4xx/5xx JSON yanıtlarının gövdesini bellekte tutar; trace ID istek bittiğinde eklenip yazılır.
+// Diğer yanıtlar (başarılı yanıtlar, CSV indirmeleri) olduğu gibi aktarılır.
+type errorTraceWriter struct {
+	gin.ResponseWriter
+	body *bytes.Buffer
+}
+
+func (w *errorTraceWriter) Write(data []byte) (int, error) {
+	if w.buffering() {
+		return w.body.Write(data)
+	}
+	return w.ResponseWriter.Write(data)
+}
+
+func (w *errorTraceWriter) WriteString(s string) (int, error) {
+	if w.buffering() {
+		return w.body.WriteString(s)
+	}
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *errorTraceWriter) Written() bool {
+	return w.body != nil || w.ResponseWriter.Written()
+}
+
+func (w *errorTraceWriter) Size() int {
+	if w.body != nil {
+		return w.body.Len()
+	}
+	return w.ResponseWriter.Size()
+}
+
+// buffering ilk yazmada yanıtın bir JSON hata yanıtı olup olmadığına karar verir
+func (w *errorTraceWriter) buffering() bool {
+	if w.body == nil && !w.ResponseWriter.Written() && w.Status() >= http.StatusBadRequest &&
+		strings.HasPrefix(w.Header().Get("Content-Type"), "application/json") {
+		w.body = &bytes.Buffer{}
+	}
+	return w.body != nil
+}
+
+// flush tutulan gövdeye trace ID'yi ekleyip yazar. Gövde bir hata zarfı değilse değiştirilmeden yazılır.
+func (w *errorTraceWriter) flush(c *gin.Context, traceID string) {
+	if w.body == nil {
+		return
+	}
+
+	body := w.body.Bytes()
+	var envelope response.Response
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	// Details içindeki büyük sayılar float64'e çevrilip yuvarlanmasın
+	decoder.UseNumber()
+	if err := decoder.Decode(&envelope); err == nil && !envelope.Success && envelope.Error != nil {
+		envelope.Error.TraceID = traceID
+		if encoded, err := json.Marshal(envelope); err == nil {
+			body = encoded
+		}
+	}
+
+	if _, err := w.ResponseWriter.Write(body); err != nil {
+		slog.DebugContext(c.Request.Context(), "Error writing response", "error", err)
+	}
+}
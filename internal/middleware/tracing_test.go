@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"prototurk/pkg/response"
+
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+const testTraceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+// Hata yanıtlarına gelen traceparent'taki trace ID eklenmeli, diğer yanıtlar değişmemeli
+func TestTraceResponseAddsTraceIDToErrors(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	// tracing.Setup'ın yaptığı gibi; exporter olmadan da gelen traceparent kullanılır
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	router := gin.New()
+	router.Use(Tracing("test"), TraceResponse())
+	router.GET("/error", func(c *gin.Context) {
+		c.JSON(http.StatusNotFound, response.Error("NOT_FOUND", "Not found", gin.H{"id": uint64(1<<63 + 1)}))
+	})
+	router.GET("/ok", func(c *gin.Context) {
+		c.JSON(http.StatusOK, response.Success(gin.H{"message": "ok"}))
+	})
+	router.GET("/csv", func(c *gin.Context) {
+		c.Data(http.StatusBadRequest, "text/csv", []byte("a,b\n"))
+	})
+
+	request := func(path string, traced bool) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if traced {
+			req.Header.Set("traceparent", testTraceParent)
+		}
+		rec := httptest.NewRecorder()
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := request("/error", true)
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusNotFound)
+	}
+	var body struct {
+		Error struct {
+			Code    string
+			TraceID string `json:"trace_id"`
+			Details map[string]json.Number
+		}
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode error response %q: %v", rec.Body.String(), err)
+	}
+	if body.Error.Code != "NOT_FOUND" || body.Error.TraceID != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Fatalf("error = %+v, want NOT_FOUND with trace ID", body.Error)
+	}
+	if body.Error.Details["id"] != "9223372036854775809" {
+		t.Fatalf("details id = %s, want it unchanged", body.Error.Details["id"])
+	}
+
+	if rec := request("/error", false); rec.Code != http.StatusNotFound || !json.Valid(rec.Body.Bytes()) {
+		t.Fatalf("untraced error response = %d %q", rec.Code, rec.Body.String())
+	}
+
+	for _, path := range []string{"/ok", "/csv"} {
+		plain := request(path, false).Body.String()
+		if traced := request(path, true).Body.String(); traced != plain {
+			t.Fatalf("%s body = %q, want %q", path, traced, plain)
+		}
+	}
+}
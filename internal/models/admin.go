@@ -4,11 +4,14 @@ import (
 	"errors"
 	"time"
 
+	"prototurk/pkg/utils"
+
 	"gorm.io/gorm"
 )
 
 type AdminRole string
 type AdminStatus string
+type AdminPermission string
 
 const (
 	AdminRoleSuperAdmin AdminRole = "super_admin"
@@ -19,8 +22,23 @@ const (
 const (
 	AdminStatusActive  AdminStatus = "active"
 	AdminStatusPassive AdminStatus = "passive"
+	// AdminStatusPending davet edilmiş ama daveti henüz kabul etmemiş adminler içindir
+	AdminStatusPending AdminStatus = "pending"
+)
+
+const (
+	// AdminPermissionImpersonateUsers destek ekibinin kullanıcının gördüğünü görebilmesi içindir
+	AdminPermissionImpersonateUsers AdminPermission = "users.impersonate"
+	AdminPermissionViewStats        AdminPermission = "stats.view"
+	AdminPermissionManageUsers      AdminPermission = "users.manage"
 )
 
+// rolePermissions her rolün sahip olduğu ek yetkileri tanımlar
+var rolePermissions = map[AdminRole][]AdminPermission{
+	AdminRoleSuperAdmin: {AdminPermissionImpersonateUsers, AdminPermissionViewStats, AdminPermissionManageUsers},
+	AdminRoleAdmin:      {AdminPermissionImpersonateUsers, AdminPermissionViewStats, AdminPermissionManageUsers},
+}
+
 type Admin struct {
 	gorm.Model
 	Email     string      `gorm:"type:varchar(255);unique;not null" json:"email"`
@@ -29,8 +47,46 @@ type Admin struct {
 	Role      AdminRole   `gorm:"type:admin_role;not null" json:"role"`
 	Status    AdminStatus `gorm:"type:admin_status;not null;default:'active'" json:"status"`
 	LastLogin time.Time   `gorm:"type:timestamp with time zone" json:"last_login"`
+
+	TwoFactorSecret  string `gorm:"type:varchar(64)" json:"-"`
+	TwoFactorEnabled bool   `gorm:"not null;default:false" json:"two_factor_enabled"`
+
+	MustChangePassword bool       `gorm:"not null;default:false" json:"must_change_password"`
+	PasswordChangedAt  *time.Time `gorm:"type:timestamp with time zone" json:"password_changed_at"`
+}
+
+// AdminPasswordHistory admin'in önceki parola hash'lerini tutar
+type AdminPasswordHistory struct {
+	ID           uint      `gorm:"primarykey"`
+	AdminID      uint      `gorm:"not null"`
+	PasswordHash string    `gorm:"type:varchar(255);not null"`
+	CreatedAt    time.Time `gorm:"type:timestamp with time zone"`
 }
 
+// AdminPasswordPolicy admin parolalarının geçerlilik süresini ve tekrar kullanım kuralını belirler
+type AdminPasswordPolicy struct {
+	// MaxAge sıfırdan büyükse bu süreden eski parolalar değiştirilmelidir
+	MaxAge time.Duration
+	// HistorySize mevcut parolaya ek olarak tekrar kullanılamayacak önceki parola sayısıdır
+	HistorySize int
+}
+
+// PasswordExpired kontrol eder admin'in parolasının politikaya göre süresinin dolup dolmadığını
+func (p AdminPasswordPolicy) PasswordExpired(a *Admin) bool {
+	if p.MaxAge <= 0 || a.PasswordChangedAt == nil {
+		return false
+	}
+	return utils.Now().Sub(*a.PasswordChangedAt) > p.MaxAge
+}
+
+// RequiresPasswordChange kontrol eder admin'in devam etmeden önce parolasını değiştirmesi gerekip gerekmediğini
+func (p AdminPasswordPolicy) RequiresPasswordChange(a *Admin) bool {
+	return a.MustChangePassword || p.PasswordExpired(a)
+}
+
+// AdminTokenScopePasswordChange sadece parola değiştirme endpoint'ini çağırabilen kısıtlı token'ları işaretler
+const AdminTokenScopePasswordChange = "password_change"
+
 // BeforeCreate ensures all timestamps are in UTC
 func (a *Admin) BeforeCreate(tx *gorm.DB) error {
 	a.CreatedAt = a.CreatedAt.UTC()
@@ -69,6 +125,21 @@ type UpdateAdminRequest struct {
 type AdminLoginRequest struct {
 	Email    string `json:"email" binding:"required,email"`
 	Password string `json:"password" binding:"required"`
+	Code     string `json:"code" binding:"omitempty,len=6,numeric"`
+}
+
+type ChangeAdminPasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required,min=6"`
+}
+
+type AdminReauthRequest struct {
+	Password string `json:"password" binding:"required"`
+	Code     string `json:"code" binding:"omitempty,len=6,numeric"`
+}
+
+type TwoFactorConfirmRequest struct {
+	Code string `json:"code" binding:"required,len=6,numeric"`
 }
 
 // ValidateRole kontrol eder admin rolünün geçerli olup olmadığını
@@ -94,18 +165,39 @@ func (a *Admin) CanDeleteAdmin() bool {
 	return a.Role == AdminRoleSuperAdmin
 }
 
-// IsActive kontrol eder admin'in aktif olup olmadığını
-func (a *Admin) IsActive() bool {
-	return a.Status == AdminStatusActive
+// CanManageTrash kontrol eder admin'in silinmiş adminleri yönetip yönetemeyeceğini
+func (a *Admin) CanManageTrash() bool {
+	return a.Role == AdminRoleSuperAdmin
 }
 
-// IsFirstSuperAdmin kontrol eder admin'in ilk oluşturulan super admin olup olmadığını
-func (a *Admin) IsFirstSuperAdmin(db *gorm.DB) bool {
-	var firstAdmin Admin
-	if err := db.Where("role = ?", AdminRoleSuperAdmin).Order("created_at ASC").First(&firstAdmin).Error; err != nil {
-		return false
+// CanManageInvitations kontrol eder admin'in admin daveti gönderip yönetip yönetemeyeceğini
+func (a *Admin) CanManageInvitations() bool {
+	return a.Role == AdminRoleSuperAdmin
+}
+
+// HasPermission kontrol eder admin'in rolünün verilen yetkiye sahip olup olmadığını
+func (a *Admin) HasPermission(permission AdminPermission) bool {
+	for _, p := range rolePermissions[a.Role] {
+		if p == permission {
+			return true
+		}
 	}
-	return a.ID == firstAdmin.ID
+	return false
+}
+
+// CanManageNetworkRules kontrol eder admin'in IP allowlist'ini yönetip yönetemeyeceğini
+func (a *Admin) CanManageNetworkRules() bool {
+	return a.Role == AdminRoleSuperAdmin
+}
+
+// CanReviewApprovals kontrol eder admin'in onay isteklerini onaylayıp reddedebileceğini
+func (a *Admin) CanReviewApprovals() bool {
+	return a.Role == AdminRoleSuperAdmin
+}
+
+// IsActive kontrol eder admin'in aktif olup olmadığını
+func (a *Admin) IsActive() bool {
+	return a.Status == AdminStatusActive
 }
 
 // CanUpdateRole kontrol eder admin'in rol güncelleyip güncelleyemeyeceğini
@@ -113,6 +205,11 @@ func (a *Admin) CanUpdateRole() bool {
 	return a.Role == AdminRoleSuperAdmin
 }
 
+// CanForcePasswordChange kontrol eder admin'in başka bir admin'e parola değişikliği zorunluluğu koyup koyamayacağını
+func (a *Admin) CanForcePasswordChange() bool {
+	return a.Role == AdminRoleSuperAdmin
+}
+
 // CanUpdateStatus kontrol eder admin'in status güncelleyip güncelleyemeyeceğini
 func (a *Admin) CanUpdateStatus() bool {
 	return a.Role == AdminRoleSuperAdmin
@@ -126,4 +223,5 @@ var (
 	ErrEmailExists     = errors.New("email already exists")
 	ErrInvalidPassword = errors.New("invalid password")
 	ErrAdminNotFound   = errors.New("admin not found")
+	ErrPasswordReused  = errors.New("password was used recently")
 )
@@ -0,0 +1,66 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"prototurk/pkg/utils"
+
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrInvitationNotFound açık (kabul edilmemiş ve iptal edilmemiş) bir davet bulunamadığında döner
+	ErrInvitationNotFound = errors.New("invitation not found")
+	// ErrInvitationInvalid davet token'ı geçersiz, süresi dolmuş veya davet artık kabul edilemez olduğunda döner
+	ErrInvitationInvalid = errors.New("invitation is invalid or expired")
+)
+
+// AdminInvitation bir admin'e gönderilen tek kullanımlık davet linkini temsil eder.
+// Token'ın kendisi saklanmaz, sadece SHA-256 hash'i tutulur.
+type AdminInvitation struct {
+	ID         uint       `gorm:"primarykey" json:"id"`
+	AdminID    *uint      `json:"admin_id"`
+	Email      string     `gorm:"type:varchar(255);not null" json:"email"`
+	Role       AdminRole  `gorm:"type:admin_role;not null" json:"role"`
+	TokenHash  string     `gorm:"type:varchar(64);not null" json:"-"`
+	InvitedBy  *uint      `json:"invited_by"`
+	ExpiresAt  time.Time  `gorm:"type:timestamp with time zone;not null" json:"expires_at"`
+	SentAt     *time.Time `gorm:"type:timestamp with time zone" json:"sent_at"`
+	AcceptedAt *time.Time `gorm:"type:timestamp with time zone" json:"accepted_at"`
+	RevokedAt  *time.Time `gorm:"type:timestamp with time zone" json:"revoked_at"`
+	CreatedAt  time.Time  `json:"created_at"`
+	UpdatedAt  time.Time  `json:"updated_at"`
+}
+
+// BeforeCreate ensures all timestamps are in UTC
+func (i *AdminInvitation) BeforeCreate(tx *gorm.DB) error {
+	i.CreatedAt = i.CreatedAt.UTC()
+	i.UpdatedAt = i.UpdatedAt.UTC()
+	i.ExpiresAt = i.ExpiresAt.UTC()
+	return nil
+}
+
+// BeforeUpdate ensures all timestamps are in UTC
+func (i *AdminInvitation) BeforeUpdate(tx *gorm.DB) error {
+	i.UpdatedAt = i.UpdatedAt.UTC()
+	i.ExpiresAt = i.ExpiresAt.UTC()
+	return nil
+}
+
+// IsPending kontrol eder davetin hala kabul edilebilir olup olmadığını
+func (i *AdminInvitation) IsPending() bool {
+	return i.AcceptedAt == nil && i.RevokedAt == nil && utils.Now().Before(i.ExpiresAt)
+}
+
+type CreateAdminInvitationRequest struct {
+	Email string    `json:"email" binding:"required,email"`
+	Name  string    `json:"name" binding:"required,min=2,max=100"`
+	Role  AdminRole `json:"role" binding:"required"`
+}
+
+type AcceptAdminInvitationRequest struct {
+	Token           string `json:"token" binding:"required"`
+	Password        string `json:"password" binding:"required,min=6"`
+	EnableTwoFactor bool   `json:"enable_two_factor"`
+}
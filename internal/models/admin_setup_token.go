@@ -0,0 +1,34 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// ErrSetupUnavailable kurulum token'ı geçersiz olduğunda veya kurulum zaten tamamlandığında döner
+var ErrSetupUnavailable = errors.New("setup is not available")
+
+// AdminSetupToken ilk super admin'in oluşturulması için log'a yazılan tek kullanımlık token'dır
+type AdminSetupToken struct {
+	ID        uint       `gorm:"primarykey"`
+	TokenHash string     `gorm:"type:varchar(64);not null"`
+	ExpiresAt time.Time  `gorm:"type:timestamp with time zone;not null"`
+	UsedAt    *time.Time `gorm:"type:timestamp with time zone"`
+	CreatedAt time.Time
+}
+
+// BeforeCreate ensures all timestamps are in UTC
+func (t *AdminSetupToken) BeforeCreate(tx *gorm.DB) error {
+	t.CreatedAt = t.CreatedAt.UTC()
+	t.ExpiresAt = t.ExpiresAt.UTC()
+	return nil
+}
+
+type AdminSetupRequest struct {
+	Token    string `json:"token" binding:"required"`
+	Email    string `json:"email" binding:"required,email"`
+	Name     string `json:"name" binding:"required,min=2,max=100"`
+	Password string `json:"password" binding:"required,min=6"`
+}
@@ -0,0 +1,158 @@
+package models
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"prototurk/pkg/utils"
+
+	"gorm.io/gorm"
+)
+
+// ApprovalAction ikinci bir super admin onayı gerektirebilecek işlem tipidir
+type ApprovalAction string
+
+const (
+	ApprovalActionAdminDelete     ApprovalAction = "admin.delete"
+	ApprovalActionAdminPurge      ApprovalAction = "admin.purge"
+	ApprovalActionAdminRoleChange ApprovalAction = "admin.role_change"
+	// ApprovalActionUserBulkDelete ApprovalPolicy.BulkDeleteThreshold'dan fazla kullanıcı silen toplu silmelerdir.
+	// ADMIN_APPROVAL_ACTIONS ile değil eşik ile açılır.
+	ApprovalActionUserBulkDelete ApprovalAction = "user.bulk_delete"
+)
+
+var (
+	ErrApprovalNotFound = errors.New("approval request not found")
+	// ErrApprovalPending aynı işlem ve hedef (veya hedef kümesi) için bekleyen bir onay isteği olduğunda döner
+	ErrApprovalPending = errors.New("approval request is already pending")
+)
+
+var approvalActions = map[ApprovalAction]bool{
+	ApprovalActionAdminDelete:     true,
+	ApprovalActionAdminPurge:      true,
+	ApprovalActionAdminRoleChange: true,
+}
+
+// ValidateAction kontrol eder action'ın onay akışında desteklenip desteklenmediğini
+func (a ApprovalAction) ValidateAction() bool {
+	return approvalActions[a]
+}
+
+type ApprovalStatus string
+
+const (
+	ApprovalStatusPending  ApprovalStatus = "pending"
+	ApprovalStatusApproved ApprovalStatus = "approved"
+	ApprovalStatusRejected ApprovalStatus = "rejected"
+	ApprovalStatusExpired  ApprovalStatus = "expired"
+	// ApprovalStatusFailed onaylanan işlemin çalıştırılması sırasında hata oluştuğunu belirtir
+	ApprovalStatusFailed ApprovalStatus = "failed"
+)
+
+// ApprovalRequest onay bekleyen ertelenmiş bir işlemi temsil eder.
+// İşlem sadece isteği oluşturandan farklı bir super admin onayladığında çalıştırılır.
+type ApprovalRequest struct {
+	ID         uint           `gorm:"primarykey" json:"id"`
+	Action     ApprovalAction `gorm:"type:varchar(100);not null" json:"action"`
+	TargetType string         `gorm:"type:varchar(50);not null" json:"target_type"`
+	TargetID   uint           `gorm:"not null" json:"target_id"`
+	// TargetKey birden fazla hedefi kapsayan işlemlerde hedef kümesinin özetidir (bkz. ApprovalTargetSetKey).
+	// Bekleyen istek tekilliği action, target_type, target_id ve target_key üzerinden kontrol edilir.
+	TargetKey string         `gorm:"type:varchar(64);not null;default:''" json:"target_key,omitempty"`
+	Payload   *string        `gorm:"type:jsonb" json:"payload"`
+	Status    ApprovalStatus `gorm:"type:approval_status;not null;default:pending" json:"status"`
+	// RequestedBy ve ReviewedBy ilgili admin kalıcı olarak silindiğinde boşalır
+	RequestedBy  *uint      `json:"requested_by"`
+	ReviewedBy   *uint      `json:"reviewed_by"`
+	ReviewedAt   *time.Time `gorm:"type:timestamp with time zone" json:"reviewed_at"`
+	ReviewReason *string    `json:"review_reason"`
+	ExecutedAt   *time.Time `gorm:"type:timestamp with time zone" json:"executed_at"`
+	Error        *string    `json:"error"`
+	ExpiresAt    time.Time  `gorm:"type:timestamp with time zone;not null" json:"expires_at"`
+	CreatedAt    time.Time  `json:"created_at"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+}
+
+// BeforeCreate ensures all timestamps are in UTC
+func (r *ApprovalRequest) BeforeCreate(tx *gorm.DB) error {
+	r.CreatedAt = r.CreatedAt.UTC()
+	r.UpdatedAt = r.UpdatedAt.UTC()
+	r.ExpiresAt = r.ExpiresAt.UTC()
+	return nil
+}
+
+// BeforeUpdate ensures all timestamps are in UTC
+func (r *ApprovalRequest) BeforeUpdate(tx *gorm.DB) error {
+	r.UpdatedAt = r.UpdatedAt.UTC()
+	r.ExpiresAt = r.ExpiresAt.UTC()
+	return nil
+}
+
+// IsPending kontrol eder isteğin hala onaylanabilir veya reddedilebilir olup olmadığını
+func (r *ApprovalRequest) IsPending() bool {
+	return r.Status == ApprovalStatusPending && utils.Now().Before(r.ExpiresAt)
+}
+
+// IsRequestedBy kontrol eder isteğin verilen admin tarafından oluşturulup oluşturulmadığını
+func (r *ApprovalRequest) IsRequestedBy(adminID uint) bool {
+	return r.RequestedBy != nil && *r.RequestedBy == adminID
+}
+
+// ApprovalTargetSetKey hedef ID kümesini sıralanmış ID'lerin SHA-256 özetine çevirir. Aynı kümeyi seçen
+// istekler sıradan bağımsız olarak aynı anahtarı alır.
+func ApprovalTargetSetKey(ids []uint) string {
+	sorted := slices.Clone(ids)
+	slices.Sort(sorted)
+	sorted = slices.Compact(sorted)
+
+	hash := sha256.New()
+	for _, id := range sorted {
+		hash.Write([]byte(strconv.FormatUint(uint64(id), 10)))
+		hash.Write([]byte{','})
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// ApprovalPolicy hangi işlemlerin ikinci bir onay gerektirdiğini ve isteklerin ne kadar süre geçerli olduğunu belirler
+type ApprovalPolicy struct {
+	Actions map[ApprovalAction]bool
+	TTL     time.Duration
+	// BulkDeleteThreshold sıfırdan büyükse bu sayıdan fazla kullanıcı silen toplu silmeler onay gerektirir
+	BulkDeleteThreshold int
+}
+
+// Requires kontrol eder verilen işlemin onay gerektirip gerektirmediğini
+func (p ApprovalPolicy) Requires(action ApprovalAction) bool {
+	return p.Actions[action]
+}
+
+// RequiresBulkDelete kontrol eder count kullanıcıyı silen toplu silmenin onay gerektirip gerektirmediğini
+func (p ApprovalPolicy) RequiresBulkDelete(count int64) bool {
+	return p.BulkDeleteThreshold > 0 && count > int64(p.BulkDeleteThreshold)
+}
+
+// ParseApprovalActions virgülle ayrılmış action listesini okur
+func ParseApprovalActions(value string) (map[ApprovalAction]bool, error) {
+	actions := make(map[ApprovalAction]bool)
+	for _, part := range strings.Split(value, ",") {
+		action := ApprovalAction(strings.TrimSpace(part))
+		if action == "" {
+			continue
+		}
+		if !action.ValidateAction() {
+			return nil, fmt.Errorf("unknown approval action %q", action)
+		}
+		actions[action] = true
+	}
+	return actions, nil
+}
+
+type ReviewApprovalRequest struct {
+	Reason string `json:"reason" binding:"max=500"`
+}
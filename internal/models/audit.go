@@ -0,0 +1,63 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Audit action'ları
+const (
+	AuditActionAdminDelete  = "admin.delete"
+	AuditActionAdminRestore = "admin.restore"
+	AuditActionAdminPurge   = "admin.purge"
+	AuditActionAdminSetup   = "admin.setup"
+
+	AuditActionAdminRoleChange = "admin.role_change"
+
+	AuditActionPasswordChange      = "admin.password_change"
+	AuditActionForcePasswordChange = "admin.force_password_change"
+	AuditActionReauth              = "admin.reauth"
+
+	AuditActionApprovalRequest = "approval.request"
+	AuditActionApprovalApprove = "approval.approve"
+	AuditActionApprovalReject  = "approval.reject"
+	AuditActionApprovalExpire  = "approval.expire"
+	AuditActionApprovalFail    = "approval.fail"
+
+	AuditActionInvitationCreate = "admin_invitation.create"
+	AuditActionInvitationResend = "admin_invitation.resend"
+	AuditActionInvitationRevoke = "admin_invitation.revoke"
+	AuditActionInvitationAccept = "admin_invitation.accept"
+
+	AuditActionTwoFactorEnable = "admin.two_factor_enable"
+
+	AuditActionUserImpersonate     = "user.impersonate"
+	AuditActionImpersonatedRequest = "user.impersonated_request"
+
+	AuditActionUserImport     = "user.import"
+	AuditActionUserBulkStatus = "user.bulk_status"
+	AuditActionUserBulkDelete = "user.bulk_delete"
+
+	AuditActionNetworkDenied     = "admin.network_denied"
+	AuditActionNetworkRuleCreate = "admin_network_rule.create"
+	AuditActionNetworkRuleDelete = "admin_network_rule.delete"
+)
+
+// AuditLog admin işlemlerinin kaydını tutar. AdminID sistem işlemlerinde boştur.
+type AuditLog struct {
+	ID         uint      `gorm:"primarykey" json:"id"`
+	AdminID    *uint     `json:"admin_id"`
+	Action     string    `gorm:"type:varchar(100);not null" json:"action"`
+	TargetType string    `gorm:"type:varchar(50)" json:"target_type"`
+	TargetID   *uint     `json:"target_id"`
+	IPAddress  string    `gorm:"type:varchar(45)" json:"ip_address"`
+	Details    *string   `gorm:"type:jsonb" json:"details"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// BeforeCreate ensures all timestamps are in UTC
+func (a *AuditLog) BeforeCreate(tx *gorm.DB) error {
+	a.CreatedAt = a.CreatedAt.UTC()
+	return nil
+}
@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+const (
+	StatsIntervalDay  = "day"
+	StatsIntervalWeek = "week"
+)
+
+// AdminStats admin paneli ana sayfasında gösterilen özet istatistiklerdir
+type AdminStats struct {
+	Interval      string               `json:"interval"`
+	From          time.Time            `json:"from"`
+	To            time.Time            `json:"to"`
+	Series        []StatsPoint         `json:"series"`
+	TotalUsers    int64                `json:"total_users"`
+	UsersByStatus map[UserStatus]int64 `json:"users_by_status"`
+	AdminsByRole  map[AdminRole]int64  `json:"active_admins_by_role"`
+	Growth        []StatsGrowth        `json:"growth"`
+	GeneratedAt   time.Time            `json:"generated_at"`
+}
+
+// StatsPoint bir gün veya haftadaki kayıt ve giriş sayılarıdır
+type StatsPoint struct {
+	Bucket        time.Time `json:"bucket"`
+	Registrations int64     `json:"registrations"`
+	Logins        int64     `json:"logins"`
+}
+
+// StatsGrowth son WindowDays gündeki kayıtları bir önceki aynı uzunluktaki dönemle karşılaştırır
+type StatsGrowth struct {
+	WindowDays   int      `json:"window_days"`
+	Current      int64    `json:"current"`
+	Previous     int64    `json:"previous"`
+	Delta        int64    `json:"delta"`
+	DeltaPercent *float64 `json:"delta_percent"`
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"errors"
 	"time"
 
 	"gorm.io/gorm"
@@ -14,6 +15,12 @@ const (
 	UserStatusBanned  UserStatus = "banned"
 )
 
+// Email çakışmaları için admin ile ortak ErrEmailExists kullanılır
+var (
+	ErrUserNotFound   = errors.New("user not found")
+	ErrUsernameExists = errors.New("username already exists")
+)
+
 type User struct {
 	gorm.Model
 	Username      string     `gorm:"type:varchar(32);unique;not null" json:"username"`
@@ -67,3 +74,85 @@ type UpdatePasswordRequest struct {
 func (r *UpdateProfileRequest) Validate() bool {
 	return r.Username != "" || r.Email != ""
 }
+
+// ValidateStatus checks if the user status is one of the known statuses
+func (s UserStatus) ValidateStatus() bool {
+	switch s {
+	case UserStatusActive, UserStatusPassive, UserStatusBanned:
+		return true
+	}
+	return false
+}
+
+// UserFilter selects users for bulk admin actions. Empty fields are ignored.
+type UserFilter struct {
+	Status        UserStatus `json:"status" binding:"omitempty"`
+	CreatedAfter  *time.Time `json:"created_after"`
+	CreatedBefore *time.Time `json:"created_before"`
+	EmailDomain   string     `json:"email_domain" binding:"omitempty,hostname"`
+}
+
+// IsEmpty reports whether no filter field is set
+func (f *UserFilter) IsEmpty() bool {
+	return f.Status == "" && f.CreatedAfter == nil && f.CreatedBefore == nil && f.EmailDomain == ""
+}
+
+// Apply adds the filter conditions to the query
+func (f *UserFilter) Apply(query *gorm.DB) *gorm.DB {
+	if f.Status != "" {
+		query = query.Where("status = ?", f.Status)
+	}
+	if f.CreatedAfter != nil {
+		query = query.Where("created_at >= ?", f.CreatedAfter.UTC())
+	}
+	if f.CreatedBefore != nil {
+		query = query.Where("created_at < ?", f.CreatedBefore.UTC())
+	}
+	if f.EmailDomain != "" {
+		query = query.Where("email ILIKE ?", "%@"+f.EmailDomain)
+	}
+	return query
+}
+
+// BulkUserRequest selects users either by ID list or by filter
+type BulkUserRequest struct {
+	IDs    []uint      `json:"ids" binding:"omitempty,max=10000"`
+	Filter *UserFilter `json:"filter"`
+}
+
+// BulkUserStatusRequest represents the request body for bulk status changes
+type BulkUserStatusRequest struct {
+	BulkUserRequest
+	Status UserStatus `json:"status" binding:"required"`
+}
+
+// UserImportRow is a single row of a CSV or JSON Lines user import
+type UserImportRow struct {
+	Username string     `json:"username"`
+	Email    string     `json:"email"`
+	Password string     `json:"password"`
+	Status   UserStatus `json:"status"`
+}
+
+// UserImportError describes why a row of an import was rejected
+type UserImportError struct {
+	Row     int    `json:"row"`
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// UserImportReport is the result of a user import
+type UserImportReport struct {
+	DryRun   bool              `json:"dry_run"`
+	Total    int               `json:"total"`
+	Valid    int               `json:"valid"`
+	Imported int               `json:"imported"`
+	Failed   int               `json:"failed"`
+	Errors   []UserImportError `json:"errors"`
+}
+
+// AddError appends a row error to the report
+func (r *UserImportReport) AddError(row int, field string, message string) {
+	r.Errors = append(r.Errors, UserImportError{Row: row, Field: field, Message: message})
+	r.Failed++
+}
@@ -0,0 +1,793 @@
+// Package contract repository implementasyonlarının (GORM, bellek içi) aynı davranışı gösterdiğini doğrulayan
+// ortak test senaryolarını içerir. Her implementasyonun testi kendi factory'si ile bu fonksiyonları çağırır:
+//
+//	func TestMemoryAdminRepository(t *testing.T) {
+//		contract.AdminRepository(t, func(t *testing.T) repository.AdminRepository {
+//			return repository.NewMemoryAdminRepository()
+//		})
+//	}
+//
+// Factory her çağrıda boş bir repository dönmelidir; GORM implementasyonu için tablolar temizlenmelidir.
+package contract
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"prototurk/internal/models"
+	"prototurk/internal/repository"
+)
+
+// UserRepository her UserRepository implementasyonunun sağlaması gereken davranışları test eder
+func UserRepository(t *testing.T, newRepo func(t *testing.T) repository.UserRepository) {
+	ctx := context.Background()
+
+	t.Run("CreateAndFind", func(t *testing.T) {
+		repo := newRepo(t)
+		user := &models.User{Username: "ayse", Email: "ayse@example.com", Password: "hash", Status: models.UserStatusActive}
+		if err := repo.Create(ctx, user); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+		if user.ID == 0 {
+			t.Fatal("Create did not assign an ID")
+		}
+
+		found, err := repo.FindByID(ctx, user.ID)
+		if err != nil {
+			t.Fatalf("FindByID: %v", err)
+		}
+		if found.Username != user.Username || found.Email != user.Email || found.Status != user.Status {
+			t.Fatalf("FindByID returned %+v, want %+v", found, user)
+		}
+
+		for _, identifier := range []string{"ayse", "ayse@example.com"} {
+			found, err := repo.FindByIdentifier(ctx, identifier)
+			if err != nil {
+				t.Fatalf("FindByIdentifier(%q): %v", identifier, err)
+			}
+			if found.ID != user.ID {
+				t.Fatalf("FindByIdentifier(%q) returned user %d, want %d", identifier, found.ID, user.ID)
+			}
+		}
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		repo := newRepo(t)
+		if _, err := repo.FindByID(ctx, 999999); !errors.Is(err, models.ErrUserNotFound) {
+			t.Fatalf("FindByID error = %v, want ErrUserNotFound", err)
+		}
+		if _, err := repo.FindByIdentifier(ctx, "nobody"); !errors.Is(err, models.ErrUserNotFound) {
+			t.Fatalf("FindByIdentifier error = %v, want ErrUserNotFound", err)
+		}
+	})
+
+	t.Run("DuplicateUsernameAndEmail", func(t *testing.T) {
+		repo := newRepo(t)
+		if err := repo.Create(ctx, &models.User{Username: "mehmet", Email: "mehmet@example.com", Password: "hash"}); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+
+		err := repo.Create(ctx, &models.User{Username: "mehmet", Email: "other@example.com", Password: "hash"})
+		if !errors.Is(err, models.ErrUsernameExists) {
+			t.Fatalf("duplicate username error = %v, want ErrUsernameExists", err)
+		}
+		err = repo.Create(ctx, &models.User{Username: "other", Email: "mehmet@example.com", Password: "hash"})
+		if !errors.Is(err, models.ErrEmailExists) {
+			t.Fatalf("duplicate email error = %v, want ErrEmailExists", err)
+		}
+
+		if taken, err := repo.UsernameExists(ctx, "mehmet"); err != nil || !taken {
+			t.Fatalf("UsernameExists = %v, %v; want true", taken, err)
+		}
+		if taken, err := repo.EmailExists(ctx, "free@example.com"); err != nil || taken {
+			t.Fatalf("EmailExists = %v, %v; want false", taken, err)
+		}
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		repo := newRepo(t)
+		user := &models.User{Username: "zeynep", Email: "zeynep@example.com", Password: "hash"}
+		if err := repo.Create(ctx, user); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+
+		loginAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+		err := repo.Update(ctx, user, map[string]interface{}{
+			"email":           "zeynep@example.org",
+			"status":          models.UserStatusBanned,
+			"last_login_date": loginAt,
+		})
+		if err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+		if user.Email != "zeynep@example.org" || user.Status != models.UserStatusBanned {
+			t.Fatalf("Update did not apply fields to the model: %+v", user)
+		}
+
+		found, err := repo.FindByID(ctx, user.ID)
+		if err != nil {
+			t.Fatalf("FindByID: %v", err)
+		}
+		if found.Email != "zeynep@example.org" || found.Status != models.UserStatusBanned || !found.LastLoginDate.Equal(loginAt) {
+			t.Fatalf("Update was not persisted: %+v", found)
+		}
+		if found.Username != "zeynep" {
+			t.Fatalf("Update changed a field that was not given: username = %q", found.Username)
+		}
+	})
+
+	t.Run("DeleteByIDs", func(t *testing.T) {
+		repo := newRepo(t)
+		var ids []uint
+		for _, name := range []string{"ali", "veli", "deniz"} {
+			user := &models.User{Username: name, Email: name + "@example.com", Password: "hash"}
+			if err := repo.Create(ctx, user); err != nil {
+				t.Fatalf("Create: %v", err)
+			}
+			ids = append(ids, user.ID)
+		}
+
+		// Bulunamayan ID'ler atlanır
+		deleted, err := repo.DeleteByIDs(ctx, []uint{ids[0], ids[1], 999999})
+		if err != nil {
+			t.Fatalf("DeleteByIDs: %v", err)
+		}
+		if deleted != 2 {
+			t.Fatalf("DeleteByIDs deleted %d users, want 2", deleted)
+		}
+
+		if _, err := repo.FindByID(ctx, ids[0]); !errors.Is(err, models.ErrUserNotFound) {
+			t.Fatalf("FindByID after delete error = %v, want ErrUserNotFound", err)
+		}
+		if _, err := repo.FindByIdentifier(ctx, "veli"); !errors.Is(err, models.ErrUserNotFound) {
+			t.Fatalf("FindByIdentifier after delete error = %v, want ErrUserNotFound", err)
+		}
+		if _, err := repo.FindByID(ctx, ids[2]); err != nil {
+			t.Fatalf("FindByID of remaining user: %v", err)
+		}
+
+		deleted, err = repo.DeleteByIDs(ctx, ids[:1])
+		if err != nil || deleted != 0 {
+			t.Fatalf("DeleteByIDs of deleted user = %d, %v; want 0", deleted, err)
+		}
+	})
+}
+
+// AdminRepository her AdminRepository implementasyonunun sağlaması gereken davranışları test eder
+func AdminRepository(t *testing.T, newRepo func(t *testing.T) repository.AdminRepository) {
+	ctx := context.Background()
+
+	create := func(t *testing.T, repo repository.AdminRepository, email string, role models.AdminRole) *models.Admin {
+		t.Helper()
+		admin := &models.Admin{Email: email, Name: "Admin", Password: "hash", Role: role, Status: models.AdminStatusActive}
+		if err := repo.Create(ctx, admin); err != nil {
+			t.Fatalf("Create(%s): %v", email, err)
+		}
+		return admin
+	}
+
+	t.Run("CreateFindAndList", func(t *testing.T) {
+		repo := newRepo(t)
+		first := create(t, repo, "first@example.com", models.AdminRoleSuperAdmin)
+		second := create(t, repo, "second@example.com", models.AdminRoleEditor)
+
+		found, err := repo.FindByID(ctx, second.ID)
+		if err != nil {
+			t.Fatalf("FindByID: %v", err)
+		}
+		if found.Email != second.Email || found.Role != models.AdminRoleEditor {
+			t.Fatalf("FindByID returned %+v", found)
+		}
+
+		found, err = repo.FindByEmail(ctx, "first@example.com")
+		if err != nil || found.ID != first.ID {
+			t.Fatalf("FindByEmail = %v, %v; want admin %d", found, err, first.ID)
+		}
+
+		admins, err := repo.List(ctx)
+		if err != nil {
+			t.Fatalf("List: %v", err)
+		}
+		if len(admins) != 2 || admins[0].ID != first.ID || admins[1].ID != second.ID {
+			t.Fatalf("List returned %d admins, want [first, second] ordered by ID", len(admins))
+		}
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		repo := newRepo(t)
+		if _, err := repo.FindByID(ctx, 999999); !errors.Is(err, models.ErrAdminNotFound) {
+			t.Fatalf("FindByID error = %v, want ErrAdminNotFound", err)
+		}
+		if _, err := repo.FindByEmail(ctx, "nobody@example.com"); !errors.Is(err, models.ErrAdminNotFound) {
+			t.Fatalf("FindByEmail error = %v, want ErrAdminNotFound", err)
+		}
+		if _, err := repo.FirstSuperAdmin(ctx); !errors.Is(err, models.ErrAdminNotFound) {
+			t.Fatalf("FirstSuperAdmin error = %v, want ErrAdminNotFound", err)
+		}
+	})
+
+	t.Run("EmailExists", func(t *testing.T) {
+		repo := newRepo(t)
+		admin := create(t, repo, "taken@example.com", models.AdminRoleAdmin)
+
+		if taken, err := repo.EmailExists(ctx, "taken@example.com", 0); err != nil || !taken {
+			t.Fatalf("EmailExists = %v, %v; want true", taken, err)
+		}
+		if taken, err := repo.EmailExists(ctx, "taken@example.com", admin.ID); err != nil || taken {
+			t.Fatalf("EmailExists excluding owner = %v, %v; want false", taken, err)
+		}
+	})
+
+	t.Run("FirstSuperAdmin", func(t *testing.T) {
+		repo := newRepo(t)
+		create(t, repo, "admin@example.com", models.AdminRoleAdmin)
+		first := create(t, repo, "root@example.com", models.AdminRoleSuperAdmin)
+		create(t, repo, "root2@example.com", models.AdminRoleSuperAdmin)
+
+		found, err := repo.FirstSuperAdmin(ctx)
+		if err != nil {
+			t.Fatalf("FirstSuperAdmin: %v", err)
+		}
+		if found.ID != first.ID {
+			t.Fatalf("FirstSuperAdmin = %d, want %d", found.ID, first.ID)
+		}
+	})
+
+	t.Run("Update", func(t *testing.T) {
+		repo := newRepo(t)
+		admin := create(t, repo, "update@example.com", models.AdminRoleEditor)
+
+		err := repo.Update(ctx, admin, map[string]interface{}{
+			"name":                 "Updated",
+			"role":                 models.AdminRoleAdmin,
+			"must_change_password": true,
+		})
+		if err != nil {
+			t.Fatalf("Update: %v", err)
+		}
+		if admin.Name != "Updated" || admin.Role != models.AdminRoleAdmin || !admin.MustChangePassword {
+			t.Fatalf("Update did not apply fields to the model: %+v", admin)
+		}
+
+		found, err := repo.FindByID(ctx, admin.ID)
+		if err != nil {
+			t.Fatalf("FindByID: %v", err)
+		}
+		if found.Name != "Updated" || found.Role != models.AdminRoleAdmin || !found.MustChangePassword || found.Email != "update@example.com" {
+			t.Fatalf("Update was not persisted: %+v", found)
+		}
+	})
+
+	t.Run("PasswordHistory", func(t *testing.T) {
+		repo := newRepo(t)
+		admin := create(t, repo, "history@example.com", models.AdminRoleAdmin)
+
+		for _, hash := range []string{"hash-1", "hash-2", "hash-3"} {
+			previous := admin.Password
+			if err := repo.UpdatePassword(ctx, admin, map[string]interface{}{"password": hash}, previous, 2); err != nil {
+				t.Fatalf("UpdatePassword: %v", err)
+			}
+		}
+		if admin.Password != "hash-3" {
+			t.Fatalf("UpdatePassword did not apply the password: %q", admin.Password)
+		}
+
+		history, err := repo.PasswordHistory(ctx, admin.ID, 5)
+		if err != nil {
+			t.Fatalf("PasswordHistory: %v", err)
+		}
+		// Geçmiş historySize ile sınırlıdır ve en yeniden eskiye sıralıdır
+		if len(history) != 2 || history[0] != "hash-2" || history[1] != "hash-1" {
+			t.Fatalf("PasswordHistory = %v, want [hash-2 hash-1]", history)
+		}
+
+		history, err = repo.PasswordHistory(ctx, admin.ID, 1)
+		if err != nil || len(history) != 1 || history[0] != "hash-2" {
+			t.Fatalf("PasswordHistory(limit 1) = %v, %v; want [hash-2]", history, err)
+		}
+	})
+
+	t.Run("PasswordHistoryDisabled", func(t *testing.T) {
+		repo := newRepo(t)
+		admin := create(t, repo, "nohistory@example.com", models.AdminRoleAdmin)
+
+		if err := repo.UpdatePassword(ctx, admin, map[string]interface{}{"password": "hash-1"}, admin.Password, 0); err != nil {
+			t.Fatalf("UpdatePassword: %v", err)
+		}
+		history, err := repo.PasswordHistory(ctx, admin.ID, 5)
+		if err != nil || len(history) != 0 {
+			t.Fatalf("PasswordHistory = %v, %v; want empty", history, err)
+		}
+	})
+
+	t.Run("SoftDelete", func(t *testing.T) {
+		repo := newRepo(t)
+		admin := create(t, repo, "deleted@example.com", models.AdminRoleAdmin)
+
+		if err := repo.Delete(ctx, admin); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+		if _, err := repo.FindByID(ctx, admin.ID); !errors.Is(err, models.ErrAdminNotFound) {
+			t.Fatalf("FindByID after delete error = %v, want ErrAdminNotFound", err)
+		}
+		if _, err := repo.FindByEmail(ctx, admin.Email); !errors.Is(err, models.ErrAdminNotFound) {
+			t.Fatalf("FindByEmail after delete error = %v, want ErrAdminNotFound", err)
+		}
+		admins, err := repo.List(ctx)
+		if err != nil || len(admins) != 0 {
+			t.Fatalf("List after delete = %d admins, %v; want none", len(admins), err)
+		}
+
+		// Silinmiş admin'in email'i yeni bir admin için kullanılabilir
+		if taken, err := repo.EmailExists(ctx, admin.Email, 0); err != nil || taken {
+			t.Fatalf("EmailExists after delete = %v, %v; want false", taken, err)
+		}
+	})
+
+	t.Run("Trash", func(t *testing.T) {
+		repo := newRepo(t)
+		active := create(t, repo, "active@example.com", models.AdminRoleAdmin)
+		first := create(t, repo, "trash1@example.com", models.AdminRoleAdmin)
+		second := create(t, repo, "trash2@example.com", models.AdminRoleEditor)
+		for _, admin := range []*models.Admin{first, second} {
+			if err := repo.Delete(ctx, admin); err != nil {
+				t.Fatalf("Delete: %v", err)
+			}
+		}
+
+		deleted, err := repo.ListDeleted(ctx)
+		if err != nil {
+			t.Fatalf("ListDeleted: %v", err)
+		}
+		if len(deleted) != 2 || deleted[0].ID != second.ID || deleted[1].ID != first.ID {
+			t.Fatalf("ListDeleted returned %d admins, want [second, first] ordered by deletion", len(deleted))
+		}
+
+		if _, err := repo.FindDeleted(ctx, active.ID); !errors.Is(err, models.ErrAdminNotFound) {
+			t.Fatalf("FindDeleted of active admin error = %v, want ErrAdminNotFound", err)
+		}
+		found, err := repo.FindDeleted(ctx, first.ID)
+		if err != nil {
+			t.Fatalf("FindDeleted: %v", err)
+		}
+
+		if err := repo.Restore(ctx, found); err != nil {
+			t.Fatalf("Restore: %v", err)
+		}
+		if found.DeletedAt.Valid {
+			t.Fatal("Restore did not clear deleted_at on the model")
+		}
+		if _, err := repo.FindByID(ctx, first.ID); err != nil {
+			t.Fatalf("FindByID after restore: %v", err)
+		}
+
+		found, err = repo.FindDeleted(ctx, second.ID)
+		if err != nil {
+			t.Fatalf("FindDeleted: %v", err)
+		}
+		if err := repo.Purge(ctx, found); err != nil {
+			t.Fatalf("Purge: %v", err)
+		}
+		if _, err := repo.FindDeleted(ctx, second.ID); !errors.Is(err, models.ErrAdminNotFound) {
+			t.Fatalf("FindDeleted after purge error = %v, want ErrAdminNotFound", err)
+		}
+		deleted, err = repo.ListDeleted(ctx)
+		if err != nil || len(deleted) != 0 {
+			t.Fatalf("ListDeleted after restore and purge = %d admins, %v; want none", len(deleted), err)
+		}
+	})
+
+	t.Run("RestoreEmailTaken", func(t *testing.T) {
+		repo := newRepo(t)
+		old := create(t, repo, "reused@example.com", models.AdminRoleAdmin)
+		if err := repo.Delete(ctx, old); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+		create(t, repo, "reused@example.com", models.AdminRoleAdmin)
+
+		if err := repo.Restore(ctx, old); !errors.Is(err, models.ErrEmailExists) {
+			t.Fatalf("Restore error = %v, want ErrEmailExists", err)
+		}
+		if _, err := repo.FindDeleted(ctx, old.ID); err != nil {
+			t.Fatalf("FindDeleted after failed restore: %v", err)
+		}
+	})
+}
+
+// ApprovalRepository her ApprovalRepository implementasyonunun sağlaması gereken davranışları test eder.
+// newRepos onay isteklerinin ve ertelenmiş işlemlerin aynı veriyi gördüğü bir çift repository dönmelidir.
+func ApprovalRepository(t *testing.T, newRepos func(t *testing.T) (repository.ApprovalRepository, repository.AdminRepository)) {
+	ctx := context.Background()
+
+	createAdmin := func(t *testing.T, admins repository.AdminRepository, email string) *models.Admin {
+		t.Helper()
+		admin := &models.Admin{Email: email, Name: "Admin", Password: "hash", Role: models.AdminRoleSuperAdmin, Status: models.AdminStatusActive}
+		if err := admins.Create(ctx, admin); err != nil {
+			t.Fatalf("Create(%s): %v", email, err)
+		}
+		return admin
+	}
+	newRequest := func(requester *models.Admin, action models.ApprovalAction, targetID uint) *models.ApprovalRequest {
+		return &models.ApprovalRequest{
+			Action:      action,
+			TargetType:  "admin",
+			TargetID:    targetID,
+			Status:      models.ApprovalStatusPending,
+			RequestedBy: &requester.ID,
+			ExpiresAt:   time.Now().Add(time.Hour),
+		}
+	}
+
+	t.Run("CreatePendingAndFind", func(t *testing.T) {
+		repo, admins := newRepos(t)
+		requester := createAdmin(t, admins, "requester@example.com")
+
+		request := newRequest(requester, models.ApprovalActionAdminDelete, 42)
+		if err := repo.CreatePending(ctx, request); err != nil {
+			t.Fatalf("CreatePending: %v", err)
+		}
+		if request.ID == 0 {
+			t.Fatal("CreatePending did not assign an ID")
+		}
+
+		found, err := repo.FindByID(ctx, request.ID)
+		if err != nil {
+			t.Fatalf("FindByID: %v", err)
+		}
+		if found.Action != request.Action || found.TargetID != 42 || found.Status != models.ApprovalStatusPending || !found.IsRequestedBy(requester.ID) {
+			t.Fatalf("FindByID returned %+v", found)
+		}
+		if _, err := repo.FindByID(ctx, 999999); !errors.Is(err, models.ErrApprovalNotFound) {
+			t.Fatalf("FindByID error = %v, want ErrApprovalNotFound", err)
+		}
+	})
+
+	t.Run("CreatePendingDuplicate", func(t *testing.T) {
+		repo, admins := newRepos(t)
+		requester := createAdmin(t, admins, "requester@example.com")
+
+		first := newRequest(requester, models.ApprovalActionAdminDelete, 42)
+		if err := repo.CreatePending(ctx, first); err != nil {
+			t.Fatalf("CreatePending: %v", err)
+		}
+
+		duplicate := newRequest(requester, models.ApprovalActionAdminDelete, 42)
+		if err := repo.CreatePending(ctx, duplicate); !errors.Is(err, models.ErrApprovalPending) {
+			t.Fatalf("CreatePending duplicate error = %v, want ErrApprovalPending", err)
+		}
+		if duplicate.ID != first.ID {
+			t.Fatalf("CreatePending duplicate returned request %d, want existing %d", duplicate.ID, first.ID)
+		}
+
+		// Farklı hedef veya işlem için istek oluşturulabilir
+		for _, other := range []*models.ApprovalRequest{
+			newRequest(requester, models.ApprovalActionAdminDelete, 43),
+			newRequest(requester, models.ApprovalActionAdminPurge, 42),
+		} {
+			if err := repo.CreatePending(ctx, other); err != nil {
+				t.Fatalf("CreatePending(%s, %d): %v", other.Action, other.TargetID, err)
+			}
+		}
+	})
+
+	t.Run("CreatePendingTargetKey", func(t *testing.T) {
+		repo, admins := newRepos(t)
+		requester := createAdmin(t, admins, "requester@example.com")
+
+		// Birden fazla hedefi kapsayan istekler target_key ile ayrılır; sadece aynı küme tekrar istenemez
+		newSetRequest := func(ids ...uint) *models.ApprovalRequest {
+			request := newRequest(requester, models.ApprovalActionUserBulkDelete, 0)
+			request.TargetType = "user"
+			request.TargetKey = models.ApprovalTargetSetKey(ids)
+			return request
+		}
+		first := newSetRequest(1, 2, 3)
+		if err := repo.CreatePending(ctx, first); err != nil {
+			t.Fatalf("CreatePending first set: %v", err)
+		}
+		if err := repo.CreatePending(ctx, newSetRequest(4, 5, 6)); err != nil {
+			t.Fatalf("CreatePending different set: %v", err)
+		}
+
+		sameSet := newSetRequest(3, 2, 1)
+		if err := repo.CreatePending(ctx, sameSet); !errors.Is(err, models.ErrApprovalPending) {
+			t.Fatalf("CreatePending same set error = %v, want ErrApprovalPending", err)
+		}
+		if sameSet.ID != first.ID {
+			t.Fatalf("CreatePending same set returned request %d, want existing %d", sameSet.ID, first.ID)
+		}
+	})
+
+	t.Run("CreatePendingReplacesExpired", func(t *testing.T) {
+		repo, admins := newRepos(t)
+		requester := createAdmin(t, admins, "requester@example.com")
+
+		stale := newRequest(requester, models.ApprovalActionAdminDelete, 42)
+		stale.ExpiresAt = time.Now().Add(-time.Minute)
+		if err := repo.CreatePending(ctx, stale); err != nil {
+			t.Fatalf("CreatePending stale: %v", err)
+		}
+
+		fresh := newRequest(requester, models.ApprovalActionAdminDelete, 42)
+		if err := repo.CreatePending(ctx, fresh); err != nil {
+			t.Fatalf("CreatePending after expiry: %v", err)
+		}
+		if fresh.ID == stale.ID {
+			t.Fatal("CreatePending returned the expired request")
+		}
+		found, err := repo.FindByID(ctx, stale.ID)
+		if err != nil || found.Status != models.ApprovalStatusExpired {
+			t.Fatalf("stale request = %+v, %v; want expired", found, err)
+		}
+	})
+
+	t.Run("List", func(t *testing.T) {
+		repo, admins := newRepos(t)
+		requester := createAdmin(t, admins, "requester@example.com")
+
+		pending := newRequest(requester, models.ApprovalActionAdminDelete, 42)
+		expired := newRequest(requester, models.ApprovalActionAdminDelete, 43)
+		for _, request := range []*models.ApprovalRequest{pending, expired} {
+			if err := repo.CreatePending(ctx, request); err != nil {
+				t.Fatalf("CreatePending: %v", err)
+			}
+		}
+		if err := repo.MarkExpired(ctx, expired.ID); err != nil {
+			t.Fatalf("MarkExpired: %v", err)
+		}
+
+		all, err := repo.List(ctx, "")
+		if err != nil || len(all) != 2 {
+			t.Fatalf("List all = %d requests, %v; want 2", len(all), err)
+		}
+		filtered, err := repo.List(ctx, models.ApprovalStatusPending)
+		if err != nil || len(filtered) != 1 || filtered[0].ID != pending.ID {
+			t.Fatalf("List pending = %+v, %v; want only request %d", filtered, err, pending.ID)
+		}
+	})
+
+	t.Run("MarkExpiredOnlyPending", func(t *testing.T) {
+		repo, admins := newRepos(t)
+		requester := createAdmin(t, admins, "requester@example.com")
+
+		request := newRequest(requester, models.ApprovalActionAdminDelete, 42)
+		if err := repo.CreatePending(ctx, request); err != nil {
+			t.Fatalf("CreatePending: %v", err)
+		}
+		_, err := repo.Resolve(ctx, request.ID, func(*models.ApprovalRequest, func(repository.ApprovalExecutor) error) (map[string]interface{}, error) {
+			return map[string]interface{}{"status": models.ApprovalStatusRejected}, nil
+		})
+		if err != nil {
+			t.Fatalf("Resolve: %v", err)
+		}
+
+		if err := repo.MarkExpired(ctx, request.ID); err != nil {
+			t.Fatalf("MarkExpired: %v", err)
+		}
+		found, err := repo.FindByID(ctx, request.ID)
+		if err != nil || found.Status != models.ApprovalStatusRejected {
+			t.Fatalf("request after MarkExpired = %+v, %v; want rejected", found, err)
+		}
+	})
+
+	t.Run("ResolveAppliesFieldsAndExecutes", func(t *testing.T) {
+		repo, admins := newRepos(t)
+		requester := createAdmin(t, admins, "requester@example.com")
+		reviewer := createAdmin(t, admins, "reviewer@example.com")
+		target := createAdmin(t, admins, "target@example.com")
+
+		request := newRequest(requester, models.ApprovalActionAdminDelete, target.ID)
+		if err := repo.CreatePending(ctx, request); err != nil {
+			t.Fatalf("CreatePending: %v", err)
+		}
+
+		resolved, err := repo.Resolve(ctx, request.ID, func(request *models.ApprovalRequest, execute func(repository.ApprovalExecutor) error) (map[string]interface{}, error) {
+			err := execute(func(ctx context.Context, admins repository.AdminRepository, users repository.UserRepository) error {
+				admin, err := admins.FindByID(ctx, request.TargetID)
+				if err != nil {
+					return err
+				}
+				return admins.Delete(ctx, admin)
+			})
+			if err != nil {
+				return nil, err
+			}
+			return map[string]interface{}{
+				"status":      models.ApprovalStatusApproved,
+				"reviewed_by": reviewer.ID,
+				"reviewed_at": time.Now(),
+			}, nil
+		})
+		if err != nil {
+			t.Fatalf("Resolve: %v", err)
+		}
+		if resolved.Status != models.ApprovalStatusApproved || resolved.ReviewedBy == nil || *resolved.ReviewedBy != reviewer.ID || resolved.ReviewedAt == nil {
+			t.Fatalf("Resolve returned %+v, want approved by %d", resolved, reviewer.ID)
+		}
+		if _, err := admins.FindByID(ctx, target.ID); !errors.Is(err, models.ErrAdminNotFound) {
+			t.Fatalf("target after executed delete error = %v, want ErrAdminNotFound", err)
+		}
+	})
+
+	t.Run("ResolveFailedActionIsRolledBack", func(t *testing.T) {
+		repo, admins := newRepos(t)
+		requester := createAdmin(t, admins, "requester@example.com")
+		target := createAdmin(t, admins, "target@example.com")
+
+		request := newRequest(requester, models.ApprovalActionAdminDelete, target.ID)
+		if err := repo.CreatePending(ctx, request); err != nil {
+			t.Fatalf("CreatePending: %v", err)
+		}
+
+		errAction := errors.New("action failed")
+		resolved, err := repo.Resolve(ctx, request.ID, func(request *models.ApprovalRequest, execute func(repository.ApprovalExecutor) error) (map[string]interface{}, error) {
+			execErr := execute(func(ctx context.Context, admins repository.AdminRepository, users repository.UserRepository) error {
+				if err := admins.Delete(ctx, target); err != nil {
+					return err
+				}
+				return errAction
+			})
+			if !errors.Is(execErr, errAction) {
+				t.Errorf("execute error = %v, want %v", execErr, errAction)
+			}
+			// İşlem başarısız olsa da isteğin durumu kaydedilir
+			return map[string]interface{}{"status": models.ApprovalStatusFailed, "error": execErr.Error()}, nil
+		})
+		if err != nil {
+			t.Fatalf("Resolve: %v", err)
+		}
+		if resolved.Status != models.ApprovalStatusFailed || resolved.Error == nil {
+			t.Fatalf("Resolve returned %+v, want failed with error", resolved)
+		}
+		if _, err := admins.FindByID(ctx, target.ID); err != nil {
+			t.Fatalf("target after failed action: %v, want the delete rolled back", err)
+		}
+	})
+
+	t.Run("ResolveErrorLeavesRequestUnchanged", func(t *testing.T) {
+		repo, admins := newRepos(t)
+		requester := createAdmin(t, admins, "requester@example.com")
+
+		request := newRequest(requester, models.ApprovalActionAdminDelete, 42)
+		if err := repo.CreatePending(ctx, request); err != nil {
+			t.Fatalf("CreatePending: %v", err)
+		}
+
+		errRejected := errors.New("not allowed")
+		_, err := repo.Resolve(ctx, request.ID, func(*models.ApprovalRequest, func(repository.ApprovalExecutor) error) (map[string]interface{}, error) {
+			return nil, errRejected
+		})
+		if !errors.Is(err, errRejected) {
+			t.Fatalf("Resolve error = %v, want %v", err, errRejected)
+		}
+		found, err := repo.FindByID(ctx, request.ID)
+		if err != nil || found.Status != models.ApprovalStatusPending {
+			t.Fatalf("request after failed Resolve = %+v, %v; want pending", found, err)
+		}
+
+		_, err = repo.Resolve(ctx, 999999, func(*models.ApprovalRequest, func(repository.ApprovalExecutor) error) (map[string]interface{}, error) {
+			t.Error("resolver called for a missing request")
+			return nil, nil
+		})
+		if !errors.Is(err, models.ErrApprovalNotFound) {
+			t.Fatalf("Resolve missing error = %v, want ErrApprovalNotFound", err)
+		}
+	})
+}
+
+// NetworkRuleRepository her NetworkRuleRepository implementasyonunun sağlaması gereken davranışları test eder.
+// Admin bazlı kurallar için newRepos aynı veritabanını kullanan bir AdminRepository de döner.
+func NetworkRuleRepository(t *testing.T, newRepos func(t *testing.T) (repository.NetworkRuleRepository, repository.AdminRepository)) {
+	ctx := context.Background()
+
+	createAdmin := func(t *testing.T, admins repository.AdminRepository) *models.Admin {
+		t.Helper()
+		admin := &models.Admin{Email: "admin@example.com", Name: "Admin", Password: "hash", Role: models.AdminRoleSuperAdmin, Status: models.AdminStatusActive}
+		if err := admins.Create(ctx, admin); err != nil {
+			t.Fatalf("Create admin: %v", err)
+		}
+		return admin
+	}
+	accept := func([]models.AdminNetworkRule) error { return nil }
+
+	t.Run("CreateListAndFind", func(t *testing.T) {
+		repo, admins := newRepos(t)
+		admin := createAdmin(t, admins)
+
+		global := &models.AdminNetworkRule{CIDR: "10.0.0.0/8", Description: "office"}
+		personal := &models.AdminNetworkRule{CIDR: "192.168.1.0/24", AdminID: &admin.ID, CreatedBy: &admin.ID}
+		for _, rule := range []*models.AdminNetworkRule{global, personal} {
+			var seen []models.AdminNetworkRule
+			err := repo.Create(ctx, rule, func(rules []models.AdminNetworkRule) error {
+				seen = rules
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("Create(%s): %v", rule.CIDR, err)
+			}
+			if rule.ID == 0 {
+				t.Fatalf("Create(%s) did not assign an ID", rule.CIDR)
+			}
+			if len(seen) == 0 || seen[len(seen)-1].ID != rule.ID {
+				t.Fatalf("verify for %s saw %+v, want the new rule included", rule.CIDR, seen)
+			}
+		}
+
+		all, err := repo.List(ctx, nil)
+		if err != nil {
+			t.Fatalf("List: %v", err)
+		}
+		if len(all) != 2 || all[0].ID != global.ID || all[1].ID != personal.ID {
+			t.Fatalf("List = %+v, want both rules in ID order", all)
+		}
+
+		own, err := repo.List(ctx, &admin.ID)
+		if err != nil {
+			t.Fatalf("List(admin): %v", err)
+		}
+		if len(own) != 1 || own[0].ID != personal.ID {
+			t.Fatalf("List(admin) = %+v, want only the personal rule", own)
+		}
+
+		found, err := repo.FindByID(ctx, global.ID)
+		if err != nil {
+			t.Fatalf("FindByID: %v", err)
+		}
+		if found.CIDR != "10.0.0.0/8" || found.AdminID != nil || found.Description != "office" {
+			t.Fatalf("FindByID returned %+v", found)
+		}
+	})
+
+	t.Run("NotFound", func(t *testing.T) {
+		repo, _ := newRepos(t)
+		if _, err := repo.FindByID(ctx, 999999); !errors.Is(err, models.ErrNetworkRuleNotFound) {
+			t.Fatalf("FindByID error = %v, want ErrNetworkRuleNotFound", err)
+		}
+	})
+
+	t.Run("VerifyErrorRollsBackCreate", func(t *testing.T) {
+		repo, _ := newRepos(t)
+
+		errRejected := errors.New("rejected")
+		err := repo.Create(ctx, &models.AdminNetworkRule{CIDR: "10.0.0.0/8"}, func([]models.AdminNetworkRule) error {
+			return errRejected
+		})
+		if !errors.Is(err, errRejected) {
+			t.Fatalf("Create error = %v, want %v", err, errRejected)
+		}
+		if rules, err := repo.List(ctx, nil); err != nil || len(rules) != 0 {
+			t.Fatalf("List after rejected Create = %+v, %v; want no rules", rules, err)
+		}
+	})
+
+	t.Run("DeleteAndVerifyErrorRollsBackDelete", func(t *testing.T) {
+		repo, _ := newRepos(t)
+		rule := &models.AdminNetworkRule{CIDR: "10.0.0.0/8"}
+		if err := repo.Create(ctx, rule, accept); err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+
+		errRejected := errors.New("rejected")
+		err := repo.Delete(ctx, rule, func(rules []models.AdminNetworkRule) error {
+			if len(rules) != 0 {
+				t.Errorf("verify saw %+v, want the rule removed", rules)
+			}
+			return errRejected
+		})
+		if !errors.Is(err, errRejected) {
+			t.Fatalf("Delete error = %v, want %v", err, errRejected)
+		}
+		if _, err := repo.FindByID(ctx, rule.ID); err != nil {
+			t.Fatalf("FindByID after rejected Delete: %v", err)
+		}
+
+		if err := repo.Delete(ctx, rule, accept); err != nil {
+			t.Fatalf("Delete: %v", err)
+		}
+		if _, err := repo.FindByID(ctx, rule.ID); !errors.Is(err, models.ErrNetworkRuleNotFound) {
+			t.Fatalf("FindByID after Delete error = %v, want ErrNetworkRuleNotFound", err)
+		}
+	})
+}
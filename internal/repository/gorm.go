@@ -0,0 +1,635 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"prototurk/internal/database"
+	"prototurk/internal/models"
+	"prototurk/pkg/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type gormUserRepository struct {
+	db *gorm.DB
+}
+
+// NewGormUserRepository Postgres üzerinde çalışan UserRepository oluşturur
+func NewGormUserRepository(db *gorm.DB) UserRepository {
+	return &gormUserRepository{db: db}
+}
+
+func (r *gormUserRepository) Create(ctx context.Context, user *models.User) error {
+	db := r.db.WithContext(ctx)
+	if err := db.Create(user).Error; err != nil {
+		// Çakışmanın hangi alanda olduğu hatayı üreten constraint yerine kayıtlardan bulunur
+		if db.Where("username = ?", user.Username).First(&models.User{}).Error == nil {
+			return models.ErrUsernameExists
+		}
+		if db.Where("email = ?", user.Email).First(&models.User{}).Error == nil {
+			return models.ErrEmailExists
+		}
+		return err
+	}
+	return nil
+}
+
+func (r *gormUserRepository) FindByID(ctx context.Context, id uint) (*models.User, error) {
+	var user models.User
+	if err := r.db.WithContext(ctx).First(&user, id).Error; err != nil {
+		return nil, notFound(err, models.ErrUserNotFound)
+	}
+	return &user, nil
+}
+
+func (r *gormUserRepository) FindByIdentifier(ctx context.Context, identifier string) (*models.User, error) {
+	var user models.User
+	if err := r.db.WithContext(ctx).Where("username = ? OR email = ?", identifier, identifier).First(&user).Error; err != nil {
+		return nil, notFound(err, models.ErrUserNotFound)
+	}
+	return &user, nil
+}
+
+func (r *gormUserRepository) UsernameExists(ctx context.Context, username string) (bool, error) {
+	return exists(r.db.WithContext(ctx).Model(&models.User{}).Where("username = ?", username))
+}
+
+func (r *gormUserRepository) EmailExists(ctx context.Context, email string) (bool, error) {
+	return exists(r.db.WithContext(ctx).Model(&models.User{}).Where("email = ?", email))
+}
+
+func (r *gormUserRepository) Update(ctx context.Context, user *models.User, fields map[string]interface{}) error {
+	return r.db.WithContext(ctx).Model(user).Updates(fields).Error
+}
+
+func (r *gormUserRepository) DeleteByIDs(ctx context.Context, ids []uint) (int64, error) {
+	if len(ids) == 0 {
+		return 0, nil
+	}
+	result := r.db.WithContext(ctx).Where("id IN ?", ids).Delete(&models.User{})
+	return result.RowsAffected, result.Error
+}
+
+type gormUserBulkRepository struct {
+	db *gorm.DB
+}
+
+// NewGormUserBulkRepository Postgres üzerinde çalışan UserBulkRepository oluşturur
+func NewGormUserBulkRepository(db *gorm.DB) UserBulkRepository {
+	return &gormUserBulkRepository{db: db}
+}
+
+func (r *gormUserBulkRepository) Count(ctx context.Context, selection models.BulkUserRequest) (int64, error) {
+	var count int64
+	err := r.selected(ctx, selection).Count(&count).Error
+	return count, err
+}
+
+func (r *gormUserBulkRepository) IDs(ctx context.Context, selection models.BulkUserRequest) ([]uint, error) {
+	var ids []uint
+	if err := r.selected(ctx, selection).Order("id ASC").Pluck("id", &ids).Error; err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (r *gormUserBulkRepository) UpdateStatus(ctx context.Context, selection models.BulkUserRequest, status models.UserStatus) (int64, error) {
+	result := r.selected(ctx, selection).Update("status", status)
+	return result.RowsAffected, result.Error
+}
+
+func (r *gormUserBulkRepository) Delete(ctx context.Context, selection models.BulkUserRequest) (int64, error) {
+	result := r.selected(ctx, selection).Delete(&models.User{})
+	return result.RowsAffected, result.Error
+}
+
+func (r *gormUserBulkRepository) ExistingIdentities(ctx context.Context, usernames []string, emails []string) (map[string]bool, map[string]bool, error) {
+	var existing []models.User
+	if err := r.db.WithContext(ctx).Unscoped().Select("username", "email").
+		Where("username IN ? OR email IN ?", usernames, emails).Find(&existing).Error; err != nil {
+		return nil, nil, err
+	}
+
+	existingUsernames := make(map[string]bool)
+	existingEmails := make(map[string]bool)
+	for _, user := range existing {
+		existingUsernames[user.Username] = true
+		existingEmails[user.Email] = true
+	}
+	return existingUsernames, existingEmails, nil
+}
+
+func (r *gormUserBulkRepository) CreateBatch(ctx context.Context, users []models.User) error {
+	return r.db.WithContext(ctx).Create(&users).Error
+}
+
+// selected seçime uyan kullanıcıların sorgusunu oluşturur
+func (r *gormUserBulkRepository) selected(ctx context.Context, selection models.BulkUserRequest) *gorm.DB {
+	query := r.db.WithContext(ctx).Model(&models.User{})
+	if len(selection.IDs) > 0 {
+		query = query.Where("id IN ?", selection.IDs)
+	}
+	if selection.Filter != nil && !selection.Filter.IsEmpty() {
+		query = selection.Filter.Apply(query)
+	}
+	return query
+}
+
+type gormAdminRepository struct {
+	db *gorm.DB
+}
+
+// NewGormAdminRepository Postgres üzerinde çalışan AdminRepository oluşturur.
+// Transaction içinde kullanmak için tx verilebilir.
+func NewGormAdminRepository(db *gorm.DB) AdminRepository {
+	return &gormAdminRepository{db: db}
+}
+
+func (r *gormAdminRepository) Create(ctx context.Context, admin *models.Admin) error {
+	return r.db.WithContext(ctx).Create(admin).Error
+}
+
+func (r *gormAdminRepository) FindByID(ctx context.Context, id uint) (*models.Admin, error) {
+	var admin models.Admin
+	if err := r.db.WithContext(ctx).First(&admin, id).Error; err != nil {
+		return nil, notFound(err, models.ErrAdminNotFound)
+	}
+	return &admin, nil
+}
+
+func (r *gormAdminRepository) FindByEmail(ctx context.Context, email string) (*models.Admin, error) {
+	var admin models.Admin
+	if err := r.db.WithContext(ctx).Where("email = ?", email).First(&admin).Error; err != nil {
+		return nil, notFound(err, models.ErrAdminNotFound)
+	}
+	return &admin, nil
+}
+
+func (r *gormAdminRepository) List(ctx context.Context) ([]models.Admin, error) {
+	var admins []models.Admin
+	if err := database.ReadOnly(r.db.WithContext(ctx)).Order("id ASC").Find(&admins).Error; err != nil {
+		return nil, err
+	}
+	return admins, nil
+}
+
+func (r *gormAdminRepository) EmailExists(ctx context.Context, email string, excludeID uint) (bool, error) {
+	query := r.db.WithContext(ctx).Model(&models.Admin{}).Where("email = ?", email)
+	if excludeID != 0 {
+		query = query.Where("id != ?", excludeID)
+	}
+	return exists(query)
+}
+
+func (r *gormAdminRepository) FirstSuperAdmin(ctx context.Context) (*models.Admin, error) {
+	var admin models.Admin
+	if err := r.db.WithContext(ctx).Where("role = ?", models.AdminRoleSuperAdmin).Order("created_at ASC, id ASC").First(&admin).Error; err != nil {
+		return nil, notFound(err, models.ErrAdminNotFound)
+	}
+	return &admin, nil
+}
+
+func (r *gormAdminRepository) Update(ctx context.Context, admin *models.Admin, fields map[string]interface{}) error {
+	return r.db.WithContext(ctx).Model(admin).Updates(fields).Error
+}
+
+func (r *gormAdminRepository) UpdatePassword(ctx context.Context, admin *models.Admin, fields map[string]interface{}, previousHash string, historySize int) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(admin).Updates(fields).Error; err != nil {
+			return err
+		}
+
+		if historySize <= 0 || previousHash == "" {
+			return nil
+		}
+		if err := tx.Create(&models.AdminPasswordHistory{AdminID: admin.ID, PasswordHash: previousHash}).Error; err != nil {
+			return err
+		}
+
+		// Politikadaki sayıdan eski kayıtları temizle
+		return tx.Where("admin_id = ? AND id NOT IN (?)", admin.ID,
+			tx.Model(&models.AdminPasswordHistory{}).Select("id").Where("admin_id = ?", admin.ID).
+				Order("created_at DESC, id DESC").Limit(historySize),
+		).Delete(&models.AdminPasswordHistory{}).Error
+	})
+}
+
+func (r *gormAdminRepository) PasswordHistory(ctx context.Context, adminID uint, limit int) ([]string, error) {
+	var hashes []string
+	if limit <= 0 {
+		return hashes, nil
+	}
+	err := r.db.WithContext(ctx).Model(&models.AdminPasswordHistory{}).Where("admin_id = ?", adminID).
+		Order("created_at DESC, id DESC").Limit(limit).Pluck("password_hash", &hashes).Error
+	return hashes, err
+}
+
+func (r *gormAdminRepository) Delete(ctx context.Context, admin *models.Admin) error {
+	return r.db.WithContext(ctx).Delete(admin).Error
+}
+
+func (r *gormAdminRepository) ListDeleted(ctx context.Context) ([]models.Admin, error) {
+	var admins []models.Admin
+	if err := database.ReadOnly(r.db.WithContext(ctx)).Unscoped().Where("deleted_at IS NOT NULL").
+		Order("deleted_at DESC, id DESC").Find(&admins).Error; err != nil {
+		return nil, err
+	}
+	return admins, nil
+}
+
+func (r *gormAdminRepository) FindDeleted(ctx context.Context, id uint) (*models.Admin, error) {
+	var admin models.Admin
+	if err := r.db.WithContext(ctx).Unscoped().Where("deleted_at IS NOT NULL").First(&admin, id).Error; err != nil {
+		return nil, notFound(err, models.ErrAdminNotFound)
+	}
+	return &admin, nil
+}
+
+func (r *gormAdminRepository) Restore(ctx context.Context, admin *models.Admin) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		taken, err := exists(tx.Model(&models.Admin{}).Where("email = ? AND id != ?", admin.Email, admin.ID))
+		if err != nil {
+			return err
+		}
+		if taken {
+			return models.ErrEmailExists
+		}
+		return tx.Unscoped().Model(admin).Update("deleted_at", nil).Error
+	})
+	if err != nil {
+		return err
+	}
+	admin.DeletedAt = gorm.DeletedAt{}
+	return nil
+}
+
+func (r *gormAdminRepository) Purge(ctx context.Context, admin *models.Admin) error {
+	return r.db.WithContext(ctx).Unscoped().Delete(admin).Error
+}
+
+func (r *gormAdminRepository) CreateWithSetupToken(ctx context.Context, admin *models.Admin, tokenHash string) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Token satırını kilitle ki eşzamanlı istekler aynı token'ı iki kez kullanamasın
+		var setupToken models.AdminSetupToken
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("token_hash = ? AND used_at IS NULL AND expires_at > ?", tokenHash, utils.Now()).
+			First(&setupToken).Error; err != nil {
+			return notFound(err, models.ErrSetupUnavailable)
+		}
+
+		var count int64
+		if err := tx.Unscoped().Model(&models.Admin{}).Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return models.ErrSetupUnavailable
+		}
+
+		if err := tx.Create(admin).Error; err != nil {
+			return err
+		}
+		return tx.Model(&setupToken).Update("used_at", utils.Now()).Error
+	})
+}
+
+type gormInvitationRepository struct {
+	db *gorm.DB
+}
+
+// NewGormInvitationRepository Postgres üzerinde çalışan InvitationRepository oluşturur
+func NewGormInvitationRepository(db *gorm.DB) InvitationRepository {
+	return &gormInvitationRepository{db: db}
+}
+
+func (r *gormInvitationRepository) Create(ctx context.Context, pendingAdmin *models.Admin, invitation *models.AdminInvitation) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(pendingAdmin).Error; err != nil {
+			return err
+		}
+		invitation.AdminID = &pendingAdmin.ID
+		return tx.Create(invitation).Error
+	})
+}
+
+func (r *gormInvitationRepository) ListOpen(ctx context.Context) ([]models.AdminInvitation, error) {
+	var invitations []models.AdminInvitation
+	if err := database.ReadOnly(r.db.WithContext(ctx)).Where("accepted_at IS NULL AND revoked_at IS NULL").
+		Order("created_at DESC").Find(&invitations).Error; err != nil {
+		return nil, err
+	}
+	return invitations, nil
+}
+
+func (r *gormInvitationRepository) FindOpen(ctx context.Context, id uint) (*models.AdminInvitation, error) {
+	var invitation models.AdminInvitation
+	if err := r.db.WithContext(ctx).Where("accepted_at IS NULL AND revoked_at IS NULL").First(&invitation, id).Error; err != nil {
+		return nil, notFound(err, models.ErrInvitationNotFound)
+	}
+	return &invitation, nil
+}
+
+func (r *gormInvitationRepository) Update(ctx context.Context, invitation *models.AdminInvitation, fields map[string]interface{}) error {
+	return r.db.WithContext(ctx).Model(invitation).Updates(fields).Error
+}
+
+func (r *gormInvitationRepository) Revoke(ctx context.Context, invitation *models.AdminInvitation) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(invitation).Update("revoked_at", utils.Now()).Error; err != nil {
+			return err
+		}
+		if invitation.AdminID == nil {
+			return nil
+		}
+		// Bekleyen admin hiç aktif olmadığı için çöp kutusuna değil, kalıcı olarak silinir
+		return tx.Unscoped().Where("status = ?", models.AdminStatusPending).Delete(&models.Admin{}, *invitation.AdminID).Error
+	})
+}
+
+func (r *gormInvitationRepository) Accept(ctx context.Context, tokenHash string, fields map[string]interface{}) (*models.Admin, error) {
+	var admin models.Admin
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Davet satırını kilitle ki aynı token ile eşzamanlı istekler daveti iki kez kabul edemesin
+		var invitation models.AdminInvitation
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("token_hash = ?", tokenHash).First(&invitation).Error; err != nil {
+			return notFound(err, models.ErrInvitationInvalid)
+		}
+		if !invitation.IsPending() || invitation.AdminID == nil {
+			return models.ErrInvitationInvalid
+		}
+
+		if err := tx.Where("status = ?", models.AdminStatusPending).First(&admin, *invitation.AdminID).Error; err != nil {
+			return notFound(err, models.ErrInvitationInvalid)
+		}
+
+		if err := tx.Model(&admin).Updates(fields).Error; err != nil {
+			return err
+		}
+		return tx.Model(&invitation).Update("accepted_at", utils.Now()).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &admin, nil
+}
+
+type gormApprovalRepository struct {
+	db *gorm.DB
+}
+
+// NewGormApprovalRepository Postgres üzerinde çalışan ApprovalRepository oluşturur
+func NewGormApprovalRepository(db *gorm.DB) ApprovalRepository {
+	return &gormApprovalRepository{db: db}
+}
+
+func (r *gormApprovalRepository) List(ctx context.Context, status models.ApprovalStatus) ([]models.ApprovalRequest, error) {
+	query := database.ReadOnly(r.db.WithContext(ctx)).Order("created_at DESC")
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+
+	var requests []models.ApprovalRequest
+	if err := query.Find(&requests).Error; err != nil {
+		return nil, err
+	}
+	return requests, nil
+}
+
+func (r *gormApprovalRepository) FindByID(ctx context.Context, id uint) (*models.ApprovalRequest, error) {
+	var request models.ApprovalRequest
+	if err := r.db.WithContext(ctx).First(&request, id).Error; err != nil {
+		return nil, notFound(err, models.ErrApprovalNotFound)
+	}
+	return &request, nil
+}
+
+func (r *gormApprovalRepository) CreatePending(ctx context.Context, request *models.ApprovalRequest) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var existing models.ApprovalRequest
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("action = ? AND target_type = ? AND target_id = ? AND target_key = ? AND status = ?",
+				request.Action, request.TargetType, request.TargetID, request.TargetKey, models.ApprovalStatusPending).
+			First(&existing).Error
+		if err == nil {
+			if existing.IsPending() {
+				*request = existing
+				return models.ErrApprovalPending
+			}
+			// Süresi dolmuş ama henüz işaretlenmemiş istek yenisine yer açar
+			if err := tx.Model(&existing).Update("status", models.ApprovalStatusExpired).Error; err != nil {
+				return err
+			}
+		} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		return tx.Create(request).Error
+	})
+}
+
+func (r *gormApprovalRepository) Resolve(ctx context.Context, id uint, resolve ApprovalResolver) (*models.ApprovalRequest, error) {
+	var request models.ApprovalRequest
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&request, id).Error; err != nil {
+			return notFound(err, models.ErrApprovalNotFound)
+		}
+
+		execute := func(action ApprovalExecutor) error {
+			return tx.Transaction(func(actionTx *gorm.DB) error {
+				return action(ctx, NewGormAdminRepository(actionTx), NewGormUserRepository(actionTx))
+			})
+		}
+		fields, err := resolve(&request, execute)
+		if err != nil {
+			return err
+		}
+		if len(fields) == 0 {
+			return nil
+		}
+
+		if err := tx.Model(&request).Updates(fields).Error; err != nil {
+			return err
+		}
+		// Yanıtta güncel durumun dönmesi için kaydı tekrar oku
+		return tx.First(&request, request.ID).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+func (r *gormApprovalRepository) MarkExpired(ctx context.Context, id uint) error {
+	return r.db.WithContext(ctx).Model(&models.ApprovalRequest{}).Where("id = ? AND status = ?", id, models.ApprovalStatusPending).
+		Update("status", models.ApprovalStatusExpired).Error
+}
+
+type gormNetworkRuleRepository struct {
+	db *gorm.DB
+}
+
+// NewGormNetworkRuleRepository Postgres üzerinde çalışan NetworkRuleRepository oluşturur
+func NewGormNetworkRuleRepository(db *gorm.DB) NetworkRuleRepository {
+	return &gormNetworkRuleRepository{db: db}
+}
+
+func (r *gormNetworkRuleRepository) List(ctx context.Context, adminID *uint) ([]models.AdminNetworkRule, error) {
+	query := r.db.WithContext(ctx).Order("id ASC")
+	if adminID != nil {
+		query = query.Where("admin_id = ?", *adminID)
+	}
+
+	var rules []models.AdminNetworkRule
+	if err := query.Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+func (r *gormNetworkRuleRepository) FindByID(ctx context.Context, id uint) (*models.AdminNetworkRule, error) {
+	var rule models.AdminNetworkRule
+	if err := r.db.WithContext(ctx).First(&rule, id).Error; err != nil {
+		return nil, notFound(err, models.ErrNetworkRuleNotFound)
+	}
+	return &rule, nil
+}
+
+func (r *gormNetworkRuleRepository) Create(ctx context.Context, rule *models.AdminNetworkRule, verify NetworkRuleVerifier) error {
+	return r.change(ctx, verify, func(tx *gorm.DB) error {
+		return tx.Create(rule).Error
+	})
+}
+
+func (r *gormNetworkRuleRepository) Delete(ctx context.Context, rule *models.AdminNetworkRule, verify NetworkRuleVerifier) error {
+	return r.change(ctx, verify, func(tx *gorm.DB) error {
+		return tx.Delete(rule).Error
+	})
+}
+
+// change değişikliği transaction içinde uygular ve verify hata dönerse geri alır
+func (r *gormNetworkRuleRepository) change(ctx context.Context, verify NetworkRuleVerifier, apply func(tx *gorm.DB) error) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := apply(tx); err != nil {
+			return err
+		}
+
+		var rules []models.AdminNetworkRule
+		if err := tx.Order("id ASC").Find(&rules).Error; err != nil {
+			return err
+		}
+		return verify(rules)
+	})
+}
+
+type gormStatsRepository struct {
+	db *gorm.DB
+}
+
+// NewGormStatsRepository Postgres üzerinde çalışan StatsRepository oluşturur
+func NewGormStatsRepository(db *gorm.DB) StatsRepository {
+	return &gormStatsRepository{db: db}
+}
+
+func (r *gormStatsRepository) CountRegistrationsByBucket(ctx context.Context, interval string, from time.Time) (map[int64]int64, error) {
+	return r.countByBucket(ctx, "created_at", interval, from)
+}
+
+func (r *gormStatsRepository) CountLoginsByBucket(ctx context.Context, interval string, from time.Time) (map[int64]int64, error) {
+	return r.countByBucket(ctx, "last_login_date", interval, from)
+}
+
+// countByBucket verilen kolonu UTC'ye göre gün/hafta bazında gruplayıp sayar
+func (r *gormStatsRepository) countByBucket(ctx context.Context, column string, interval string, from time.Time) (map[int64]int64, error) {
+	if interval != models.StatsIntervalDay && interval != models.StatsIntervalWeek {
+		return nil, fmt.Errorf("invalid stats interval %q", interval)
+	}
+
+	var rows []struct {
+		Bucket time.Time
+		Count  int64
+	}
+
+	// Aralık filtresi index'in (013_add_user_activity_indexes) kullanılabilmesi için yuvarlanmamış kolona uygulanır,
+	// date_trunc sadece eşleşen satırlarda çalışır
+	bucketExpr := fmt.Sprintf("date_trunc('%s', %s AT TIME ZONE 'UTC')", interval, column)
+	err := database.ReadOnly(r.db.WithContext(ctx)).Model(&models.User{}).
+		Select(bucketExpr+" AS bucket, COUNT(*) AS count").
+		Where(column+" >= ?", from).
+		Group("bucket").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[int64]int64, len(rows))
+	for _, row := range rows {
+		counts[utils.ParseTime(row.Bucket).Unix()] = row.Count
+	}
+	return counts, nil
+}
+
+func (r *gormStatsRepository) CountUsersByStatus(ctx context.Context) (map[models.UserStatus]int64, error) {
+	var rows []struct {
+		Status models.UserStatus
+		Count  int64
+	}
+	if err := database.ReadOnly(r.db.WithContext(ctx)).Model(&models.User{}).Select("status, COUNT(*) AS count").Group("status").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	counts := make(map[models.UserStatus]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Status] = row.Count
+	}
+	return counts, nil
+}
+
+func (r *gormStatsRepository) CountActiveAdminsByRole(ctx context.Context) (map[models.AdminRole]int64, error) {
+	var rows []struct {
+		Role  models.AdminRole
+		Count int64
+	}
+	if err := database.ReadOnly(r.db.WithContext(ctx)).Model(&models.Admin{}).Select("role, COUNT(*) AS count").
+		Where("status = ?", models.AdminStatusActive).Group("role").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	counts := make(map[models.AdminRole]int64, len(rows))
+	for _, row := range rows {
+		counts[row.Role] = row.Count
+	}
+	return counts, nil
+}
+
+func (r *gormStatsRepository) CountRegistrationsAround(ctx context.Context, previous, current time.Time) (int64, int64, error) {
+	var row struct {
+		Current  int64
+		Previous int64
+	}
+	err := database.ReadOnly(r.db.WithContext(ctx)).Model(&models.User{}).
+		Select("COUNT(*) FILTER (WHERE created_at >= ?) AS current, COUNT(*) FILTER (WHERE created_at < ?) AS previous", current, current).
+		Where("created_at >= ?", previous).
+		Scan(&row).Error
+	if err != nil {
+		return 0, 0, err
+	}
+	return row.Previous, row.Current, nil
+}
+
+// notFound GORM'un kayıt bulunamadı hatasını repository'nin hatasına çevirir
+func notFound(err error, target error) error {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return target
+	}
+	return err
+}
+
+func exists(query *gorm.DB) (bool, error) {
+	var count int64
+	if err := query.Limit(1).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
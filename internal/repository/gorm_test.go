@@ -0,0 +1,85 @@
+package repository_test
+
+import (
+	"os"
+	"testing"
+
+	"gorm.io/gorm"
+
+	"prototurk/internal/database"
+	"prototurk/internal/repository"
+	"prototurk/internal/repository/contract"
+	"prototurk/migrations"
+)
+
+// TEST_DATABASE_URL tanımlı değilse GORM testleri atlanır. Tablolar her senaryoda temizlendiği için
+// bu değişken geliştirme veya production veritabanını göstermemelidir.
+const testDatabaseURLEnv = "TEST_DATABASE_URL"
+
+func TestGormUserRepository(t *testing.T) {
+	db := openTestDB(t)
+	contract.UserRepository(t, func(t *testing.T) repository.UserRepository {
+		truncateTables(t, db)
+		return repository.NewGormUserRepository(db)
+	})
+}
+
+func TestGormAdminRepository(t *testing.T) {
+	db := openTestDB(t)
+	contract.AdminRepository(t, func(t *testing.T) repository.AdminRepository {
+		truncateTables(t, db)
+		return repository.NewGormAdminRepository(db)
+	})
+}
+
+func TestGormApprovalRepository(t *testing.T) {
+	db := openTestDB(t)
+	contract.ApprovalRepository(t, func(t *testing.T) (repository.ApprovalRepository, repository.AdminRepository) {
+		truncateTables(t, db)
+		return repository.NewGormApprovalRepository(db), repository.NewGormAdminRepository(db)
+	})
+}
+
+func TestGormNetworkRuleRepository(t *testing.T) {
+	db := openTestDB(t)
+	contract.NetworkRuleRepository(t, func(t *testing.T) (repository.NetworkRuleRepository, repository.AdminRepository) {
+		truncateTables(t, db)
+		return repository.NewGormNetworkRuleRepository(db), repository.NewGormAdminRepository(db)
+	})
+}
+
+// openTestDB test veritabanına bağlanır ve bekleyen migration'ları uygular
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	url := os.Getenv(testDatabaseURLEnv)
+	if url == "" {
+		t.Skipf("%s is not set", testDatabaseURLEnv)
+	}
+
+	config := database.DefaultConfig()
+	config.URL = url
+	db, err := database.NewConnection(config)
+	if err != nil {
+		t.Fatalf("connect test database: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := database.Close(db); err != nil {
+			t.Errorf("close test database: %v", err)
+		}
+	})
+
+	if err := database.RunMigrations(db, migrations.FS, database.DefaultMigratorOptions()); err != nil {
+		t.Fatalf("run migrations: %v", err)
+	}
+	return db
+}
+
+// truncateTables kullanıcı, admin, onay isteği ve ağ kuralı tablolarını, bunlara bağlı kayıtlarla birlikte boşaltır
+func truncateTables(t *testing.T, db *gorm.DB) {
+	t.Helper()
+
+	if err := db.Exec("TRUNCATE TABLE users, admins, approval_requests, admin_network_rules RESTART IDENTITY CASCADE").Error; err != nil {
+		t.Fatalf("truncate tables: %v", err)
+	}
+}
@@ -0,0 +1,696 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"prototurk/internal/models"
+	"prototurk/pkg/utils"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// MemoryUserRepository unit testler için bellekte çalışan UserRepository'dir.
+// Silinen kullanıcılar saklanmaya devam eder ancak sorgularda görünmez.
+type MemoryUserRepository struct {
+	mu     sync.Mutex
+	nextID uint
+	users  map[uint]models.User
+}
+
+func NewMemoryUserRepository() *MemoryUserRepository {
+	return &MemoryUserRepository{users: make(map[uint]models.User)}
+}
+
+func (r *MemoryUserRepository) Create(ctx context.Context, user *models.User) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.users {
+		if existing.Username == user.Username {
+			return models.ErrUsernameExists
+		}
+		if existing.Email == user.Email {
+			return models.ErrEmailExists
+		}
+	}
+
+	r.nextID++
+	now := utils.Now()
+	user.ID = r.nextID
+	user.CreatedAt, user.UpdatedAt = now, now
+	if user.Status == "" {
+		user.Status = models.UserStatusActive
+	}
+	if user.LastLoginDate.IsZero() {
+		user.LastLoginDate = now
+	}
+	r.users[user.ID] = *user
+	return nil
+}
+
+func (r *MemoryUserRepository) FindByID(ctx context.Context, id uint) (*models.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	user, ok := r.users[id]
+	if !ok || user.DeletedAt.Valid {
+		return nil, models.ErrUserNotFound
+	}
+	return &user, nil
+}
+
+func (r *MemoryUserRepository) FindByIdentifier(ctx context.Context, identifier string) (*models.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, id := range sortedIDs(r.users) {
+		if user := r.users[id]; !user.DeletedAt.Valid && (user.Username == identifier || user.Email == identifier) {
+			return &user, nil
+		}
+	}
+	return nil, models.ErrUserNotFound
+}
+
+func (r *MemoryUserRepository) UsernameExists(ctx context.Context, username string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, user := range r.users {
+		if !user.DeletedAt.Valid && user.Username == username {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *MemoryUserRepository) EmailExists(ctx context.Context, email string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, user := range r.users {
+		if !user.DeletedAt.Valid && user.Email == email {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *MemoryUserRepository) Update(ctx context.Context, user *models.User, fields map[string]interface{}) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.users[user.ID]
+	if !ok || stored.DeletedAt.Valid {
+		return models.ErrUserNotFound
+	}
+	if err := applyFields(ctx, &stored, fields); err != nil {
+		return err
+	}
+	stored.UpdatedAt = utils.Now()
+	r.users[user.ID] = stored
+	*user = stored
+	return nil
+}
+
+func (r *MemoryUserRepository) DeleteByIDs(ctx context.Context, ids []uint) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var deleted int64
+	now := utils.Now()
+	for _, id := range ids {
+		user, ok := r.users[id]
+		if !ok || user.DeletedAt.Valid {
+			continue
+		}
+		user.DeletedAt = gorm.DeletedAt{Time: now, Valid: true}
+		r.users[id] = user
+		deleted++
+	}
+	return deleted, nil
+}
+
+// MemoryAdminRepository unit testler için bellekte çalışan AdminRepository'dir.
+// Silinen adminler saklanmaya devam eder ancak sorgularda görünmez.
+type MemoryAdminRepository struct {
+	mu          sync.Mutex
+	nextID      uint
+	admins      map[uint]models.Admin
+	history     map[uint][]string
+	setupTokens map[string]memorySetupToken
+}
+
+type memorySetupToken struct {
+	expiresAt time.Time
+	used      bool
+}
+
+func NewMemoryAdminRepository() *MemoryAdminRepository {
+	return &MemoryAdminRepository{
+		admins:      make(map[uint]models.Admin),
+		history:     make(map[uint][]string),
+		setupTokens: make(map[string]memorySetupToken),
+	}
+}
+
+// AddSetupToken CreateWithSetupToken ile kullanılabilecek bir kurulum token'ı ekler
+func (r *MemoryAdminRepository) AddSetupToken(tokenHash string, expiresAt time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.setupTokens[tokenHash] = memorySetupToken{expiresAt: expiresAt}
+}
+
+func (r *MemoryAdminRepository) Create(ctx context.Context, admin *models.Admin) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, existing := range r.admins {
+		if !existing.DeletedAt.Valid && existing.Email == admin.Email {
+			return models.ErrEmailExists
+		}
+	}
+
+	r.nextID++
+	now := utils.Now()
+	admin.ID = r.nextID
+	admin.CreatedAt, admin.UpdatedAt = now, now
+	if admin.Status == "" {
+		admin.Status = models.AdminStatusActive
+	}
+	r.admins[admin.ID] = *admin
+	return nil
+}
+
+func (r *MemoryAdminRepository) FindByID(ctx context.Context, id uint) (*models.Admin, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	admin, ok := r.admins[id]
+	if !ok || admin.DeletedAt.Valid {
+		return nil, models.ErrAdminNotFound
+	}
+	return &admin, nil
+}
+
+func (r *MemoryAdminRepository) FindByEmail(ctx context.Context, email string) (*models.Admin, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, id := range sortedIDs(r.admins) {
+		if admin := r.admins[id]; !admin.DeletedAt.Valid && admin.Email == email {
+			return &admin, nil
+		}
+	}
+	return nil, models.ErrAdminNotFound
+}
+
+func (r *MemoryAdminRepository) List(ctx context.Context) ([]models.Admin, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	admins := make([]models.Admin, 0, len(r.admins))
+	for _, id := range sortedIDs(r.admins) {
+		if admin := r.admins[id]; !admin.DeletedAt.Valid {
+			admins = append(admins, admin)
+		}
+	}
+	return admins, nil
+}
+
+func (r *MemoryAdminRepository) EmailExists(ctx context.Context, email string, excludeID uint) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for id, admin := range r.admins {
+		if id != excludeID && !admin.DeletedAt.Valid && admin.Email == email {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *MemoryAdminRepository) FirstSuperAdmin(ctx context.Context) (*models.Admin, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var first *models.Admin
+	for _, id := range sortedIDs(r.admins) {
+		admin := r.admins[id]
+		if admin.DeletedAt.Valid || admin.Role != models.AdminRoleSuperAdmin {
+			continue
+		}
+		if first == nil || admin.CreatedAt.Before(first.CreatedAt) {
+			first = &admin
+		}
+	}
+	if first == nil {
+		return nil, models.ErrAdminNotFound
+	}
+	return first, nil
+}
+
+func (r *MemoryAdminRepository) Update(ctx context.Context, admin *models.Admin, fields map[string]interface{}) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.update(ctx, admin, fields)
+}
+
+func (r *MemoryAdminRepository) UpdatePassword(ctx context.Context, admin *models.Admin, fields map[string]interface{}, previousHash string, historySize int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if err := r.update(ctx, admin, fields); err != nil {
+		return err
+	}
+	if historySize <= 0 || previousHash == "" {
+		return nil
+	}
+
+	history := append([]string{previousHash}, r.history[admin.ID]...)
+	if len(history) > historySize {
+		history = history[:historySize]
+	}
+	r.history[admin.ID] = history
+	return nil
+}
+
+func (r *MemoryAdminRepository) PasswordHistory(ctx context.Context, adminID uint, limit int) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	history := r.history[adminID]
+	if limit < len(history) {
+		history = history[:limit]
+	}
+	return append([]string{}, history...), nil
+}
+
+func (r *MemoryAdminRepository) Delete(ctx context.Context, admin *models.Admin) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.admins[admin.ID]
+	if !ok || stored.DeletedAt.Valid {
+		return nil
+	}
+	stored.DeletedAt = gorm.DeletedAt{Time: utils.Now(), Valid: true}
+	r.admins[admin.ID] = stored
+	admin.DeletedAt = stored.DeletedAt
+	return nil
+}
+
+func (r *MemoryAdminRepository) ListDeleted(ctx context.Context) ([]models.Admin, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	admins := make([]models.Admin, 0)
+	for _, id := range sortedIDs(r.admins) {
+		if admin := r.admins[id]; admin.DeletedAt.Valid {
+			admins = append(admins, admin)
+		}
+	}
+	sort.SliceStable(admins, func(i, j int) bool {
+		if admins[i].DeletedAt.Time.Equal(admins[j].DeletedAt.Time) {
+			return admins[i].ID > admins[j].ID
+		}
+		return admins[i].DeletedAt.Time.After(admins[j].DeletedAt.Time)
+	})
+	return admins, nil
+}
+
+func (r *MemoryAdminRepository) FindDeleted(ctx context.Context, id uint) (*models.Admin, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	admin, ok := r.admins[id]
+	if !ok || !admin.DeletedAt.Valid {
+		return nil, models.ErrAdminNotFound
+	}
+	return &admin, nil
+}
+
+func (r *MemoryAdminRepository) Restore(ctx context.Context, admin *models.Admin) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.admins[admin.ID]
+	if !ok {
+		return nil
+	}
+	for id, other := range r.admins {
+		if id != admin.ID && !other.DeletedAt.Valid && other.Email == stored.Email {
+			return models.ErrEmailExists
+		}
+	}
+	stored.DeletedAt = gorm.DeletedAt{}
+	r.admins[admin.ID] = stored
+	admin.DeletedAt = stored.DeletedAt
+	return nil
+}
+
+func (r *MemoryAdminRepository) Purge(ctx context.Context, admin *models.Admin) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.admins, admin.ID)
+	delete(r.history, admin.ID)
+	return nil
+}
+
+func (r *MemoryAdminRepository) CreateWithSetupToken(ctx context.Context, admin *models.Admin, tokenHash string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	token, ok := r.setupTokens[tokenHash]
+	if !ok || token.used || !utils.Now().Before(token.expiresAt) || len(r.admins) > 0 {
+		return models.ErrSetupUnavailable
+	}
+
+	r.nextID++
+	now := utils.Now()
+	admin.ID = r.nextID
+	admin.CreatedAt, admin.UpdatedAt = now, now
+	r.admins[admin.ID] = *admin
+
+	token.used = true
+	r.setupTokens[tokenHash] = token
+	return nil
+}
+
+func (r *MemoryAdminRepository) update(ctx context.Context, admin *models.Admin, fields map[string]interface{}) error {
+	stored, ok := r.admins[admin.ID]
+	if !ok || stored.DeletedAt.Valid {
+		return models.ErrAdminNotFound
+	}
+	if email, ok := fields["email"].(string); ok && email != stored.Email {
+		for id, other := range r.admins {
+			if id != admin.ID && !other.DeletedAt.Valid && other.Email == email {
+				return models.ErrEmailExists
+			}
+		}
+	}
+	if err := applyFields(ctx, &stored, fields); err != nil {
+		return err
+	}
+	stored.UpdatedAt = utils.Now()
+	r.admins[admin.ID] = stored
+	*admin = stored
+	return nil
+}
+
+// MemoryApprovalRepository unit testler için bellekte çalışan ApprovalRepository'dir. Ertelenmiş işlemler
+// verilen bellek içi repository'ler üzerinde çalışır; işlem hata dönerse yaptığı değişiklikler geri alınır.
+type MemoryApprovalRepository struct {
+	mu       sync.Mutex
+	nextID   uint
+	requests map[uint]models.ApprovalRequest
+	admins   *MemoryAdminRepository
+	users    *MemoryUserRepository
+}
+
+func NewMemoryApprovalRepository(admins *MemoryAdminRepository, users *MemoryUserRepository) *MemoryApprovalRepository {
+	return &MemoryApprovalRepository{requests: make(map[uint]models.ApprovalRequest), admins: admins, users: users}
+}
+
+func (r *MemoryApprovalRepository) List(ctx context.Context, status models.ApprovalStatus) ([]models.ApprovalRequest, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	requests := make([]models.ApprovalRequest, 0)
+	for _, id := range sortedIDs(r.requests) {
+		if request := r.requests[id]; status == "" || request.Status == status {
+			requests = append(requests, request)
+		}
+	}
+	sort.SliceStable(requests, func(i, j int) bool {
+		if requests[i].CreatedAt.Equal(requests[j].CreatedAt) {
+			return requests[i].ID > requests[j].ID
+		}
+		return requests[i].CreatedAt.After(requests[j].CreatedAt)
+	})
+	return requests, nil
+}
+
+func (r *MemoryApprovalRepository) FindByID(ctx context.Context, id uint) (*models.ApprovalRequest, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	request, ok := r.requests[id]
+	if !ok {
+		return nil, models.ErrApprovalNotFound
+	}
+	return &request, nil
+}
+
+func (r *MemoryApprovalRepository) CreatePending(ctx context.Context, request *models.ApprovalRequest) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, id := range sortedIDs(r.requests) {
+		existing := r.requests[id]
+		if existing.Status != models.ApprovalStatusPending || existing.Action != request.Action ||
+			existing.TargetType != request.TargetType || existing.TargetID != request.TargetID || existing.TargetKey != request.TargetKey {
+			continue
+		}
+		if existing.IsPending() {
+			*request = existing
+			return models.ErrApprovalPending
+		}
+		// Süresi dolmuş ama henüz işaretlenmemiş istek yenisine yer açar
+		existing.Status = models.ApprovalStatusExpired
+		existing.UpdatedAt = utils.Now()
+		r.requests[id] = existing
+	}
+
+	r.nextID++
+	now := utils.Now()
+	request.ID = r.nextID
+	request.CreatedAt, request.UpdatedAt = now, now
+	if request.Status == "" {
+		request.Status = models.ApprovalStatusPending
+	}
+	r.requests[request.ID] = *request
+	return nil
+}
+
+func (r *MemoryApprovalRepository) Resolve(ctx context.Context, id uint, resolve ApprovalResolver) (*models.ApprovalRequest, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	request, ok := r.requests[id]
+	if !ok {
+		return nil, models.ErrApprovalNotFound
+	}
+
+	execute := func(action ApprovalExecutor) error {
+		admins, users := r.admins.snapshot(), r.users.snapshot()
+		if err := action(ctx, r.admins, r.users); err != nil {
+			r.admins.restore(admins)
+			r.users.restore(users)
+			return err
+		}
+		return nil
+	}
+	fields, err := resolve(&request, execute)
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) == 0 {
+		return &request, nil
+	}
+
+	stored := r.requests[id]
+	if err := applyFields(ctx, &stored, fields); err != nil {
+		return nil, err
+	}
+	stored.UpdatedAt = utils.Now()
+	r.requests[id] = stored
+	return &stored, nil
+}
+
+func (r *MemoryApprovalRepository) MarkExpired(ctx context.Context, id uint) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	request, ok := r.requests[id]
+	if !ok || request.Status != models.ApprovalStatusPending {
+		return nil
+	}
+	request.Status = models.ApprovalStatusExpired
+	request.UpdatedAt = utils.Now()
+	r.requests[id] = request
+	return nil
+}
+
+// memoryAdminState ve memoryUserState başarısız ertelenmiş işlemleri geri almak için alınan kopyalardır
+type memoryAdminState struct {
+	nextID  uint
+	admins  map[uint]models.Admin
+	history map[uint][]string
+}
+
+func (r *MemoryAdminRepository) snapshot() memoryAdminState {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	state := memoryAdminState{nextID: r.nextID, admins: make(map[uint]models.Admin, len(r.admins)), history: make(map[uint][]string, len(r.history))}
+	for id, admin := range r.admins {
+		state.admins[id] = admin
+	}
+	for id, hashes := range r.history {
+		state.history[id] = append([]string{}, hashes...)
+	}
+	return state
+}
+
+func (r *MemoryAdminRepository) restore(state memoryAdminState) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.nextID, r.admins, r.history = state.nextID, state.admins, state.history
+}
+
+type memoryUserState struct {
+	nextID uint
+	users  map[uint]models.User
+}
+
+func (r *MemoryUserRepository) snapshot() memoryUserState {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	state := memoryUserState{nextID: r.nextID, users: make(map[uint]models.User, len(r.users))}
+	for id, user := range r.users {
+		state.users[id] = user
+	}
+	return state
+}
+
+func (r *MemoryUserRepository) restore(state memoryUserState) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.nextID, r.users = state.nextID, state.users
+}
+
+var schemaCache sync.Map
+
+// MemoryNetworkRuleRepository unit testler için bellekte çalışan NetworkRuleRepository'dir
+type MemoryNetworkRuleRepository struct {
+	mu     sync.Mutex
+	nextID uint
+	rules  map[uint]models.AdminNetworkRule
+}
+
+func NewMemoryNetworkRuleRepository() *MemoryNetworkRuleRepository {
+	return &MemoryNetworkRuleRepository{rules: make(map[uint]models.AdminNetworkRule)}
+}
+
+func (r *MemoryNetworkRuleRepository) List(ctx context.Context, adminID *uint) ([]models.AdminNetworkRule, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rules := make([]models.AdminNetworkRule, 0)
+	for _, id := range sortedIDs(r.rules) {
+		rule := r.rules[id]
+		if adminID != nil && (rule.AdminID == nil || *rule.AdminID != *adminID) {
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func (r *MemoryNetworkRuleRepository) FindByID(ctx context.Context, id uint) (*models.AdminNetworkRule, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	rule, ok := r.rules[id]
+	if !ok {
+		return nil, models.ErrNetworkRuleNotFound
+	}
+	return &rule, nil
+}
+
+func (r *MemoryNetworkRuleRepository) Create(ctx context.Context, rule *models.AdminNetworkRule, verify NetworkRuleVerifier) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, err := models.ParseCIDR(rule.CIDR); err != nil {
+		return err
+	}
+
+	created := *rule
+	created.ID = r.nextID + 1
+	created.CreatedAt = utils.Now()
+	r.rules[created.ID] = created
+	if err := verify(r.sorted()); err != nil {
+		delete(r.rules, created.ID)
+		return err
+	}
+
+	r.nextID = created.ID
+	*rule = created
+	return nil
+}
+
+func (r *MemoryNetworkRuleRepository) Delete(ctx context.Context, rule *models.AdminNetworkRule, verify NetworkRuleVerifier) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.rules[rule.ID]
+	if !ok {
+		return nil
+	}
+	delete(r.rules, rule.ID)
+	if err := verify(r.sorted()); err != nil {
+		r.rules[rule.ID] = stored
+		return err
+	}
+	return nil
+}
+
+func (r *MemoryNetworkRuleRepository) sorted() []models.AdminNetworkRule {
+	rules := make([]models.AdminNetworkRule, 0, len(r.rules))
+	for _, id := range sortedIDs(r.rules) {
+		rules = append(rules, r.rules[id])
+	}
+	return rules
+}
+
+// applyFields GORM'un Updates(map) davranışına benzer şekilde kolon adlarıyla verilen değerleri modele yazar
+func applyFields(ctx context.Context, model interface{}, fields map[string]interface{}) error {
+	sch, err := schema.Parse(model, &schemaCache, schema.NamingStrategy{})
+	if err != nil {
+		return err
+	}
+
+	value := reflect.ValueOf(model).Elem()
+	for column, fieldValue := range fields {
+		field := sch.LookUpField(column)
+		if field == nil {
+			return fmt.Errorf("unknown column %q", column)
+		}
+		if err := field.Set(ctx, value, fieldValue); err != nil {
+			return fmt.Errorf("error setting %s: %v", column, err)
+		}
+	}
+	return nil
+}
+
+func sortedIDs[T any](records map[uint]T) []uint {
+	ids := make([]uint, 0, len(records))
+	for id := range records {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
@@ -0,0 +1,33 @@
+package repository_test
+
+import (
+	"testing"
+
+	"prototurk/internal/repository"
+	"prototurk/internal/repository/contract"
+)
+
+func TestMemoryUserRepository(t *testing.T) {
+	contract.UserRepository(t, func(t *testing.T) repository.UserRepository {
+		return repository.NewMemoryUserRepository()
+	})
+}
+
+func TestMemoryAdminRepository(t *testing.T) {
+	contract.AdminRepository(t, func(t *testing.T) repository.AdminRepository {
+		return repository.NewMemoryAdminRepository()
+	})
+}
+
+func TestMemoryApprovalRepository(t *testing.T) {
+	contract.ApprovalRepository(t, func(t *testing.T) (repository.ApprovalRepository, repository.AdminRepository) {
+		admins := repository.NewMemoryAdminRepository()
+		return repository.NewMemoryApprovalRepository(admins, repository.NewMemoryUserRepository()), admins
+	})
+}
+
+func TestMemoryNetworkRuleRepository(t *testing.T) {
+	contract.NetworkRuleRepository(t, func(t *testing.T) (repository.NetworkRuleRepository, repository.AdminRepository) {
+		return repository.NewMemoryNetworkRuleRepository(), repository.NewMemoryAdminRepository()
+	})
+}
@@ -0,0 +1,146 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"prototurk/internal/models"
+)
+
+// UserRepository kullanıcıların saklanmasını soyutlar. Silinmiş (soft delete) kullanıcılar sorgularda görünmez.
+// Bulunamayan kayıtlar için models.ErrUserNotFound döner.
+type UserRepository interface {
+	// Create kullanıcı adı veya email kullanılıyorsa models.ErrUsernameExists veya models.ErrEmailExists döner
+	Create(ctx context.Context, user *models.User) error
+	FindByID(ctx context.Context, id uint) (*models.User, error)
+	// FindByIdentifier kullanıcıyı kullanıcı adı veya email ile bulur
+	FindByIdentifier(ctx context.Context, identifier string) (*models.User, error)
+	UsernameExists(ctx context.Context, username string) (bool, error)
+	EmailExists(ctx context.Context, email string) (bool, error)
+	// Update verilen kolonları günceller ve user'a uygular
+	Update(ctx context.Context, user *models.User, fields map[string]interface{}) error
+	// DeleteByIDs verilen kullanıcıları siler ve silinen kullanıcı sayısını döner. Bulunamayan ID'ler atlanır.
+	DeleteByIDs(ctx context.Context, ids []uint) (int64, error)
+}
+
+// UserBulkRepository admin panelindeki toplu kullanıcı işlemlerini ve importu soyutlar. Kullanıcılar ID listesi
+// ve/veya filtre ile seçilir; silinmiş kullanıcılar seçilmez. Seçimin boş olmadığını çağıran doğrular.
+type UserBulkRepository interface {
+	Count(ctx context.Context, selection models.BulkUserRequest) (int64, error)
+	// IDs seçilen kullanıcıların ID'lerini artan sırayla döner
+	IDs(ctx context.Context, selection models.BulkUserRequest) ([]uint, error)
+	// UpdateStatus seçilen kullanıcıların durumunu değiştirir ve etkilenen kullanıcı sayısını döner
+	UpdateStatus(ctx context.Context, selection models.BulkUserRequest, status models.UserStatus) (int64, error)
+	// Delete seçilen kullanıcıları siler ve silinen kullanıcı sayısını döner
+	Delete(ctx context.Context, selection models.BulkUserRequest) (int64, error)
+	// ExistingIdentities verilen kullanıcı adı ve emaillerden silinmiş kullanıcılar dahil kullanımda olanları döner
+	ExistingIdentities(ctx context.Context, usernames []string, emails []string) (map[string]bool, map[string]bool, error)
+	// CreateBatch kullanıcıları tek sorguda oluşturur; biri oluşturulamazsa hiçbiri oluşturulmaz
+	CreateBatch(ctx context.Context, users []models.User) error
+}
+
+// AdminRepository adminlerin ve parola geçmişlerinin saklanmasını soyutlar. Silinmiş adminler sorgularda görünmez.
+// Bulunamayan kayıtlar için models.ErrAdminNotFound döner.
+type AdminRepository interface {
+	Create(ctx context.Context, admin *models.Admin) error
+	FindByID(ctx context.Context, id uint) (*models.Admin, error)
+	FindByEmail(ctx context.Context, email string) (*models.Admin, error)
+	List(ctx context.Context) ([]models.Admin, error)
+	// EmailExists excludeID dışındaki silinmemiş adminlerde email'in kullanılıp kullanılmadığını döner
+	EmailExists(ctx context.Context, email string, excludeID uint) (bool, error)
+	// FirstSuperAdmin en önce oluşturulmuş super admin'i döner
+	FirstSuperAdmin(ctx context.Context) (*models.Admin, error)
+	// Update verilen kolonları günceller ve admin'e uygular
+	Update(ctx context.Context, admin *models.Admin, fields map[string]interface{}) error
+	// UpdatePassword parola kolonlarını günceller ve previousHash'i geçmişe ekler; tek bir işlem olarak uygulanır.
+	// Geçmişte en fazla historySize kayıt tutulur, historySize sıfırsa geçmiş yazılmaz.
+	UpdatePassword(ctx context.Context, admin *models.Admin, fields map[string]interface{}, previousHash string, historySize int) error
+	// PasswordHistory en yeniden eskiye en fazla limit adet önceki parola hash'ini döner
+	PasswordHistory(ctx context.Context, adminID uint, limit int) ([]string, error)
+	Delete(ctx context.Context, admin *models.Admin) error
+	// ListDeleted silinmiş adminleri en son silinenden başlayarak döner
+	ListDeleted(ctx context.Context) ([]models.Admin, error)
+	// FindDeleted sadece silinmiş adminler arasında arar
+	FindDeleted(ctx context.Context, id uint) (*models.Admin, error)
+	// Restore silinmiş admin'i geri getirir. Email silinmemiş başka bir admin tarafından kullanılıyorsa
+	// models.ErrEmailExists döner.
+	Restore(ctx context.Context, admin *models.Admin) error
+	// Purge silinmiş admin'i parola geçmişiyle birlikte kalıcı olarak siler
+	Purge(ctx context.Context, admin *models.Admin) error
+	// CreateWithSetupToken tokenHash kullanılmamış ve süresi dolmamış bir kurulum token'ıyla eşleşiyorsa ve hiç admin
+	// (silinmişler dahil) yoksa admin'i oluşturup token'ı kullanılmış olarak işaretler; tek bir işlem olarak uygulanır.
+	// Eşzamanlı istekler aynı token'ı iki kez kullanamaz. Aksi halde models.ErrSetupUnavailable döner.
+	CreateWithSetupToken(ctx context.Context, admin *models.Admin, tokenHash string) error
+}
+
+// InvitationRepository admin davetlerinin saklanmasını soyutlar. Açık (kabul edilmemiş ve iptal edilmemiş) bir davet
+// bulunamazsa models.ErrInvitationNotFound döner.
+type InvitationRepository interface {
+	// Create bekleyen admin'i ve davetini tek bir işlem olarak oluşturur
+	Create(ctx context.Context, pendingAdmin *models.Admin, invitation *models.AdminInvitation) error
+	// ListOpen açık davetleri en yeniden başlayarak döner
+	ListOpen(ctx context.Context) ([]models.AdminInvitation, error)
+	FindOpen(ctx context.Context, id uint) (*models.AdminInvitation, error)
+	// Update verilen kolonları günceller ve invitation'a uygular
+	Update(ctx context.Context, invitation *models.AdminInvitation, fields map[string]interface{}) error
+	// Revoke daveti iptal eder ve bekleyen admin kaydını kalıcı olarak siler; tek bir işlem olarak uygulanır
+	Revoke(ctx context.Context, invitation *models.AdminInvitation) error
+	// Accept tokenHash ile eşleşen daveti kabul eder ve bekleyen admin'e fields'ı uygulayıp admin'i döner; tek bir
+	// işlem olarak uygulanır. Eşzamanlı istekler aynı daveti iki kez kabul edemez. Davet artık kabul edilemiyorsa
+	// models.ErrInvitationInvalid döner.
+	Accept(ctx context.Context, tokenHash string, fields map[string]interface{}) (*models.Admin, error)
+}
+
+// ApprovalRepository onay isteklerinin saklanmasını soyutlar. Bulunamayan kayıtlar için models.ErrApprovalNotFound döner.
+type ApprovalRepository interface {
+	// List istekleri en yeniden başlayarak döner; status boş değilse sadece o durumdakiler döner
+	List(ctx context.Context, status models.ApprovalStatus) ([]models.ApprovalRequest, error)
+	FindByID(ctx context.Context, id uint) (*models.ApprovalRequest, error)
+	// CreatePending aynı işlem ve hedef (target_type, target_id, target_key) için bekleyen bir istek yoksa request'i oluşturur. Varsa request'e bekleyen
+	// isteği yazar ve models.ErrApprovalPending döner. Süresi dolmuş ama henüz işaretlenmemiş istek expired yapılıp yenisine yer açar.
+	CreatePending(ctx context.Context, request *models.ApprovalRequest) error
+	// Resolve isteği eşzamanlı incelemelere karşı kilitler, resolve'un döndüğü kolonları uygular ve isteğin güncel
+	// halini döner; tek bir işlem olarak uygulanır. resolve hata dönerse hiçbir değişiklik uygulanmaz.
+	Resolve(ctx context.Context, id uint, resolve ApprovalResolver) (*models.ApprovalRequest, error)
+	// MarkExpired istek hala bekliyorsa expired olarak işaretler
+	MarkExpired(ctx context.Context, id uint) error
+}
+
+// ApprovalResolver kilitlenmiş isteği inceler ve güncellenecek kolonları döner. execute ertelenmiş işlemi isteğin
+// işlemi içinde kendi savepoint'inde çalıştırır; işlem başarısız olursa sadece onun değişiklikleri geri alınır.
+type ApprovalResolver func(request *models.ApprovalRequest, execute func(action ApprovalExecutor) error) (map[string]interface{}, error)
+
+// ApprovalExecutor ertelenmiş işlemi isteğin işlemine bağlı repository'ler üzerinden çalıştırır
+type ApprovalExecutor func(ctx context.Context, admins AdminRepository, users UserRepository) error
+
+// NetworkRuleRepository admin ağ kurallarının saklanmasını soyutlar. Bulunamayan kayıtlar için
+// models.ErrNetworkRuleNotFound döner.
+type NetworkRuleRepository interface {
+	// List kuralları ID sırasıyla döner; adminID verilirse sadece o admin'e ait kurallar döner
+	List(ctx context.Context, adminID *uint) ([]models.AdminNetworkRule, error)
+	FindByID(ctx context.Context, id uint) (*models.AdminNetworkRule, error)
+	// Create kuralı oluşturur ve verify'ı değişiklik uygulanmış kurallarla çağırır; tek bir işlem olarak uygulanır.
+	// verify hata dönerse kural oluşturulmaz.
+	Create(ctx context.Context, rule *models.AdminNetworkRule, verify NetworkRuleVerifier) error
+	// Delete kuralı siler ve verify'ı değişiklik uygulanmış kurallarla çağırır; tek bir işlem olarak uygulanır.
+	// verify hata dönerse kural silinmez.
+	Delete(ctx context.Context, rule *models.AdminNetworkRule, verify NetworkRuleVerifier) error
+}
+
+// NetworkRuleVerifier değişiklikten sonraki tüm kuralları inceler; hata dönerse değişiklik geri alınır
+type NetworkRuleVerifier func(rules []models.AdminNetworkRule) error
+
+// StatsRepository admin paneli istatistikleri için kullanıcı ve admin sayımlarını soyutlar. Sorgular read replica
+// tanımlıysa orada çalışır.
+type StatsRepository interface {
+	// CountRegistrationsByBucket from'dan sonra kayıt olan kullanıcıları UTC'ye göre interval (models.StatsIntervalDay
+	// veya models.StatsIntervalWeek) başlangıcına göre sayar. Anahtar bucket başlangıcının Unix zamanıdır.
+	CountRegistrationsByBucket(ctx context.Context, interval string, from time.Time) (map[int64]int64, error)
+	// CountLoginsByBucket son girişi from'dan sonra olan kullanıcıları CountRegistrationsByBucket gibi sayar
+	CountLoginsByBucket(ctx context.Context, interval string, from time.Time) (map[int64]int64, error)
+	CountUsersByStatus(ctx context.Context) (map[models.UserStatus]int64, error)
+	CountActiveAdminsByRole(ctx context.Context) (map[models.AdminRole]int64, error)
+	// CountRegistrationsAround previous <= created_at < current ve created_at >= current aralıklarındaki kayıtları
+	// tek sorguda sayar
+	CountRegistrationsAround(ctx context.Context, previous, current time.Time) (before int64, after int64, err error)
+}
@@ -0,0 +1,29 @@
+package seed
+
+import "strings"
+
+var firstNames = []string{
+	"Ahmet", "Mehmet", "Mustafa", "Ali", "Hüseyin", "Hasan", "İbrahim", "İsmail", "Osman", "Yusuf",
+	"Murat", "Ömer", "Emre", "Burak", "Serkan", "Oğuz", "Can", "Kerem", "Barış", "Çağlar",
+	"Ayşe", "Fatma", "Emine", "Hatice", "Zeynep", "Elif", "Meryem", "Şerife", "Sultan", "Hanife",
+	"Merve", "Büşra", "Esra", "Özge", "Gül", "Derya", "Ebru", "Tuğba", "Gökçe", "Şule",
+}
+
+var lastNames = []string{
+	"Yılmaz", "Kaya", "Demir", "Şahin", "Çelik", "Yıldız", "Yıldırım", "Öztürk", "Aydın", "Özdemir",
+	"Arslan", "Doğan", "Kılıç", "Aslan", "Çetin", "Kara", "Koç", "Kurt", "Özkan", "Şimşek",
+	"Polat", "Özcan", "Korkmaz", "Çakır", "Erdoğan", "Yavuz", "Can", "Acar", "Güneş", "Aktaş",
+}
+
+// emailDomains RFC 2606 ile ayrılmış, gerçek kullanıcılara ulaşmayan domain'lerdir
+var emailDomains = []string{"example.com", "example.org", "example.net"}
+
+var asciiReplacer = strings.NewReplacer(
+	"ç", "c", "Ç", "c", "ğ", "g", "Ğ", "g", "ı", "i", "İ", "i",
+	"ö", "o", "Ö", "o", "ş", "s", "Ş", "s", "ü", "u", "Ü", "u",
+)
+
+// slug Türkçe karakterleri ASCII karşılıklarına çevirip küçük harfe dönüştürür
+func slug(name string) string {
+	return strings.ToLower(asciiReplacer.Replace(name))
+}
@@ -0,0 +1,256 @@
+// Package seed geliştirme ve yük testleri için seed değerinden deterministik sahte veri üretir
+package seed
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"time"
+
+	"prototurk/internal/database"
+	"prototurk/internal/models"
+
+	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrProductionDatabase production olarak işaretlenmiş veritabanlarında seed çalıştırılmasını engeller
+var ErrProductionDatabase = errors.New("refusing to seed a database marked as production")
+
+const defaultBatchSize = 1000
+
+// AdminSpec profilde oluşturulacak bir admin'i tanımlar
+type AdminSpec struct {
+	Email              string
+	Role               models.AdminRole
+	Status             models.AdminStatus
+	MustChangePassword bool
+}
+
+// Profile üretilecek verinin miktarını belirler
+type Profile struct {
+	Name  string
+	Users int
+	// HistoryDays kullanıcı kayıt tarihlerinin referans tarihten ne kadar geriye yayılacağıdır
+	HistoryDays int
+	Admins      []AdminSpec
+}
+
+var baseAdmins = []AdminSpec{
+	{Email: "seed-super-admin@example.com", Role: models.AdminRoleSuperAdmin, Status: models.AdminStatusActive},
+	{Email: "seed-admin@example.com", Role: models.AdminRoleAdmin, Status: models.AdminStatusActive},
+	{Email: "seed-editor@example.com", Role: models.AdminRoleEditor, Status: models.AdminStatusActive},
+}
+
+var Profiles = map[string]Profile{
+	"minimal": {Name: "minimal", Users: 10, HistoryDays: 30, Admins: baseAdmins},
+	"demo": {Name: "demo", Users: 500, HistoryDays: 180, Admins: append(append([]AdminSpec{}, baseAdmins...),
+		AdminSpec{Email: "seed-passive-admin@example.com", Role: models.AdminRoleAdmin, Status: models.AdminStatusPassive},
+		AdminSpec{Email: "seed-new-editor@example.com", Role: models.AdminRoleEditor, Status: models.AdminStatusActive, MustChangePassword: true},
+	)},
+	"load-test": {Name: "load-test", Users: 100000, HistoryDays: 365, Admins: baseAdmins},
+}
+
+// ProfileNames bilinen profil adlarını sıralı olarak döner
+func ProfileNames() []string {
+	names := make([]string, 0, len(Profiles))
+	for name := range Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+type Options struct {
+	Profile Profile
+	// Seed aynı değerle her çalıştırmada aynı kullanıcı adları, email'ler, statüler ve zamanlar üretilir
+	Seed int64
+	// Reference kayıt ve giriş zamanlarının hesaplandığı "şimdi"dir
+	Reference     time.Time
+	AdminPassword string
+	UserPassword  string
+	BatchSize     int
+}
+
+// Report seed sonucunda eklenen ve zaten var olduğu için atlanan kayıt sayılarıdır
+type Report struct {
+	AdminsCreated int64
+	AdminsSkipped int64
+	UsersCreated  int64
+	UsersSkipped  int64
+}
+
+// Run profile göre admin ve kullanıcıları ekler. Email veya kullanıcı adı zaten varsa kayıt atlanır,
+// bu yüzden aynı seed ile tekrar çalıştırmak veriyi çoğaltmaz.
+func Run(db *gorm.DB, options Options) (*Report, error) {
+	production, err := database.IsProduction(db)
+	if err != nil {
+		return nil, fmt.Errorf("error reading environment marker: %v", err)
+	}
+	if production {
+		return nil, ErrProductionDatabase
+	}
+
+	if options.BatchSize <= 0 {
+		options.BatchSize = defaultBatchSize
+	}
+	report := &Report{}
+
+	admins, err := generateAdmins(options)
+	if err != nil {
+		return nil, err
+	}
+	result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&admins)
+	if result.Error != nil {
+		return nil, fmt.Errorf("error seeding admins: %v", result.Error)
+	}
+	report.AdminsCreated = result.RowsAffected
+	report.AdminsSkipped = int64(len(admins)) - result.RowsAffected
+
+	// Binlerce kullanıcı için bcrypt maliyetinden kaçınmak adına tüm kullanıcılar aynı hash'i paylaşır
+	userHash, err := bcrypt.GenerateFromPassword([]byte(options.UserPassword), bcrypt.MinCost)
+	if err != nil {
+		return nil, err
+	}
+
+	generator := newGenerator(options.Seed, options.Reference, options.Profile.HistoryDays)
+	for start := 0; start < options.Profile.Users; start += options.BatchSize {
+		end := start + options.BatchSize
+		if end > options.Profile.Users {
+			end = options.Profile.Users
+		}
+
+		users := make([]models.User, 0, end-start)
+		for i := start; i < end; i++ {
+			users = append(users, generator.user(i, string(userHash)))
+		}
+
+		result := db.Clauses(clause.OnConflict{DoNothing: true}).Create(&users)
+		if result.Error != nil {
+			return report, fmt.Errorf("error seeding users %d-%d: %v", start+1, end, result.Error)
+		}
+		report.UsersCreated += result.RowsAffected
+		report.UsersSkipped += int64(len(users)) - result.RowsAffected
+	}
+
+	return report, nil
+}
+
+func generateAdmins(options Options) ([]models.Admin, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(options.AdminPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+
+	// Adminler kullanıcılardan ayrı bir kaynaktan üretilir; profil değişince kullanıcı verisi kaymaz
+	generator := newGenerator(options.Seed+1, options.Reference, 90)
+	admins := make([]models.Admin, 0, len(options.Profile.Admins))
+	for _, spec := range options.Profile.Admins {
+		first, last := generator.name()
+		createdAt := generator.createdAt()
+		lastLogin := generator.lastLogin(createdAt)
+		admins = append(admins, models.Admin{
+			Model:              gorm.Model{CreatedAt: createdAt, UpdatedAt: createdAt},
+			Email:              spec.Email,
+			Name:               first + " " + last,
+			Password:           string(hash),
+			Role:               spec.Role,
+			Status:             spec.Status,
+			LastLogin:          lastLogin,
+			MustChangePassword: spec.MustChangePassword,
+			PasswordChangedAt:  &createdAt,
+		})
+	}
+	return admins, nil
+}
+
+type generator struct {
+	rng         *rand.Rand
+	reference   time.Time
+	historyDays int
+}
+
+func newGenerator(seed int64, reference time.Time, historyDays int) *generator {
+	return &generator{rng: rand.New(rand.NewSource(seed)), reference: reference.UTC(), historyDays: historyDays}
+}
+
+func (g *generator) name() (string, string) {
+	return firstNames[g.rng.Intn(len(firstNames))], lastNames[g.rng.Intn(len(lastNames))]
+}
+
+// user i. kullanıcıyı üretir. İlk üç kullanıcı her statünün en az bir kez bulunmasını garanti eder.
+func (g *generator) user(i int, passwordHash string) models.User {
+	first, last := g.name()
+	username := fmt.Sprintf("%s.%s%d", slug(first), slug(last), i+1)
+	email := username + "@" + emailDomains[g.rng.Intn(len(emailDomains))]
+
+	createdAt := g.createdAt()
+	lastLogin := g.lastLogin(createdAt)
+
+	return models.User{
+		Model:         gorm.Model{CreatedAt: createdAt, UpdatedAt: lastLogin},
+		Username:      username,
+		Email:         email,
+		Password:      passwordHash,
+		Status:        g.status(i),
+		LastLoginDate: lastLogin,
+	}
+}
+
+func (g *generator) status(i int) models.UserStatus {
+	statuses := []models.UserStatus{models.UserStatusActive, models.UserStatusPassive, models.UserStatusBanned}
+	if i < len(statuses) {
+		g.rng.Float64() // Her kullanıcı aynı sayıda rastgele değer tüketsin
+		return statuses[i]
+	}
+
+	switch p := g.rng.Float64(); {
+	case p < 0.82:
+		return models.UserStatusActive
+	case p < 0.96:
+		return models.UserStatusPassive
+	default:
+		return models.UserStatusBanned
+	}
+}
+
+// createdAt kayıtları son günlere daha yoğun olacak şekilde geçmişe yayar, saatleri Türkiye'de gündüz ve akşama denk getirir
+func (g *generator) createdAt() time.Time {
+	daysAgo := int(float64(g.historyDays) * math.Pow(g.rng.Float64(), 1.5))
+	day := g.reference.Truncate(24*time.Hour).AddDate(0, 0, -daysAgo)
+	t := day.Add(g.activeHour())
+	if t.After(g.reference) {
+		t = g.reference.Add(-time.Duration(g.rng.Intn(3600)) * time.Second)
+	}
+	return t
+}
+
+// lastLogin kullanıcıların bir kısmını hiç giriş yapmamış (kayıt anı), kalanını çoğunlukla yakın zamanda giriş yapmış olarak üretir
+func (g *generator) lastLogin(createdAt time.Time) time.Time {
+	if g.rng.Float64() < 0.1 {
+		return createdAt
+	}
+	span := g.reference.Sub(createdAt)
+	t := g.reference.Add(-time.Duration(float64(span) * math.Pow(g.rng.Float64(), 3)))
+	day := t.Truncate(24 * time.Hour)
+	if withHour := day.Add(g.activeHour()); withHour.After(createdAt) && withHour.Before(g.reference) {
+		return withHour
+	}
+	return t
+}
+
+// activeHour 08:00-24:00 (UTC+3) arasında, akşam saatlerine ağırlık veren bir gün içi süre döner
+func (g *generator) activeHour() time.Duration {
+	hour := 8 + int(16*math.Sqrt(g.rng.Float64()))
+	if hour > 23 {
+		hour = 23
+	}
+	offset := time.Duration(hour-3)*time.Hour + time.Duration(g.rng.Intn(3600))*time.Second
+	if offset < 0 {
+		offset += 24 * time.Hour
+	}
+	return offset
+}
@@ -0,0 +1,115 @@
+// Package server HTTP sunucusunu zaman aşımlarıyla çalıştırır ve kapanışta
+// istekleri boşaltıp bağımlı bileşenleri sırayla durdurur
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"prototurk/internal/config"
+)
+
+// hook kapanışta çalıştırılacak bir adımdır
+type hook struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+type Server struct {
+	http            *http.Server
+	shutdownDelay   time.Duration
+	shutdownTimeout time.Duration
+	hooks           []hook
+	draining        atomic.Bool
+}
+
+func New(handler http.Handler, config config.ServerConfig) *Server {
+	return &Server{
+		http: &http.Server{
+			Addr:              ":" + strconv.Itoa(config.Port),
+			Handler:           handler,
+			ReadHeaderTimeout: config.ReadHeaderTimeout,
+			ReadTimeout:       config.ReadTimeout,
+			WriteTimeout:      config.WriteTimeout,
+			IdleTimeout:       config.IdleTimeout,
+			MaxHeaderBytes:    config.MaxHeaderBytes,
+		},
+		shutdownDelay:   config.ShutdownDelay,
+		shutdownTimeout: config.ShutdownTimeout,
+	}
+}
+
+// Draining kapanış sinyali alındıktan sonra true döner; readiness kontrolü bu sırada başarısız olmalıdır
+func (s *Server) Draining() bool {
+	return s.draining.Load()
+}
+
+// OnShutdown HTTP istekleri boşaltıldıktan sonra çalışacak bir adım ekler.
+// Adımlar eklenme sırasıyla ve aynı kapanış süresi içinde çalışır; bir adımın hatası sonrakileri durdurmaz.
+func (s *Server) OnShutdown(name string, fn func(ctx context.Context) error) {
+	s.hooks = append(s.hooks, hook{name: name, fn: fn})
+}
+
+// Run sunucuyu yapılandırılmış portta başlatır ve ctx iptal edilene kadar (örneğin SIGTERM) bekler
+func (s *Server) Run(ctx context.Context) error {
+	listener, err := net.Listen("tcp", s.http.Addr)
+	if err != nil {
+		return err
+	}
+	return s.Serve(ctx, listener)
+}
+
+// Serve verilen listener üzerinden istekleri karşılar. ctx iptal edildiğinde önce Draining true olur ve
+// shutdown delay boyunca istekler kabul edilmeye devam eder; ardından yeni bağlantılar reddedilir,
+// devam eden istekler kapanış süresi dolana kadar beklenir ve kapanış adımları çalıştırılır.
+func (s *Server) Serve(ctx context.Context, listener net.Listener) error {
+	serveErr := make(chan error, 1)
+	go func() {
+		slog.Info("Server listening", "addr", listener.Addr().String())
+		serveErr <- s.http.Serve(listener)
+	}()
+
+	select {
+	case err := <-serveErr:
+		// Sunucu kendiliğinden durduysa da bileşenler kapatılır
+		return errors.Join(err, s.shutdown())
+	case <-ctx.Done():
+	}
+
+	s.draining.Store(true)
+	if s.shutdownDelay > 0 {
+		slog.Info("Shutdown signal received, reporting not ready", "delay", s.shutdownDelay.String())
+		time.Sleep(s.shutdownDelay)
+	}
+
+	slog.Info("Shutting down, draining requests", "timeout", s.shutdownTimeout.String())
+	err := s.shutdown()
+	if serveErr := <-serveErr; !errors.Is(serveErr, http.ErrServerClosed) {
+		err = errors.Join(serveErr, err)
+	}
+	return err
+}
+
+func (s *Server) shutdown() error {
+	ctx, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
+	defer cancel()
+
+	var errs []error
+	if err := s.http.Shutdown(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("http server: %v", err))
+	}
+	for _, h := range s.hooks {
+		if err := h.fn(ctx); err != nil {
+			slog.Error("Error during shutdown", "component", h.name, "error", err)
+			errs = append(errs, fmt.Errorf("%s: %v", h.name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
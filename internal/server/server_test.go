@@ -0,0 +1,90 @@
+package server
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"prototurk/internal/config"
+)
+
+// Kapanış sinyali devam eden bir isteği kesmemeli; istek tamamlanmalı ve Serve kapanış süresi içinde dönmelidir.
+func TestServeDrainsInFlightRequest(t *testing.T) {
+	const requestDuration = 300 * time.Millisecond
+
+	started := make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		time.Sleep(requestDuration)
+		w.WriteHeader(http.StatusOK)
+	})
+
+	serverConfig := config.Default().Server
+	serverConfig.ShutdownDelay = 0
+	serverConfig.ShutdownTimeout = 2 * time.Second
+	srv := New(handler, serverConfig)
+
+	hookCalled := false
+	srv.OnShutdown("test", func(ctx context.Context) error {
+		hookCalled = true
+		return nil
+	})
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	serveErr := make(chan error, 1)
+	go func() { serveErr <- srv.Serve(ctx, listener) }()
+
+	type result struct {
+		status int
+		err    error
+	}
+	response := make(chan result, 1)
+	go func() {
+		resp, err := http.Get("http://" + listener.Addr().String())
+		if err != nil {
+			response <- result{err: err}
+			return
+		}
+		resp.Body.Close()
+		response <- result{status: resp.StatusCode}
+	}()
+
+	select {
+	case <-started:
+	case <-time.After(2 * time.Second):
+		t.Fatal("request did not reach the handler")
+	}
+	cancel()
+
+	res := <-response
+	if res.err != nil {
+		t.Fatalf("request failed during shutdown: %v", res.err)
+	}
+	if res.status != http.StatusOK {
+		t.Fatalf("status = %d, want %d", res.status, http.StatusOK)
+	}
+
+	select {
+	case err := <-serveErr:
+		if err != nil {
+			t.Fatalf("Serve returned error: %v", err)
+		}
+	case <-time.After(serverConfig.ShutdownTimeout):
+		t.Fatal("Serve did not return within the shutdown timeout")
+	}
+
+	if !srv.Draining() {
+		t.Error("Draining() = false after shutdown")
+	}
+	if !hookCalled {
+		t.Error("shutdown hook was not called")
+	}
+}
@@ -0,0 +1,437 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"prototurk/internal/models"
+	"prototurk/internal/repository"
+	"prototurk/pkg/totp"
+	"prototurk/pkg/utils"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// AdminService admin yönetimine ait yetki ve iş kurallarını HTTP katmanından bağımsız olarak uygular
+type AdminService struct {
+	admins         repository.AdminRepository
+	passwordPolicy models.AdminPasswordPolicy
+}
+
+func NewAdminService(admins repository.AdminRepository, passwordPolicy models.AdminPasswordPolicy) *AdminService {
+	return &AdminService{admins: admins, passwordPolicy: passwordPolicy}
+}
+
+// AdminUpdateOptions güncellemeyi yapan isteğin bağlamını taşır
+type AdminUpdateOptions struct {
+	// Reauthenticated admin'in reauth penceresi içinde olup olmadığıdır; rol ve parola değişiklikleri bunu gerektirir
+	Reauthenticated bool
+	// DeferRoleChange true ise rol değişikliği uygulanmaz, onay için AdminUpdateResult.DeferredRole ile döner
+	DeferRoleChange bool
+}
+
+// AdminUpdateResult güncellemenin sonucunu ve handler'ın yapması gereken ek işlemleri (audit, onay isteği) belirtir
+type AdminUpdateResult struct {
+	Admin        *models.Admin
+	PreviousRole models.AdminRole
+	RoleChanged  bool
+	DeferredRole models.AdminRole
+}
+
+// Create yeni bir admin oluşturur (Sadece super admin yapabilir).
+// Parolayı super admin belirlediği için yeni admin ilk girişte parolasını değiştirmelidir.
+func (s *AdminService) Create(ctx context.Context, actor *models.Admin, req models.CreateAdminRequest) (*models.Admin, error) {
+	if actor.Role != models.AdminRoleSuperAdmin {
+		return nil, forbidden("Super admin permission required")
+	}
+	if !req.Role.ValidateRole() {
+		return nil, models.ErrInvalidRole
+	}
+
+	// İlk super admin varken ikinci bir super admin oluşturulamaz
+	if req.Role == models.AdminRoleSuperAdmin {
+		if _, err := s.admins.FirstSuperAdmin(ctx); err == nil {
+			return nil, forbidden("Cannot create another super admin while first super admin exists")
+		} else if !errors.Is(err, models.ErrAdminNotFound) {
+			return nil, err
+		}
+	}
+
+	if !req.Status.ValidateStatus() {
+		return nil, models.ErrInvalidStatus
+	}
+
+	// Email kontrolü - sadece aktif (silinmemiş) admin'lerde kontrol et
+	taken, err := s.admins.EmailExists(ctx, req.Email, 0)
+	if err != nil {
+		return nil, err
+	}
+	if taken {
+		return nil, models.ErrEmailExists
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+
+	now := utils.Now()
+	admin := &models.Admin{
+		Email:              req.Email,
+		Name:               req.Name,
+		Password:           string(hashedPassword),
+		Role:               req.Role,
+		Status:             req.Status,
+		MustChangePassword: true,
+		PasswordChangedAt:  &now,
+	}
+	if err := s.admins.Create(ctx, admin); err != nil {
+		return nil, err
+	}
+	return admin, nil
+}
+
+func (s *AdminService) List(ctx context.Context) ([]models.Admin, error) {
+	return s.admins.List(ctx)
+}
+
+func (s *AdminService) Get(ctx context.Context, id uint) (*models.Admin, error) {
+	return s.admins.FindByID(ctx, id)
+}
+
+// AuthorizeUpdate actor'ün hedef admin'i güncelleyip güncelleyemeyeceğini kontrol edip hedefi döner.
+// Super admin herkesi, diğer adminler sadece kendilerini güncelleyebilir; ilk super admin'i sadece kendisi güncelleyebilir.
+func (s *AdminService) AuthorizeUpdate(ctx context.Context, actor *models.Admin, id uint) (*models.Admin, error) {
+	target, err := s.admins.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	first, err := s.IsFirstSuperAdmin(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	if first && actor.ID != target.ID {
+		return nil, forbidden("Cannot update first super admin")
+	}
+	if actor.ID != target.ID && actor.Role != models.AdminRoleSuperAdmin {
+		return nil, forbidden("Super admin permission required")
+	}
+	return target, nil
+}
+
+// Update AuthorizeUpdate ile alınan hedef admin'e isteği uygular.
+// Parola değişikliği geçmiş kontrolü ile birlikte diğer alanlarla aynı işlemde uygulanır;
+// başka bir admin'in parolasını belirleyen super admin, o admin'i ilk girişte parola değiştirmeye zorlar.
+func (s *AdminService) Update(ctx context.Context, actor *models.Admin, target *models.Admin, req models.UpdateAdminRequest, options AdminUpdateOptions) (*AdminUpdateResult, error) {
+	if (req.Role != "" || req.Password != "") && !options.Reauthenticated {
+		return nil, ErrReauthRequired
+	}
+
+	result := &AdminUpdateResult{Admin: target, PreviousRole: target.Role}
+	updates := make(map[string]interface{})
+
+	if req.Email != "" && req.Email != target.Email {
+		taken, err := s.admins.EmailExists(ctx, req.Email, target.ID)
+		if err != nil {
+			return nil, err
+		}
+		if taken {
+			return nil, models.ErrEmailExists
+		}
+		updates["email"] = req.Email
+	}
+
+	if req.Name != "" {
+		updates["name"] = req.Name
+	}
+
+	first := false
+	if req.Role != "" || req.Status != "" {
+		var err error
+		if first, err = s.IsFirstSuperAdmin(ctx, target); err != nil {
+			return nil, err
+		}
+	}
+	// Super admin olmayan adminler kendi rol ve statülerini değiştiremez
+	selfRestricted := actor.ID == target.ID && actor.Role != models.AdminRoleSuperAdmin
+
+	if req.Role != "" {
+		if first {
+			return nil, forbidden("First super admin role cannot be changed")
+		}
+		if !actor.CanUpdateRole() || selfRestricted {
+			return nil, forbidden("Cannot update role")
+		}
+		if !req.Role.ValidateRole() {
+			return nil, models.ErrInvalidRole
+		}
+		if req.Role != target.Role {
+			// Onay gerektiren rol değişikliği diğer alanlardan ayrılır ve ikinci bir super admin onaylayana kadar ertelenir
+			if options.DeferRoleChange {
+				result.DeferredRole = req.Role
+			} else {
+				updates["role"] = req.Role
+				result.RoleChanged = true
+			}
+		}
+	}
+
+	if req.Status != "" {
+		if first {
+			return nil, forbidden("First super admin status cannot be changed")
+		}
+		if !actor.CanUpdateStatus() || selfRestricted {
+			return nil, forbidden("Cannot update status")
+		}
+		if !req.Status.ValidateStatus() {
+			return nil, models.ErrInvalidStatus
+		}
+		updates["status"] = req.Status
+	}
+
+	if req.Password != "" {
+		if err := s.setPassword(ctx, target, req.Password, actor.ID != target.ID, updates); err != nil {
+			return nil, err
+		}
+	} else if len(updates) > 0 {
+		if err := s.admins.Update(ctx, target, updates); err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// AuthorizeDelete actor'ün verilen admin'i silip silemeyeceğini kontrol edip hedefi döner
+func (s *AdminService) AuthorizeDelete(ctx context.Context, actor *models.Admin, id uint) (*models.Admin, error) {
+	if !actor.CanDeleteAdmin() {
+		return nil, forbidden("Super admin permission required")
+	}
+	if actor.ID == id {
+		return nil, forbidden("You cannot delete yourself")
+	}
+
+	target, err := s.admins.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	first, err := s.IsFirstSuperAdmin(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	if first {
+		return nil, forbidden("Cannot delete first super admin")
+	}
+	return target, nil
+}
+
+func (s *AdminService) Delete(ctx context.Context, target *models.Admin) error {
+	return s.admins.Delete(ctx, target)
+}
+
+// ListDeleted silinmiş adminleri listeler (Sadece super admin yapabilir)
+func (s *AdminService) ListDeleted(ctx context.Context, actor *models.Admin) ([]models.Admin, error) {
+	if !actor.CanManageTrash() {
+		return nil, forbidden("Super admin permission required")
+	}
+	return s.admins.ListDeleted(ctx)
+}
+
+// FindDeleted çöp kutusundaki bir admin'i döner (Sadece super admin yapabilir)
+func (s *AdminService) FindDeleted(ctx context.Context, actor *models.Admin, id uint) (*models.Admin, error) {
+	if !actor.CanManageTrash() {
+		return nil, forbidden("Super admin permission required")
+	}
+	return s.admins.FindDeleted(ctx, id)
+}
+
+// Restore FindDeleted ile alınan admin'i geri getirir.
+// Aynı email ile sonradan oluşturulmuş bir admin varsa *EmailInUseError döner.
+func (s *AdminService) Restore(ctx context.Context, target *models.Admin) error {
+	existing, err := s.admins.FindByEmail(ctx, target.Email)
+	if err == nil {
+		return &EmailInUseError{AdminID: existing.ID}
+	}
+	if !errors.Is(err, models.ErrAdminNotFound) {
+		return err
+	}
+	return s.admins.Restore(ctx, target)
+}
+
+// Purge FindDeleted ile alınan admin'i kalıcı olarak siler
+func (s *AdminService) Purge(ctx context.Context, target *models.Admin) error {
+	return s.admins.Purge(ctx, target)
+}
+
+// Setup log'a yazılan kurulum token'ı ile ilk super admin'i oluşturur.
+// Token geçersizse veya kurulum tamamlanmışsa models.ErrSetupUnavailable döner.
+func (s *AdminService) Setup(ctx context.Context, req models.AdminSetupRequest) (*models.Admin, error) {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+
+	now := utils.Now()
+	admin := &models.Admin{
+		Email:             req.Email,
+		Name:              req.Name,
+		Password:          string(hashedPassword),
+		Role:              models.AdminRoleSuperAdmin,
+		Status:            models.AdminStatusActive,
+		PasswordChangedAt: &now,
+	}
+	if err := s.admins.CreateWithSetupToken(ctx, admin, utils.HashToken(req.Token)); err != nil {
+		return nil, err
+	}
+	return admin, nil
+}
+
+// SetTwoFactorSecret admin'e yeni bir 2FA secret'ı kaydeder; 2FA ilk kod doğrulanana kadar aktif olmaz
+func (s *AdminService) SetTwoFactorSecret(ctx context.Context, admin *models.Admin, secret string) error {
+	return s.admins.Update(ctx, admin, map[string]interface{}{"two_factor_secret": secret})
+}
+
+// EnableTwoFactor kaydedilmiş secret ile 2FA'yı aktif eder
+func (s *AdminService) EnableTwoFactor(ctx context.Context, admin *models.Admin) error {
+	return s.admins.Update(ctx, admin, map[string]interface{}{"two_factor_enabled": true})
+}
+
+// ForcePasswordChange hedef admin'in bir sonraki isteğinde parolasını değiştirmesini zorunlu kılar (Sadece super admin yapabilir)
+func (s *AdminService) ForcePasswordChange(ctx context.Context, actor *models.Admin, id uint) (*models.Admin, error) {
+	if !actor.CanForcePasswordChange() {
+		return nil, forbidden("Super admin permission required")
+	}
+
+	target, err := s.admins.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// İlk super admin'e sadece kendisi zorunluluk koyabilir
+	first, err := s.IsFirstSuperAdmin(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	if first && actor.ID != target.ID {
+		return nil, forbidden("Cannot update first super admin")
+	}
+
+	if err := s.admins.Update(ctx, target, map[string]interface{}{"must_change_password": true}); err != nil {
+		return nil, err
+	}
+	return target, nil
+}
+
+// ChangePassword admin'in mevcut parolasını doğrulayıp kendi parolasını değiştirir
+func (s *AdminService) ChangePassword(ctx context.Context, admin *models.Admin, currentPassword string, newPassword string) error {
+	if err := bcrypt.CompareHashAndPassword([]byte(admin.Password), []byte(currentPassword)); err != nil {
+		return models.ErrInvalidPassword
+	}
+	return s.setPassword(ctx, admin, newPassword, false, nil)
+}
+
+// Authenticate email, parola ve aktifse 2FA kodu ile admin girişini doğrular.
+// Parola doğrulanmadan hesabın var olup olmadığı belli edilmez.
+func (s *AdminService) Authenticate(ctx context.Context, email string, password string, code string) (*models.Admin, error) {
+	admin, err := s.admins.FindByEmail(ctx, email)
+	if errors.Is(err, models.ErrAdminNotFound) {
+		return nil, ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(admin.Password), []byte(password)); err != nil {
+		return nil, ErrInvalidCredentials
+	}
+	// Hesap durumu sadece parolayı bilen birine gösterilir
+	if !admin.IsActive() {
+		return nil, models.ErrAdminNotActive
+	}
+	if err := verifyTwoFactor(admin, code); err != nil {
+		return nil, err
+	}
+	return admin, nil
+}
+
+// Reauthenticate giriş yapmış admin'in parolasını ve aktifse 2FA kodunu tekrar doğrular
+func (s *AdminService) Reauthenticate(admin *models.Admin, password string, code string) error {
+	if err := bcrypt.CompareHashAndPassword([]byte(admin.Password), []byte(password)); err != nil {
+		return models.ErrInvalidPassword
+	}
+	return verifyTwoFactor(admin, code)
+}
+
+// RecordLogin son giriş tarihini günceller
+func (s *AdminService) RecordLogin(ctx context.Context, admin *models.Admin) error {
+	return s.admins.Update(ctx, admin, map[string]interface{}{"last_login": utils.Now()})
+}
+
+// RequiresPasswordChange admin'in devam etmeden önce parolasını değiştirmesi gerekip gerekmediğini döner
+func (s *AdminService) RequiresPasswordChange(admin *models.Admin) bool {
+	return s.passwordPolicy.RequiresPasswordChange(admin)
+}
+
+// IsFirstSuperAdmin admin'in ilk oluşturulan super admin olup olmadığını döner
+func (s *AdminService) IsFirstSuperAdmin(ctx context.Context, admin *models.Admin) (bool, error) {
+	return IsFirstSuperAdmin(ctx, s.admins, admin)
+}
+
+// IsFirstSuperAdmin verilen repository üzerinden (örneğin bir transaction içinde) ilk super admin kontrolü yapar
+func IsFirstSuperAdmin(ctx context.Context, admins repository.AdminRepository, admin *models.Admin) (bool, error) {
+	first, err := admins.FirstSuperAdmin(ctx)
+	if errors.Is(err, models.ErrAdminNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return first.ID == admin.ID, nil
+}
+
+// setPassword parolayı politikaya göre kontrol edip günceller ve eski hash'i geçmişe ekler.
+// extra aynı işlem içinde uygulanacak diğer alan güncellemeleridir.
+// Son parolalardan biri tekrar kullanılırsa models.ErrPasswordReused döner.
+func (s *AdminService) setPassword(ctx context.Context, admin *models.Admin, password string, mustChange bool, extra map[string]interface{}) error {
+	history, err := s.admins.PasswordHistory(ctx, admin.ID, s.passwordPolicy.HistorySize)
+	if err != nil {
+		return err
+	}
+
+	recent := append([]string{admin.Password}, history...)
+	for _, hash := range recent {
+		if hash != "" && bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil {
+			return models.ErrPasswordReused
+		}
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+
+	updates := map[string]interface{}{}
+	for k, v := range extra {
+		updates[k] = v
+	}
+	updates["password"] = string(hashedPassword)
+	updates["password_changed_at"] = utils.Now()
+	updates["must_change_password"] = mustChange
+
+	return s.admins.UpdatePassword(ctx, admin, updates, admin.Password, s.passwordPolicy.HistorySize)
+}
+
+func verifyTwoFactor(admin *models.Admin, code string) error {
+	if !admin.TwoFactorEnabled {
+		return nil
+	}
+	if code == "" {
+		return ErrTwoFactorRequired
+	}
+	if !totp.Validate(admin.TwoFactorSecret, code, utils.Now()) {
+		return ErrInvalidTwoFactorCode
+	}
+	return nil
+}
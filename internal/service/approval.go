@@ -0,0 +1,277 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"prototurk/internal/models"
+	"prototurk/internal/repository"
+	"prototurk/pkg/utils"
+)
+
+// bulkDeleteBatchSize onaylanan toplu silmelerde tek sorguda silinen kullanıcı sayısıdır
+const bulkDeleteBatchSize = 1000
+
+// ApprovalService ikinci bir super admin onayı gerektiren işlemler için istek oluşturur, istekleri inceler ve
+// onaylanan işlemleri çalıştırır
+type ApprovalService struct {
+	approvals repository.ApprovalRepository
+	policy    models.ApprovalPolicy
+}
+
+func NewApprovalService(approvals repository.ApprovalRepository, policy models.ApprovalPolicy) *ApprovalService {
+	return &ApprovalService{approvals: approvals, policy: policy}
+}
+
+// Requires kontrol eder verilen işlemin onay gerektirip gerektirmediğini
+func (s *ApprovalService) Requires(action models.ApprovalAction) bool {
+	return s.policy.Requires(action)
+}
+
+// BulkDeleteThreshold toplu silmelerin onay gerektirdiği kullanıcı sayısını döner, sıfır ise eşik kapalıdır
+func (s *ApprovalService) BulkDeleteThreshold() int {
+	return s.policy.BulkDeleteThreshold
+}
+
+// RequiresBulkDelete kontrol eder count kullanıcıyı silen toplu silmenin onay gerektirip gerektirmediğini
+func (s *ApprovalService) RequiresBulkDelete(count int64) bool {
+	return s.policy.RequiresBulkDelete(count)
+}
+
+// Request tek bir hedefi etkileyen işlemi ertelemek için bir onay isteği oluşturur. Aynı hedef için bekleyen bir
+// istek varsa *ApprovalPendingError döner.
+func (s *ApprovalService) Request(ctx context.Context, requester *models.Admin, action models.ApprovalAction, targetType string, targetID uint, payload map[string]interface{}) (*models.ApprovalRequest, error) {
+	return s.create(ctx, requester, &models.ApprovalRequest{
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+	}, payload)
+}
+
+// RequestSet birden fazla hedefi etkileyen işlemi (örneğin toplu silme) ertelemek için bir onay isteği oluşturur.
+// Hedef ID'leri payload'a ids ve count olarak eklenir. Sadece aynı hedef kümesi için bekleyen bir istek varsa
+// *ApprovalPendingError döner; farklı kümeleri seçen istekler aynı anda bekleyebilir.
+func (s *ApprovalService) RequestSet(ctx context.Context, requester *models.Admin, action models.ApprovalAction, targetType string, ids []uint, payload map[string]interface{}) (*models.ApprovalRequest, error) {
+	withIDs := map[string]interface{}{"ids": ids, "count": len(ids)}
+	for key, value := range payload {
+		withIDs[key] = value
+	}
+	return s.create(ctx, requester, &models.ApprovalRequest{
+		Action:     action,
+		TargetType: targetType,
+		TargetKey:  models.ApprovalTargetSetKey(ids),
+	}, withIDs)
+}
+
+func (s *ApprovalService) create(ctx context.Context, requester *models.Admin, request *models.ApprovalRequest, payload map[string]interface{}) (*models.ApprovalRequest, error) {
+	request.Status = models.ApprovalStatusPending
+	request.RequestedBy = &requester.ID
+	request.ExpiresAt = utils.Now().Add(s.policy.TTL)
+	if len(payload) > 0 {
+		encoded, err := json.Marshal(payload)
+		if err != nil {
+			return nil, err
+		}
+		value := string(encoded)
+		request.Payload = &value
+	}
+
+	err := s.approvals.CreatePending(ctx, request)
+	if errors.Is(err, models.ErrApprovalPending) {
+		// Başka bir admin'in isteğinin payload'ı (örneğin seçilen kullanıcılar) gösterilmez
+		pending := &ApprovalPendingError{ID: request.ID, Status: request.Status}
+		if request.IsRequestedBy(requester.ID) {
+			pending.Request = request
+		}
+		return nil, pending
+	}
+	if err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+// List onay isteklerini listeler, status boş değilse sadece o durumdakiler döner (Sadece super admin yapabilir)
+func (s *ApprovalService) List(ctx context.Context, actor *models.Admin, status models.ApprovalStatus) ([]models.ApprovalRequest, error) {
+	if !actor.CanReviewApprovals() {
+		return nil, forbidden("Super admin permission required")
+	}
+	return s.approvals.List(ctx, status)
+}
+
+// Get tek bir onay isteğini döner (Super admin veya isteği oluşturan admin görebilir)
+func (s *ApprovalService) Get(ctx context.Context, actor *models.Admin, id uint) (*models.ApprovalRequest, error) {
+	request, err := s.approvals.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if !actor.CanReviewApprovals() && !request.IsRequestedBy(actor.ID) {
+		return nil, forbidden("Super admin permission required")
+	}
+	return request, nil
+}
+
+// Approve isteği onaylar ve ertelenmiş işlemi çalıştırır. İsteği oluşturan admin kendi isteğini onaylayamaz.
+// İşlem çalıştırılamazsa istek failed olarak işaretlenir ve güncel istekle birlikte *ApprovalExecutionError döner.
+func (s *ApprovalService) Approve(ctx context.Context, reviewer *models.Admin, id uint, reason string) (*models.ApprovalRequest, error) {
+	if !reviewer.CanReviewApprovals() {
+		return nil, forbidden("Super admin permission required")
+	}
+
+	var execErr error
+	request, err := s.review(ctx, reviewer, id, func(request *models.ApprovalRequest, execute func(repository.ApprovalExecutor) error) map[string]interface{} {
+		execErr = execute(approvalExecutor(*request))
+
+		fields := reviewFields(reviewer, models.ApprovalStatusApproved, reason)
+		fields["executed_at"] = fields["reviewed_at"]
+		if execErr != nil {
+			fields["status"] = models.ApprovalStatusFailed
+			fields["executed_at"] = nil
+			fields["error"] = execErr.Error()
+		}
+		return fields
+	})
+	if err != nil {
+		return nil, err
+	}
+	if execErr != nil {
+		return request, &ApprovalExecutionError{Err: execErr}
+	}
+	return request, nil
+}
+
+// Reject isteği reddeder, ertelenmiş işlem çalıştırılmaz
+func (s *ApprovalService) Reject(ctx context.Context, reviewer *models.Admin, id uint, reason string) (*models.ApprovalRequest, error) {
+	if !reviewer.CanReviewApprovals() {
+		return nil, forbidden("Super admin permission required")
+	}
+
+	return s.review(ctx, reviewer, id, func(*models.ApprovalRequest, func(repository.ApprovalExecutor) error) map[string]interface{} {
+		return reviewFields(reviewer, models.ApprovalStatusRejected, reason)
+	})
+}
+
+// Expire bekleyen bir isteği süresini beklemeden geçersiz kılar (Super admin veya isteği oluşturan admin yapabilir)
+func (s *ApprovalService) Expire(ctx context.Context, actor *models.Admin, id uint) (*models.ApprovalRequest, error) {
+	return s.approvals.Resolve(ctx, id, func(request *models.ApprovalRequest, _ func(repository.ApprovalExecutor) error) (map[string]interface{}, error) {
+		if !actor.CanReviewApprovals() && !request.IsRequestedBy(actor.ID) {
+			return nil, forbidden("Super admin permission required")
+		}
+		if request.Status != models.ApprovalStatusPending {
+			return nil, ErrApprovalNotPending
+		}
+		return map[string]interface{}{"status": models.ApprovalStatusExpired}, nil
+	})
+}
+
+// review isteği kilitleyip incelenebilir olduğunu doğrular ve decide'ın döndüğü kolonları uygular.
+// Süresi dolmuş istek, inceleme geri alındıktan sonra ayrıca expired olarak işaretlenir.
+func (s *ApprovalService) review(ctx context.Context, reviewer *models.Admin, id uint, decide func(request *models.ApprovalRequest, execute func(repository.ApprovalExecutor) error) map[string]interface{}) (*models.ApprovalRequest, error) {
+	request, err := s.approvals.Resolve(ctx, id, func(request *models.ApprovalRequest, execute func(repository.ApprovalExecutor) error) (map[string]interface{}, error) {
+		if request.Status != models.ApprovalStatusPending {
+			return nil, ErrApprovalNotPending
+		}
+		if !request.IsPending() {
+			return nil, ErrApprovalExpired
+		}
+		if request.IsRequestedBy(reviewer.ID) {
+			return nil, ErrSelfApproval
+		}
+		return decide(request, execute), nil
+	})
+	if errors.Is(err, ErrApprovalExpired) {
+		if err := s.approvals.MarkExpired(ctx, id); err != nil {
+			slog.ErrorContext(ctx, "Error expiring approval request", "approval_request_id", id, "error", err)
+		}
+	}
+	return request, err
+}
+
+func reviewFields(reviewer *models.Admin, status models.ApprovalStatus, reason string) map[string]interface{} {
+	fields := map[string]interface{}{
+		"reviewed_by": reviewer.ID,
+		"reviewed_at": utils.Now(),
+		"status":      status,
+	}
+	if reason != "" {
+		fields["review_reason"] = reason
+	}
+	return fields
+}
+
+// approvalExecutor onaylanan işlemi çalıştıran fonksiyonu döner. Hedefin durumu istek oluşturulduktan sonra
+// değişmiş olabileceği için işlem öncesi kontroller burada tekrar yapılır.
+func approvalExecutor(request models.ApprovalRequest) repository.ApprovalExecutor {
+	return func(ctx context.Context, admins repository.AdminRepository, users repository.UserRepository) error {
+		switch request.Action {
+		case models.ApprovalActionAdminDelete:
+			target, err := findApprovalTarget(ctx, admins, request.TargetID)
+			if err != nil {
+				return err
+			}
+			if first, err := IsFirstSuperAdmin(ctx, admins, target); err != nil {
+				return err
+			} else if first {
+				return errors.New("cannot delete first super admin")
+			}
+			return admins.Delete(ctx, target)
+
+		case models.ApprovalActionAdminPurge:
+			target, err := admins.FindDeleted(ctx, request.TargetID)
+			if errors.Is(err, models.ErrAdminNotFound) {
+				return fmt.Errorf("deleted admin %d not found", request.TargetID)
+			}
+			if err != nil {
+				return err
+			}
+			return admins.Purge(ctx, target)
+
+		case models.ApprovalActionAdminRoleChange:
+			var payload struct {
+				Role models.AdminRole `json:"role"`
+			}
+			if request.Payload == nil || json.Unmarshal([]byte(*request.Payload), &payload) != nil || !payload.Role.ValidateRole() {
+				return errors.New("invalid role change payload")
+			}
+			target, err := findApprovalTarget(ctx, admins, request.TargetID)
+			if err != nil {
+				return err
+			}
+			if first, err := IsFirstSuperAdmin(ctx, admins, target); err != nil {
+				return err
+			} else if first {
+				return errors.New("first super admin role cannot be changed")
+			}
+			return admins.Update(ctx, target, map[string]interface{}{"role": payload.Role})
+
+		case models.ApprovalActionUserBulkDelete:
+			var payload struct {
+				IDs []uint `json:"ids"`
+			}
+			if request.Payload == nil || json.Unmarshal([]byte(*request.Payload), &payload) != nil || len(payload.IDs) == 0 {
+				return errors.New("invalid bulk delete payload")
+			}
+			// İstek oluşturulduktan sonra silinmiş kullanıcılar atlanır
+			for start := 0; start < len(payload.IDs); start += bulkDeleteBatchSize {
+				batch := payload.IDs[start:min(start+bulkDeleteBatchSize, len(payload.IDs))]
+				if _, err := users.DeleteByIDs(ctx, batch); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+
+		return fmt.Errorf("unsupported approval action %q", request.Action)
+	}
+}
+
+func findApprovalTarget(ctx context.Context, admins repository.AdminRepository, id uint) (*models.Admin, error) {
+	target, err := admins.FindByID(ctx, id)
+	if errors.Is(err, models.ErrAdminNotFound) {
+		return nil, fmt.Errorf("admin %d not found", id)
+	}
+	return target, err
+}
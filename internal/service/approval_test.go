@@ -0,0 +1,78 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"prototurk/internal/models"
+	"prototurk/internal/repository"
+	"prototurk/internal/service"
+)
+
+func newApprovalService() *service.ApprovalService {
+	admins := repository.NewMemoryAdminRepository()
+	approvals := repository.NewMemoryApprovalRepository(admins, repository.NewMemoryUserRepository())
+	return service.NewApprovalService(approvals, models.ApprovalPolicy{TTL: time.Hour, BulkDeleteThreshold: 2})
+}
+
+func newAdmin(id uint) *models.Admin {
+	admin := &models.Admin{Role: models.AdminRoleAdmin}
+	admin.ID = id
+	return admin
+}
+
+// Farklı kullanıcı kümelerini silen toplu silmeler birbirini engellememeli
+func TestRequestSetAllowsDifferentBulkDeletes(t *testing.T) {
+	ctx := context.Background()
+	approvals := newApprovalService()
+	requester := newAdmin(1)
+
+	first, err := approvals.RequestSet(ctx, requester, models.ApprovalActionUserBulkDelete, "user", []uint{1, 2, 3}, nil)
+	if err != nil {
+		t.Fatalf("RequestSet first: %v", err)
+	}
+	second, err := approvals.RequestSet(ctx, requester, models.ApprovalActionUserBulkDelete, "user", []uint{4, 5, 6}, nil)
+	if err != nil {
+		t.Fatalf("RequestSet second: %v", err)
+	}
+	if first.ID == second.ID || first.Status != models.ApprovalStatusPending || second.Status != models.ApprovalStatusPending {
+		t.Fatalf("requests = %+v and %+v, want two pending requests", first, second)
+	}
+
+	// Aynı küme (farklı sırada da olsa) tekrar istenemez
+	_, err = approvals.RequestSet(ctx, requester, models.ApprovalActionUserBulkDelete, "user", []uint{3, 1, 2}, nil)
+	var pending *service.ApprovalPendingError
+	if !errors.As(err, &pending) {
+		t.Fatalf("RequestSet same set error = %v, want *ApprovalPendingError", err)
+	}
+	if pending.ID != first.ID || pending.Request == nil || pending.Request.ID != first.ID {
+		t.Fatalf("pending error = %+v, want own request %d with details", pending, first.ID)
+	}
+}
+
+// Başka bir admin'in bekleyen isteğinin içeriği (seçilen kullanıcılar, filtre) gösterilmemeli
+func TestRequestSetHidesOtherAdminsPendingRequest(t *testing.T) {
+	ctx := context.Background()
+	approvals := newApprovalService()
+	owner := newAdmin(1)
+	other := newAdmin(2)
+
+	request, err := approvals.RequestSet(ctx, owner, models.ApprovalActionUserBulkDelete, "user", []uint{1, 2, 3}, map[string]interface{}{"filter": "banned"})
+	if err != nil {
+		t.Fatalf("RequestSet: %v", err)
+	}
+
+	_, err = approvals.RequestSet(ctx, other, models.ApprovalActionUserBulkDelete, "user", []uint{1, 2, 3}, nil)
+	var pending *service.ApprovalPendingError
+	if !errors.As(err, &pending) || !errors.Is(err, models.ErrApprovalPending) {
+		t.Fatalf("RequestSet error = %v, want *ApprovalPendingError", err)
+	}
+	if pending.ID != request.ID || pending.Status != models.ApprovalStatusPending {
+		t.Fatalf("pending error = %+v, want id %d and pending status", pending, request.ID)
+	}
+	if pending.Request != nil {
+		t.Fatalf("pending error exposes another admin's request: %+v", pending.Request)
+	}
+}
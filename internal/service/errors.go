@@ -0,0 +1,78 @@
+package service
+
+import (
+	"errors"
+
+	"prototurk/internal/models"
+)
+
+var (
+	ErrInvalidCredentials   = errors.New("invalid credentials")
+	ErrUserBanned           = errors.New("user is banned")
+	ErrTwoFactorRequired    = errors.New("two-factor code required")
+	ErrInvalidTwoFactorCode = errors.New("invalid two-factor code")
+	ErrReauthRequired       = errors.New("recent re-authentication required")
+
+	ErrApprovalNotPending = errors.New("approval request is not pending")
+	ErrApprovalExpired    = errors.New("approval request has expired")
+	ErrSelfApproval       = errors.New("approval request cannot be reviewed by its requester")
+
+	ErrNetworkLockout = errors.New("change would lock out the current admin")
+)
+
+// PermissionError bir yetki kuralının ihlal edildiğini belirtir. Mesaj istemciye olduğu gibi döner.
+type PermissionError struct {
+	Message string
+}
+
+func (e *PermissionError) Error() string {
+	return e.Message
+}
+
+func forbidden(message string) error {
+	return &PermissionError{Message: message}
+}
+
+// EmailInUseError email'in silinmemiş başka bir admin tarafından kullanıldığını belirtir.
+// errors.Is ile models.ErrEmailExists'e eşleşir.
+type EmailInUseError struct {
+	AdminID uint
+}
+
+func (e *EmailInUseError) Error() string {
+	return models.ErrEmailExists.Error()
+}
+
+func (e *EmailInUseError) Unwrap() error {
+	return models.ErrEmailExists
+}
+
+// ApprovalPendingError aynı işlem ve hedef için bekleyen bir onay isteği olduğunu belirtir. Request sadece bekleyen
+// istek aynı admin'e aitse doldurulur; başka bir admin'in isteğinin sadece ID'si ve durumu gösterilir.
+// errors.Is ile models.ErrApprovalPending'e eşleşir.
+type ApprovalPendingError struct {
+	ID      uint
+	Status  models.ApprovalStatus
+	Request *models.ApprovalRequest
+}
+
+func (e *ApprovalPendingError) Error() string {
+	return models.ErrApprovalPending.Error()
+}
+
+func (e *ApprovalPendingError) Unwrap() error {
+	return models.ErrApprovalPending
+}
+
+// ApprovalExecutionError onaylanan işlemin çalıştırılamadığını belirtir; istek failed olarak işaretlenmiştir
+type ApprovalExecutionError struct {
+	Err error
+}
+
+func (e *ApprovalExecutionError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *ApprovalExecutionError) Unwrap() error {
+	return e.Err
+}
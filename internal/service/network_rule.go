@@ -0,0 +1,100 @@
+package service
+
+import (
+	"context"
+	"net"
+
+	"prototurk/internal/models"
+	"prototurk/internal/repository"
+)
+
+// NetworkRuleService admin API'sinin ağ kurallarını yönetir. Kuralı değiştiren admin'in kendi erişimini kesecek
+// değişiklikler uygulanmaz.
+type NetworkRuleService struct {
+	rules           repository.NetworkRuleRepository
+	admins          repository.AdminRepository
+	allowedNetworks []*net.IPNet
+	bypass          bool
+}
+
+// NewNetworkRuleService ortam değişkeninden gelen allowedNetworks ile global kuralları birlikte değerlendiren bir
+// NetworkRuleService oluşturur. bypass açıkken politika uygulanmadığı için kilitlenme kontrolü yapılmaz.
+func NewNetworkRuleService(rules repository.NetworkRuleRepository, admins repository.AdminRepository, allowedNetworks []*net.IPNet, bypass bool) *NetworkRuleService {
+	return &NetworkRuleService{rules: rules, admins: admins, allowedNetworks: allowedNetworks, bypass: bypass}
+}
+
+// List ağ kurallarını listeler; adminID verilirse sadece o admin'in kuralları döner (Sadece super admin yapabilir)
+func (s *NetworkRuleService) List(ctx context.Context, actor *models.Admin, adminID *uint) ([]models.AdminNetworkRule, error) {
+	if !actor.CanManageNetworkRules() {
+		return nil, forbidden("Super admin permission required")
+	}
+	return s.rules.List(ctx, adminID)
+}
+
+// Create yeni bir global veya admin bazlı ağ kuralı ekler (Sadece super admin yapabilir). Kural actor'ün clientIP'den
+// erişimini keserse ErrNetworkLockout döner.
+func (s *NetworkRuleService) Create(ctx context.Context, actor *models.Admin, req models.CreateAdminNetworkRuleRequest, clientIP string) (*models.AdminNetworkRule, error) {
+	if !actor.CanManageNetworkRules() {
+		return nil, forbidden("Super admin permission required")
+	}
+
+	network, err := models.ParseCIDR(req.CIDR)
+	if err != nil {
+		return nil, models.ErrInvalidCIDR
+	}
+
+	if req.AdminID != nil {
+		if _, err := s.admins.FindByID(ctx, *req.AdminID); err != nil {
+			return nil, err
+		}
+	}
+
+	rule := &models.AdminNetworkRule{
+		AdminID:     req.AdminID,
+		CIDR:        network.String(),
+		Description: req.Description,
+		CreatedBy:   &actor.ID,
+	}
+	if err := s.rules.Create(ctx, rule, s.verifyAccess(actor.ID, clientIP)); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// Delete bir ağ kuralını siler ve silinen kuralı döner (Sadece super admin yapabilir). Silme actor'ün clientIP'den
+// erişimini keserse ErrNetworkLockout döner.
+func (s *NetworkRuleService) Delete(ctx context.Context, actor *models.Admin, id uint, clientIP string) (*models.AdminNetworkRule, error) {
+	if !actor.CanManageNetworkRules() {
+		return nil, forbidden("Super admin permission required")
+	}
+
+	rule, err := s.rules.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.rules.Delete(ctx, rule, s.verifyAccess(actor.ID, clientIP)); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// verifyAccess değişiklikten sonraki kurallarla adminID'nin clientIP'den erişip erişemeyeceğini kontrol eder
+func (s *NetworkRuleService) verifyAccess(adminID uint, clientIP string) repository.NetworkRuleVerifier {
+	return func(rules []models.AdminNetworkRule) error {
+		if s.bypass {
+			return nil
+		}
+
+		// Başka adminlere ait kurallar bu admin'in erişimini etkilemez
+		applicable := make([]models.AdminNetworkRule, 0, len(rules))
+		for _, rule := range rules {
+			if rule.AdminID == nil || *rule.AdminID == adminID {
+				applicable = append(applicable, rule)
+			}
+		}
+		if !models.NetworkAllowed(net.ParseIP(clientIP), s.allowedNetworks, applicable) {
+			return ErrNetworkLockout
+		}
+		return nil
+	}
+}
@@ -0,0 +1,86 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"prototurk/internal/models"
+	"prototurk/internal/repository"
+	"prototurk/internal/service"
+)
+
+func newSuperAdmin(t *testing.T, admins repository.AdminRepository, email string) *models.Admin {
+	t.Helper()
+	admin := &models.Admin{Email: email, Name: "Admin", Password: "hash", Role: models.AdminRoleSuperAdmin, Status: models.AdminStatusActive}
+	if err := admins.Create(context.Background(), admin); err != nil {
+		t.Fatalf("Create admin: %v", err)
+	}
+	return admin
+}
+
+// Admin'in kendi IP'sini dışarıda bırakan kural eklenmemeli ve silinen kural erişimi kesmemeli
+func TestNetworkRuleServicePreventsLockout(t *testing.T) {
+	ctx := context.Background()
+	rules := repository.NewMemoryNetworkRuleRepository()
+	admins := repository.NewMemoryAdminRepository()
+	rulesService := service.NewNetworkRuleService(rules, admins, nil, false)
+	actor := newSuperAdmin(t, admins, "actor@example.com")
+	other := newSuperAdmin(t, admins, "other@example.com")
+
+	_, err := rulesService.Create(ctx, actor, models.CreateAdminNetworkRuleRequest{CIDR: "10.0.0.0/8"}, "192.168.1.10")
+	if !errors.Is(err, service.ErrNetworkLockout) {
+		t.Fatalf("Create global rule excluding actor error = %v, want ErrNetworkLockout", err)
+	}
+	if list, _ := rules.List(ctx, nil); len(list) != 0 {
+		t.Fatalf("rules after prevented lockout = %+v, want none", list)
+	}
+
+	// Başka bir admin'e ait kural isteği yapanın erişimini etkilemez
+	if _, err := rulesService.Create(ctx, actor, models.CreateAdminNetworkRuleRequest{CIDR: "10.0.0.0/8", AdminID: &other.ID}, "192.168.1.10"); err != nil {
+		t.Fatalf("Create rule for other admin: %v", err)
+	}
+
+	// İki global kuraldan biri silinince erişim kesilecekse silme engellenir
+	office, err := rulesService.Create(ctx, actor, models.CreateAdminNetworkRuleRequest{CIDR: "192.168.1.0/24"}, "192.168.1.10")
+	if err != nil {
+		t.Fatalf("Create office rule: %v", err)
+	}
+	if _, err := rulesService.Create(ctx, actor, models.CreateAdminNetworkRuleRequest{CIDR: "172.16.0.1"}, "192.168.1.10"); err != nil {
+		t.Fatalf("Create second global rule: %v", err)
+	}
+	if _, err := rulesService.Delete(ctx, actor, office.ID, "192.168.1.10"); !errors.Is(err, service.ErrNetworkLockout) {
+		t.Fatalf("Delete office rule error = %v, want ErrNetworkLockout", err)
+	}
+	if _, err := rules.FindByID(ctx, office.ID); err != nil {
+		t.Fatalf("office rule after prevented delete: %v", err)
+	}
+
+	// Bypass açıkken politika uygulanmadığı için kilitlenme kontrolü yapılmaz
+	bypassed := service.NewNetworkRuleService(rules, admins, nil, true)
+	if _, err := bypassed.Delete(ctx, actor, office.ID, "192.168.1.10"); err != nil {
+		t.Fatalf("Delete with bypass: %v", err)
+	}
+}
+
+func TestNetworkRuleServiceValidation(t *testing.T) {
+	ctx := context.Background()
+	admins := repository.NewMemoryAdminRepository()
+	rulesService := service.NewNetworkRuleService(repository.NewMemoryNetworkRuleRepository(), admins, nil, false)
+	actor := newSuperAdmin(t, admins, "actor@example.com")
+
+	var permissionErr *service.PermissionError
+	if _, err := rulesService.List(ctx, newAdmin(99), nil); !errors.As(err, &permissionErr) {
+		t.Fatalf("List as admin error = %v, want PermissionError", err)
+	}
+	if _, err := rulesService.Create(ctx, actor, models.CreateAdminNetworkRuleRequest{CIDR: "not-a-network"}, "127.0.0.1"); !errors.Is(err, models.ErrInvalidCIDR) {
+		t.Fatalf("Create invalid CIDR error = %v, want ErrInvalidCIDR", err)
+	}
+	missing := uint(999)
+	if _, err := rulesService.Create(ctx, actor, models.CreateAdminNetworkRuleRequest{CIDR: "127.0.0.1", AdminID: &missing}, "127.0.0.1"); !errors.Is(err, models.ErrAdminNotFound) {
+		t.Fatalf("Create for missing admin error = %v, want ErrAdminNotFound", err)
+	}
+	if _, err := rulesService.Delete(ctx, actor, 999, "127.0.0.1"); !errors.Is(err, models.ErrNetworkRuleNotFound) {
+		t.Fatalf("Delete missing rule error = %v, want ErrNetworkRuleNotFound", err)
+	}
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"prototurk/internal/models"
+	"prototurk/internal/repository"
+	"prototurk/pkg/utils"
+)
+
+// StatsService admin paneli için özet istatistikleri üretir.
+// Sonuçlar aynı parametreler için kısa bir süre bellekte tutulur.
+type StatsService struct {
+	stats    repository.StatsRepository
+	cacheTTL time.Duration
+
+	mu    sync.Mutex
+	cache map[string]cachedStats
+}
+
+type cachedStats struct {
+	stats     *models.AdminStats
+	expiresAt time.Time
+}
+
+func NewStatsService(stats repository.StatsRepository, cacheTTL time.Duration) *StatsService {
+	return &StatsService{stats: stats, cacheTTL: cacheTTL, cache: make(map[string]cachedStats)}
+}
+
+// Get son days günün interval bazında serisini ve windows için büyüme karşılaştırmalarını döner (stats yetkisi
+// gerektirir). Cache'te geçerli bir sonuç varsa o döner.
+func (s *StatsService) Get(ctx context.Context, actor *models.Admin, interval string, days int, windows []int) (*models.AdminStats, error) {
+	if !actor.HasPermission(models.AdminPermissionViewStats) {
+		return nil, forbidden("Stats permission required")
+	}
+
+	key := fmt.Sprintf("%s:%d:%v", interval, days, windows)
+	now := utils.Now()
+
+	s.mu.Lock()
+	cached, ok := s.cache[key]
+	s.mu.Unlock()
+	if ok && now.Before(cached.expiresAt) {
+		return cached.stats, nil
+	}
+
+	stats, err := s.calculate(ctx, interval, days, windows, now)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	// Süresi dolmuş kayıtları temizle ki farklı parametrelerle cache şişmesin
+	for k, v := range s.cache {
+		if now.After(v.expiresAt) {
+			delete(s.cache, k)
+		}
+	}
+	s.cache[key] = cachedStats{stats: stats, expiresAt: now.Add(s.cacheTTL)}
+	s.mu.Unlock()
+
+	return stats, nil
+}
+
+func (s *StatsService) calculate(ctx context.Context, interval string, days int, windows []int, now time.Time) (*models.AdminStats, error) {
+	from := truncateUTC(now.AddDate(0, 0, -days+1), interval)
+
+	stats := &models.AdminStats{
+		Interval:    interval,
+		From:        from,
+		To:          now,
+		GeneratedAt: now,
+	}
+
+	registrations, err := s.stats.CountRegistrationsByBucket(ctx, interval, from)
+	if err != nil {
+		return nil, err
+	}
+	logins, err := s.stats.CountLoginsByBucket(ctx, interval, from)
+	if err != nil {
+		return nil, err
+	}
+
+	// Boş aralıklar da seride yer alsın diye bucket'lar Go tarafında doldurulur
+	for bucket := from; !bucket.After(now); bucket = nextBucket(bucket, interval) {
+		stats.Series = append(stats.Series, models.StatsPoint{
+			Bucket:        bucket,
+			Registrations: registrations[bucket.Unix()],
+			Logins:        logins[bucket.Unix()],
+		})
+	}
+
+	if stats.UsersByStatus, err = s.stats.CountUsersByStatus(ctx); err != nil {
+		return nil, err
+	}
+	for _, count := range stats.UsersByStatus {
+		stats.TotalUsers += count
+	}
+
+	if stats.AdminsByRole, err = s.stats.CountActiveAdminsByRole(ctx); err != nil {
+		return nil, err
+	}
+
+	for _, window := range windows {
+		growth, err := s.growth(ctx, window, now)
+		if err != nil {
+			return nil, err
+		}
+		stats.Growth = append(stats.Growth, growth)
+	}
+
+	return stats, nil
+}
+
+// growth son window günündeki kayıtları bir önceki window günü ile karşılaştırır
+func (s *StatsService) growth(ctx context.Context, window int, now time.Time) (models.StatsGrowth, error) {
+	current := now.AddDate(0, 0, -window)
+	previous := current.AddDate(0, 0, -window)
+
+	previousCount, currentCount, err := s.stats.CountRegistrationsAround(ctx, previous, current)
+	if err != nil {
+		return models.StatsGrowth{}, err
+	}
+
+	growth := models.StatsGrowth{
+		WindowDays: window,
+		Current:    currentCount,
+		Previous:   previousCount,
+		Delta:      currentCount - previousCount,
+	}
+	if previousCount > 0 {
+		percent := float64(growth.Delta) / float64(previousCount) * 100
+		growth.DeltaPercent = &percent
+	}
+	return growth, nil
+}
+
+// truncateUTC zamanı Postgres date_trunc ile aynı şekilde gün veya haftanın (pazartesi) başına yuvarlar
+func truncateUTC(t time.Time, interval string) time.Time {
+	t = utils.ParseTime(t)
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	if interval == models.StatsIntervalWeek {
+		offset := (int(day.Weekday()) + 6) % 7
+		day = day.AddDate(0, 0, -offset)
+	}
+	return day
+}
+
+func nextBucket(t time.Time, interval string) time.Time {
+	if interval == models.StatsIntervalWeek {
+		return t.AddDate(0, 0, 7)
+	}
+	return t.AddDate(0, 0, 1)
+}
@@ -0,0 +1,85 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"prototurk/internal/models"
+	"prototurk/internal/service"
+)
+
+// fakeStatsRepository sabit sayımlar döner ve çağrı sayısını tutar
+type fakeStatsRepository struct {
+	calls         int
+	registrations map[int64]int64
+}
+
+func (r *fakeStatsRepository) CountRegistrationsByBucket(ctx context.Context, interval string, from time.Time) (map[int64]int64, error) {
+	r.calls++
+	return r.registrations, nil
+}
+
+func (r *fakeStatsRepository) CountLoginsByBucket(ctx context.Context, interval string, from time.Time) (map[int64]int64, error) {
+	return nil, nil
+}
+
+func (r *fakeStatsRepository) CountUsersByStatus(ctx context.Context) (map[models.UserStatus]int64, error) {
+	return map[models.UserStatus]int64{models.UserStatusActive: 3, models.UserStatusBanned: 1}, nil
+}
+
+func (r *fakeStatsRepository) CountActiveAdminsByRole(ctx context.Context) (map[models.AdminRole]int64, error) {
+	return map[models.AdminRole]int64{models.AdminRoleAdmin: 2}, nil
+}
+
+func (r *fakeStatsRepository) CountRegistrationsAround(ctx context.Context, previous, current time.Time) (int64, int64, error) {
+	return 4, 6, nil
+}
+
+// Boş günler de seride sıfır olarak yer almalı ve aynı parametreler cache'ten dönmeli
+func TestStatsServiceGet(t *testing.T) {
+	ctx := context.Background()
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	repo := &fakeStatsRepository{registrations: map[int64]int64{today.Unix(): 5}}
+	stats := service.NewStatsService(repo, time.Minute)
+
+	result, err := stats.Get(ctx, newAdmin(1), models.StatsIntervalDay, 7, []int{7})
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if len(result.Series) != 7 {
+		t.Fatalf("len(Series) = %d, want 7", len(result.Series))
+	}
+	last := result.Series[len(result.Series)-1]
+	if !last.Bucket.Equal(today) || last.Registrations != 5 || result.Series[0].Registrations != 0 {
+		t.Fatalf("Series = %+v, want 5 registrations only today", result.Series)
+	}
+	if result.TotalUsers != 4 {
+		t.Fatalf("TotalUsers = %d, want 4", result.TotalUsers)
+	}
+	growth := result.Growth[0]
+	if growth.Current != 6 || growth.Previous != 4 || growth.Delta != 2 || growth.DeltaPercent == nil || *growth.DeltaPercent != 50 {
+		t.Fatalf("Growth = %+v", growth)
+	}
+
+	if _, err := stats.Get(ctx, newAdmin(1), models.StatsIntervalDay, 7, []int{7}); err != nil {
+		t.Fatalf("Get cached: %v", err)
+	}
+	if repo.calls != 1 {
+		t.Fatalf("repository called %d times, want 1 (second call cached)", repo.calls)
+	}
+}
+
+func TestStatsServiceWeekBucketsStartOnMonday(t *testing.T) {
+	stats := service.NewStatsService(&fakeStatsRepository{}, time.Minute)
+
+	result, err := stats.Get(context.Background(), newAdmin(1), models.StatsIntervalWeek, 30, []int{7})
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	for _, point := range result.Series {
+		if point.Bucket.Weekday() != time.Monday || point.Bucket.Hour() != 0 {
+			t.Fatalf("bucket %s does not start on Monday 00:00 UTC", point.Bucket)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"prototurk/internal/models"
+	"prototurk/internal/repository"
+	"prototurk/pkg/utils"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// UserService kullanıcı kaydı, girişi ve profil işlemlerinin kurallarını uygular
+type UserService struct {
+	users repository.UserRepository
+}
+
+func NewUserService(users repository.UserRepository) *UserService {
+	return &UserService{users: users}
+}
+
+// Register yeni bir aktif kullanıcı oluşturur
+func (s *UserService) Register(ctx context.Context, req models.RegisterRequest) (*models.User, error) {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+
+	user := &models.User{
+		Username: req.Username,
+		Email:    req.Email,
+		Password: string(hashedPassword),
+		Status:   models.UserStatusActive,
+	}
+	if err := s.users.Create(ctx, user); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// Authenticate kullanıcı adı veya email ve parola ile girişi doğrular, başarılı girişte son giriş tarihini günceller
+func (s *UserService) Authenticate(ctx context.Context, identifier string, password string) (*models.User, error) {
+	user, err := s.users.FindByIdentifier(ctx, identifier)
+	if errors.Is(err, models.ErrUserNotFound) {
+		return nil, ErrInvalidCredentials
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(password)); err != nil {
+		return nil, ErrInvalidCredentials
+	}
+	// Hesap durumu sadece parolayı bilen birine gösterilir
+	if user.Status == models.UserStatusBanned {
+		return nil, ErrUserBanned
+	}
+
+	if err := s.users.Update(ctx, user, map[string]interface{}{"last_login_date": utils.Now()}); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+func (s *UserService) Get(ctx context.Context, id uint) (*models.User, error) {
+	return s.users.FindByID(ctx, id)
+}
+
+// UpdateProfile kullanıcı adı ve email'den verilenleri, başka bir kullanıcıda kullanılmıyorsa günceller
+func (s *UserService) UpdateProfile(ctx context.Context, id uint, req models.UpdateProfileRequest) (*models.User, error) {
+	user, err := s.users.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := make(map[string]interface{})
+	if req.Username != "" && req.Username != user.Username {
+		taken, err := s.users.UsernameExists(ctx, req.Username)
+		if err != nil {
+			return nil, err
+		}
+		if taken {
+			return nil, models.ErrUsernameExists
+		}
+		updates["username"] = req.Username
+	}
+	if req.Email != "" && req.Email != user.Email {
+		taken, err := s.users.EmailExists(ctx, req.Email)
+		if err != nil {
+			return nil, err
+		}
+		if taken {
+			return nil, models.ErrEmailExists
+		}
+		updates["email"] = req.Email
+	}
+
+	if len(updates) > 0 {
+		if err := s.users.Update(ctx, user, updates); err != nil {
+			return nil, err
+		}
+	}
+	return user, nil
+}
+
+// UpdatePassword mevcut parolayı doğrulayıp yeni parolayı kaydeder
+func (s *UserService) UpdatePassword(ctx context.Context, id uint, currentPassword string, newPassword string) error {
+	user, err := s.users.FindByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(currentPassword)); err != nil {
+		return models.ErrInvalidPassword
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	return s.users.Update(ctx, user, map[string]interface{}{"password": string(hashedPassword)})
+}
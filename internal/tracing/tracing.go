@@ -0,0 +1,137 @@
+// Package tracing OpenTelemetry tracing'i kurar: W3C trace-context propagation, span'lerin OTLP,
+// stdout veya dosyaya aktarılması ve GORM sorguları için span üreten plugin
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+const (
+	ExporterNone   = "none"
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+	ExporterFile   = "file"
+)
+
+// ScopeName uygulamanın ürettiği span'lerin instrumentation scope adıdır
+const ScopeName = "prototurk"
+
+type Config struct {
+	// Exporter none, otlp, stdout veya file. none iken span kaydedilmez ama gelen trace-context
+	// korunur; hata yanıtlarında ve loglarda çağıranın trace ID'si yer alır.
+	Exporter string `yaml:"exporter"`
+	// Endpoint OTLP/HTTP collector adresidir (örneğin http://localhost:4318). Boşsa
+	// OTEL_EXPORTER_OTLP_ENDPOINT veya varsayılan http://localhost:4318 kullanılır.
+	Endpoint string `yaml:"endpoint"`
+	// File exporter file iken span'lerin JSON olarak eklendiği dosyadır
+	File        string `yaml:"file"`
+	ServiceName string `yaml:"service_name"`
+	// SampleRatio üst span'i olmayan isteklerin kaydedilme oranıdır (0-1). Çağıranın örnekleme kararına uyulur.
+	SampleRatio float64 `yaml:"sample_ratio"`
+}
+
+// Validate exporter'ın bilinen bir değer olduğunu ve gerekli ayarların verildiğini kontrol eder
+func (c Config) Validate() error {
+	var errs []error
+	switch strings.ToLower(c.Exporter) {
+	case "", ExporterNone, ExporterOTLP, ExporterStdout:
+	case ExporterFile:
+		if c.File == "" {
+			errs = append(errs, errors.New("tracing file is required when tracing exporter is file"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("unknown tracing exporter %q", c.Exporter))
+	}
+	if c.ServiceName == "" {
+		errs = append(errs, errors.New("tracing service name must not be empty"))
+	}
+	if c.SampleRatio < 0 || c.SampleRatio > 1 {
+		errs = append(errs, fmt.Errorf("tracing sample ratio %v is out of range 0-1", c.SampleRatio))
+	}
+	return errors.Join(errs...)
+}
+
+// Setup global propagator'ı (W3C traceparent ve baggage) ve exporter tanımlıysa tracer provider'ı kurar.
+// Dönen fonksiyon kapanışta bekleyen span'leri aktarıp exporter'ı kapatır.
+func Setup(config Config) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	exporter, closer, err := newExporter(config)
+	if err != nil {
+		return nil, err
+	}
+	if exporter == nil {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName(config.ServiceName),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("error creating tracing resource: %v", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+
+	return func(ctx context.Context) error {
+		err := provider.Shutdown(ctx)
+		if closer != nil {
+			err = errors.Join(err, closer.Close())
+		}
+		return err
+	}, nil
+}
+
+// newExporter exporter none ise nil döner. Dosya exporter'ı için dosya kapanışta kapatılmalıdır.
+func newExporter(config Config) (sdktrace.SpanExporter, io.Closer, error) {
+	switch strings.ToLower(config.Exporter) {
+	case ExporterOTLP:
+		var opts []otlptracehttp.Option
+		if config.Endpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpointURL(config.Endpoint))
+		}
+		// Bağlantı ilk aktarımda kurulur; collector'a ulaşılamaması açılışı engellemez
+		exporter, err := otlptracehttp.New(context.Background(), opts...)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error creating otlp trace exporter: %v", err)
+		}
+		return exporter, nil, nil
+	case ExporterStdout:
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+		if err != nil {
+			return nil, nil, fmt.Errorf("error creating stdout trace exporter: %v", err)
+		}
+		return exporter, nil, nil
+	case ExporterFile:
+		file, err := os.OpenFile(config.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error opening trace file: %v", err)
+		}
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(file))
+		if err != nil {
+			file.Close()
+			return nil, nil, fmt.Errorf("error creating file trace exporter: %v", err)
+		}
+		return exporter, file, nil
+	default:
+		return nil, nil, nil
+	}
+}
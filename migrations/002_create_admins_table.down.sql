@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS admins;
+DROP TYPE IF EXISTS admin_status;
+DROP TYPE IF EXISTS admin_role;
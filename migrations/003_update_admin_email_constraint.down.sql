@@ -0,0 +1,3 @@
+-- Partial unique index'i kaldırıp tablo genelinde unique constraint'i geri getir
+DROP INDEX IF EXISTS idx_admins_email;
+ALTER TABLE admins ADD CONSTRAINT admins_email_key UNIQUE (email);
@@ -0,0 +1,11 @@
+-- Postgres enum'dan değer silmeyi desteklemediği için tip yeniden oluşturulur
+UPDATE admins SET status = 'passive' WHERE status = 'pending';
+
+ALTER TYPE admin_status RENAME TO admin_status_old;
+CREATE TYPE admin_status AS ENUM ('active', 'passive');
+
+ALTER TABLE admins ALTER COLUMN status DROP DEFAULT;
+ALTER TABLE admins ALTER COLUMN status TYPE admin_status USING status::text::admin_status;
+ALTER TABLE admins ALTER COLUMN status SET DEFAULT 'active';
+
+DROP TYPE admin_status_old;
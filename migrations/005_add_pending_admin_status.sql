@@ -0,0 +1 @@
+ALTER TYPE admin_status ADD VALUE IF NOT EXISTS 'pending';
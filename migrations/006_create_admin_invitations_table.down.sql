@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS admin_invitations;
+
+ALTER TABLE admins DROP COLUMN IF EXISTS two_factor_enabled;
+ALTER TABLE admins DROP COLUMN IF EXISTS two_factor_secret;
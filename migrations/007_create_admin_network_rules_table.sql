@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS admin_network_rules (
+    id SERIAL PRIMARY KEY,
+    admin_id INTEGER REFERENCES admins(id) ON DELETE CASCADE,
+    cidr CIDR NOT NULL,
+    description VARCHAR(255),
+    created_by INTEGER REFERENCES admins(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_admin_network_rules_admin_id ON admin_network_rules(admin_id);
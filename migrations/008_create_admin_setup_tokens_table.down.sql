@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS admin_setup_tokens;
+
+ALTER TABLE admins DROP COLUMN IF EXISTS must_change_password;
@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS admin_password_histories;
+
+ALTER TABLE admins DROP COLUMN IF EXISTS password_changed_at;
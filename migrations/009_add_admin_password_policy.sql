@@ -0,0 +1,12 @@
+ALTER TABLE admins ADD COLUMN IF NOT EXISTS password_changed_at TIMESTAMP WITH TIME ZONE;
+
+UPDATE admins SET password_changed_at = COALESCE(updated_at, created_at) WHERE password_changed_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS admin_password_histories (
+    id SERIAL PRIMARY KEY,
+    admin_id INTEGER NOT NULL REFERENCES admins(id) ON DELETE CASCADE,
+    password_hash VARCHAR(255) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_admin_password_histories_admin_id ON admin_password_histories(admin_id, created_at DESC);
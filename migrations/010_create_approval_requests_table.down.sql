@@ -0,0 +1,2 @@
+DROP TABLE IF EXISTS approval_requests;
+DROP TYPE IF EXISTS approval_status;
@@ -0,0 +1,30 @@
+DO $$ 
+BEGIN
+    IF NOT EXISTS (SELECT 1 FROM pg_type WHERE typname = 'approval_status') THEN
+        CREATE TYPE approval_status AS ENUM ('pending', 'approved', 'rejected', 'expired', 'failed');
+    END IF;
+END $$;
+
+CREATE TABLE IF NOT EXISTS approval_requests (
+    id SERIAL PRIMARY KEY,
+    action VARCHAR(100) NOT NULL,
+    target_type VARCHAR(50) NOT NULL,
+    target_id INTEGER NOT NULL,
+    payload JSONB,
+    status approval_status NOT NULL DEFAULT 'pending',
+    requested_by INTEGER NOT NULL REFERENCES admins(id),
+    reviewed_by INTEGER REFERENCES admins(id),
+    reviewed_at TIMESTAMP WITH TIME ZONE,
+    review_reason TEXT,
+    executed_at TIMESTAMP WITH TIME ZONE,
+    error TEXT,
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_approval_requests_status ON approval_requests(status);
+
+-- Aynı hedef için aynı anda sadece bir bekleyen istek olabilir
+CREATE UNIQUE INDEX IF NOT EXISTS idx_approval_requests_pending_target
+    ON approval_requests(action, target_type, target_id) WHERE status = 'pending';
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_admins_deleted_at;
+DROP INDEX IF EXISTS idx_users_deleted_at;
+
+ALTER TABLE users DROP COLUMN IF EXISTS deleted_at;
+ALTER TABLE users DROP COLUMN IF EXISTS updated_at;
@@ -0,0 +1,6 @@
+-- Veritabanına ait anahtar/değer bilgileri; örneğin environment=production işareti seed gibi geliştirme araçlarını engeller
+CREATE TABLE IF NOT EXISTS app_metadata (
+    key VARCHAR(100) PRIMARY KEY,
+    value TEXT NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,3 @@
+-- migrate:no-transaction
+DROP INDEX CONCURRENTLY IF EXISTS idx_users_last_login_date;
+DROP INDEX CONCURRENTLY IF EXISTS idx_users_created_at;
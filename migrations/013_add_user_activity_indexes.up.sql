@@ -0,0 +1,5 @@
+-- migrate:no-transaction
+-- Dashboard istatistikleri kayıt ve giriş tarihlerini aralık ile filtreler; büyük tablolarda yazmaları kilitlememek için
+-- index'ler CONCURRENTLY oluşturulur
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_users_created_at ON users(created_at);
+CREATE INDEX CONCURRENTLY IF NOT EXISTS idx_users_last_login_date ON users(last_login_date);
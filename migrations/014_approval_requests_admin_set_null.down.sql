@@ -0,0 +1,10 @@
+-- Oluşturan admin'i silinmiş istekler varsa requested_by tekrar NOT NULL yapılamaz ve geri alma başarısız olur
+ALTER TABLE approval_requests DROP CONSTRAINT IF EXISTS approval_requests_reviewed_by_fkey;
+ALTER TABLE approval_requests ADD CONSTRAINT approval_requests_reviewed_by_fkey
+    FOREIGN KEY (reviewed_by) REFERENCES admins(id);
+
+ALTER TABLE approval_requests DROP CONSTRAINT IF EXISTS approval_requests_requested_by_fkey;
+ALTER TABLE approval_requests ADD CONSTRAINT approval_requests_requested_by_fkey
+    FOREIGN KEY (requested_by) REFERENCES admins(id);
+
+ALTER TABLE approval_requests ALTER COLUMN requested_by SET NOT NULL;
@@ -0,0 +1,10 @@
+-- Onay isteklerini oluşturan veya inceleyen admin kalıcı olarak silinebilsin; istek kaydı audit için kalır
+ALTER TABLE approval_requests ALTER COLUMN requested_by DROP NOT NULL;
+
+ALTER TABLE approval_requests DROP CONSTRAINT IF EXISTS approval_requests_requested_by_fkey;
+ALTER TABLE approval_requests ADD CONSTRAINT approval_requests_requested_by_fkey
+    FOREIGN KEY (requested_by) REFERENCES admins(id) ON DELETE SET NULL;
+
+ALTER TABLE approval_requests DROP CONSTRAINT IF EXISTS approval_requests_reviewed_by_fkey;
+ALTER TABLE approval_requests ADD CONSTRAINT approval_requests_reviewed_by_fkey
+    FOREIGN KEY (reviewed_by) REFERENCES admins(id) ON DELETE SET NULL;
@@ -0,0 +1,11 @@
+-- Eski index aynı hedefte tek bir bekleyen istek kabul eder; fazla olanlar (en eskisi hariç) expired yapılır
+UPDATE approval_requests SET status = 'expired'
+WHERE status = 'pending' AND id NOT IN (
+    SELECT MIN(id) FROM approval_requests WHERE status = 'pending' GROUP BY action, target_type, target_id
+);
+
+DROP INDEX IF EXISTS idx_approval_requests_pending_target;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_approval_requests_pending_target
+    ON approval_requests(action, target_type, target_id) WHERE status = 'pending';
+
+ALTER TABLE approval_requests DROP COLUMN IF EXISTS target_key;
@@ -0,0 +1,7 @@
+-- Toplu silme gibi birden fazla hedefi kapsayan işlemler tek bir target_id ile ifade edilemez. target_key hedef
+-- kümesinin özetini taşır ve bekleyen istek tekilliğine dahil edilir; tek hedefli işlemlerde boştur.
+ALTER TABLE approval_requests ADD COLUMN IF NOT EXISTS target_key VARCHAR(64) NOT NULL DEFAULT '';
+
+DROP INDEX IF EXISTS idx_approval_requests_pending_target;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_approval_requests_pending_target
+    ON approval_requests(action, target_type, target_id, target_key) WHERE status = 'pending';
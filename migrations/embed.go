@@ -0,0 +1,8 @@
+// Package migrations SQL migration dosyalarını binary'ye gömer, böylece uygulama
+// çalışma dizininden bağımsız olarak kendi şemasını taşır.
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS
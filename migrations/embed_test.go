@@ -0,0 +1,51 @@
+package migrations_test
+
+import (
+	"io/fs"
+	"strings"
+	"testing"
+
+	"prototurk/internal/database"
+	"prototurk/migrations"
+)
+
+// Gömülü her .sql dosyası migrator'ın okuduğu bir up veya down dosyası olmalıdır; yanlış adlandırılmış bir dosya
+// sessizce atlanırsa binary eksik bir şema uygular. Yeni biçimdeki her migration geri alınabilir olmalıdır.
+func TestEmbeddedMigrationsArePaired(t *testing.T) {
+	list, err := database.NewMigrator(nil, migrations.FS, database.DefaultMigratorOptions()).Migrations()
+	if err != nil {
+		t.Fatalf("read embedded migrations: %v", err)
+	}
+	if len(list) == 0 {
+		t.Fatal("no embedded migrations")
+	}
+
+	known := make(map[string]bool)
+	for i, migration := range list {
+		if migration.Version != i {
+			t.Errorf("migration %s has version %d, want %d (versions must be consecutive)", migration.Name, migration.Version, i)
+		}
+
+		known[migration.UpFile] = true
+		if migration.HasDown() {
+			known[migration.DownFile] = true
+		}
+
+		if strings.HasSuffix(migration.UpFile, ".up.sql") {
+			want := migration.Name + ".down.sql"
+			if migration.DownFile != want {
+				t.Errorf("migration %s has up file %s but no %s", migration.Name, migration.UpFile, want)
+			}
+		}
+	}
+
+	files, err := fs.Glob(migrations.FS, "*.sql")
+	if err != nil {
+		t.Fatalf("list embedded files: %v", err)
+	}
+	for _, file := range files {
+		if !known[file] {
+			t.Errorf("embedded file %s is not picked up by the migrator", file)
+		}
+	}
+}
@@ -10,6 +10,9 @@ type APIError struct {
 	Code    string      `json:"code"`
 	Message string      `json:"message"`
 	Details interface{} `json:"details,omitempty"`
+	// TraceID hata bildirimlerinde isteği loglarda ve trace'lerde bulmak için kullanılır.
+	// Handler'lar doldurmaz; middleware.TraceResponse isteğin trace ID'sini yanıta ekler.
+	TraceID string `json:"trace_id,omitempty"`
 }
 
 func Success(data interface{}) Response {